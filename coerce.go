@@ -0,0 +1,64 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _durationType = reflect.TypeOf(time.Duration(0))
+
+// coerce converts a string scalar to the numeric or Boolean kind of t. Other
+// nodes are returned unchanged.
+func coerce(node interface{}, t reflect.Type, path []string) (interface{}, error) {
+	s, ok := node.(string)
+	if !ok || t == _durationType {
+		// gopkg.in/yaml.v2 already parses durations from strings.
+		return node, nil
+	}
+	var (
+		coerced interface{}
+		err     error
+	)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		coerced, err = strconv.ParseInt(s, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		coerced, err = strconv.ParseUint(s, 10, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		coerced, err = strconv.ParseFloat(s, t.Bits())
+	case reflect.Bool:
+		coerced, err = strconv.ParseBool(s)
+	default:
+		return node, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf(
+			"can't coerce %q at key %q to %v: %v",
+			s, strings.Join(path, _separator), t, err.(*strconv.NumError).Err,
+		)
+	}
+	return coerced, nil
+}
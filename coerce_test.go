@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerceScalars(t *testing.T) {
+	type server struct {
+		Port    int
+		Weight  float64
+		Debug   bool
+		Workers *uint8
+		Timeout time.Duration
+		Name    string
+	}
+	env := map[string]string{
+		"PORT":  "8080",
+		"DEBUG": "true",
+	}
+	lookup := func(key string) (string, bool) {
+		s, ok := env[key]
+		return s, ok
+	}
+	const src = `
+port: ${PORT}
+weight: "0.5"
+debug: ${DEBUG}
+workers: "4"
+timeout: 5s
+name: 42
+`
+
+	t.Run("disabled", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup))
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		assert.Error(t, p.Get(Root).Populate(&s), "expected strings not to populate numeric fields by default")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup), CoerceScalars())
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		require.NoError(t, p.Get(Root).Populate(&s), "couldn't populate struct")
+		require.NotNil(t, s.Workers, "expected pointer to be allocated")
+		assert.Equal(t, 8080, s.Port, "unexpected int")
+		assert.Equal(t, 0.5, s.Weight, "unexpected float")
+		assert.True(t, s.Debug, "unexpected bool")
+		assert.Equal(t, uint8(4), *s.Workers, "unexpected uint")
+		assert.Equal(t, 5*time.Second, s.Timeout, "unexpected duration")
+		assert.Equal(t, "42", s.Name, "unexpected string")
+
+		var port int
+		require.NoError(t, p.Get("port").Populate(&port), "couldn't populate top-level int")
+		assert.Equal(t, 8080, port, "unexpected top-level int")
+	})
+
+	t.Run("survives WithDefault", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup), CoerceScalars())
+		require.NoError(t, err, "couldn't construct provider")
+		v, err := p.Get("ports").WithDefault([]string{"80", "443"})
+		require.NoError(t, err, "couldn't set default")
+		var ports []int
+		require.NoError(t, v.Populate(&ports), "couldn't populate slice")
+		assert.Equal(t, []int{80, 443}, ports, "unexpected slice")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			desc   string
+			src    string
+			target interface{}
+			msg    string
+		}{
+			{"int", "servers: [{port: 80}, {port: eighty}]", &struct{ Servers []server }{}, `can't coerce "eighty" at key "servers.1.port" to int`},
+			{"bool", "debug: maybe", &server{}, `can't coerce "maybe" at key "debug" to bool`},
+			{"overflow", "workers: '256'", &server{}, `can't coerce "256" at key "workers" to uint8: value out of range`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.desc, func(t *testing.T) {
+				p, err := NewYAML(Source(strings.NewReader(tt.src)), CoerceScalars())
+				require.NoError(t, err, "couldn't construct provider")
+				err = p.Get(Root).Populate(tt.target)
+				require.Error(t, err, "expected coercion to fail")
+				assert.Contains(t, err.Error(), tt.msg, "unexpected error message")
+			})
+		}
+	})
+}
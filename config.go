@@ -22,16 +22,26 @@ package config
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
 	"go.uber.org/config/internal/merge"
 	"go.uber.org/config/internal/unreachable"
+	"go.uber.org/multierr"
 	yaml "gopkg.in/yaml.v2"
 )
 
-const _separator = "."
+const (
+	_separator       = "."
+	_defaultMaxDepth = 1000 // see MaxDepth
+)
 
 //YAML是从一个或多个YAML源读取的提供者。
 //结果提供者行为的许多方面可以通过传递函数选项来改变。
@@ -39,36 +49,117 @@ const _separator = "."
 //通过启用gopkg.in/yaml公司.v2的严格模式。
 //有关详细信息，请参阅关于严格解组的包级文档。
 //填充Go结构时，YAML提供程序正确生成的值
+//
+//构造完成后，YAML提供者是不可变的，可以安全地被多个goroutine并发读取（包括Get、Populate和Value）。
 type YAML struct {
-	name     string
-	raw      [][]byte
-	lookup   LookupFunc // see withDefault
-	contents interface{}
-	strict   bool
-	empty    bool
-}
+	name         string
+	raw          [][]byte
+	origins      []string      // see Explain
+	defaults     []bool        // see Explain
+	lookup       LookupErrFunc // see withDefault
+	contents     interface{}
+	strict       bool
+	empty        bool
+	verbatim     []verbatimValue // see withDefault
+	coerce       bool
+	keyMatch     KeyMatchMode
+	strictExpand bool
+	strictPaths  [][]string              // see strictAt
+	replaceMaps  [][]string              // see withDefault
+	tag          string                  // see StructTag
+	maxDepth     int                     // see withDefault
+	maxSeqLen    int                     // see withDefault
+	sealed       bool                    // see Seal
+	defaultTags  bool                    // see UseDefaultTags
+	envTags      bool                    // see UseEnvTags
+	rejectNulls  bool                    // see RejectNullStructs
+	strictScalar bool                    // see StrictScalars
+	transform    func(string) string     // see TrimStringsFunc
+	registry     *Registry               // see TypeRegistry
+	observer     Observer                // see WithObserver
+	deprecated   map[string]string       // see DeprecateKeys
+	mergeFunc    MergeResolver           // see MergeFunc
+	normalize    func(string) string     // see NormalizeKeys
+	noExpand     []*regexp.Regexp        // see NoExpandPattern
+	preserve     bool                    // see PreserveNumbers
+	numbers      map[string]string       // see restoreNumbers
+	owned        map[string]reflect.Type // see OwnedSections
+	warnings     []string
+	stats        *LoadStats     // see CollectStats
+	closers      []func() error // see Close
 
+	// resolved caches the values found by at, keyed by cacheKey. Providers
+	// are immutable after construction, so entries never need to be
+	// invalidated. Misses aren't cached: they're cheap to re-walk, and
+	// caching them would let lookups of arbitrary keys grow the cache
+	// without bound.
+	resolved *sync.Map
+}
 
 //NewYAML构造一个YAML提供者。
 //有关默认行为的可用调整，请参见各种YAMLOptions。
+//它等价于使用context.Background()调用NewYAMLContext。
 func NewYAML(options ...YAMLOption) (*YAML, error) {
+	return NewYAMLContext(context.Background(), options...)
+}
+
+//NewYAMLContext与NewYAML类似，但在构造期间遵守ctx的取消和截止时间。
+//ctx会传递给ExpandContext提供的查找函数；对于其他查找函数，每次查找前都会检查ctx。
+//如果ctx在构造完成前被取消，NewYAMLContext返回的错误会包装ctx.Err()，可以用errors.Is检查。
+//
+//ctx只用于构造：WithDefault展开默认值中的变量时使用context.Background()。
+func NewYAMLContext(ctx context.Context, options ...YAMLOption) (*YAML, error) {
 	cfg := &config{
-		strict: true,
-		name:   "YAML",
+		strict:   true,
+		name:     "YAML",
+		maxDepth: _defaultMaxDepth,
 	}
 	for _, o := range options {
 		o.apply(cfg)
 	}
+	//缓存包装查找函数本身，因此WithDefault构造的提供者通过ExpandE(y.lookup)共享同一缓存。
+	if cfg.cacheLookups && cfg.lookupCtx != nil {
+		cfg.lookupCtx = cachedLookup(cfg.lookupCtx)
+		cfg.lookup = bindContext(context.Background(), cfg.lookupCtx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't construct provider: %w", err)
+	}
+	sources, err := loadSources(cfg.sources, cfg.maxSourceBytes)
+	cfg.sources = sources
+	cfg.err = multierr.Append(cfg.err, err)
+	if cfg.friendly && cfg.err == nil {
+		cfg.err = lintSources(cfg.sources)
+	}
+	if cfg.includes && cfg.err == nil {
+		cfg.sources, err = resolveIncludes(cfg.sources, cfg)
+		cfg.err = err
+	}
+	if len(cfg.tags) > 0 && cfg.err == nil {
+		cfg.sources, err = resolveTags(cfg.sources, cfg.tags)
+		cfg.err = err
+	}
 
 	if cfg.err != nil {
 		return nil, fmt.Errorf("error applying options: %v", cfg.err)
 	}
+	if cfg.nonEmpty {
+		for i, s := range cfg.sources {
+			if isEmptySource(s.bytes) {
+				return nil, fmt.Errorf("%s is empty", s.describe(i))
+			}
+		}
+	}
 	//有些源不应该扩展环境变量；通过转义内容来保护这些源。
 	//（合并前扩展会重新暴露出许多错误，因此我们不能在合并前选择性地扩展源代码。）
+	//如果没有启用扩展，就不需要转义（否则原始源中的$会被加倍）。
 	sourceBytes := make([][]byte, len(cfg.sources))
+	origins := make([]string, len(cfg.sources))
+	defaults := make([]bool, len(cfg.sources))
 	for i := range cfg.sources {
 		s := cfg.sources[i]
-		if !s.raw {
+		origins[i], defaults[i] = s.describeOrigin(i), s.isDefault
+		if !s.raw || cfg.lookup == nil {
 			sourceBytes[i] = s.bytes
 			continue
 		}
@@ -77,36 +168,237 @@ func NewYAML(options ...YAMLOption) (*YAML, error) {
 
 	//在构造时，经历一个完整的merge-serialize-deserialize循环，以尽早捕获任何重复的键（在严格模式下）。
 	//它还剥离了注释，从而阻止我们尝试环境变量扩展。（接下来我们将展开环境变量。）
-	merged, err := merge.YAML(sourceBytes, cfg.strict)
+	replaceMaps := splitPaths(cfg.replaceMaps)
+	watch := stopwatch{enabled: cfg.collectStats}
+	watch.restart()
+	merged, err := merge.YAML(
+		sourceBytes,
+		cfg.strict,
+		merge.ReplaceMappings(replaceMaps...),
+		merge.MaxDepth(cfg.maxDepth),
+		merge.MaxSequenceLen(cfg.maxSeqLen),
+		merge.MergeFunc(cfg.mergeFunc),
+		merge.NormalizeKeys(cfg.normalizeKeys),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't merge YAML sources: %v", err)
 	}
+	mergeTime := watch.lap()
+
+	//与NoExpandPattern匹配的值也会被转义，使扩展恢复其原样。
+	if len(cfg.noExpand) > 0 && cfg.lookup != nil {
+		merged, err = protectPatterns(merged, cfg.noExpand)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//逐字路径保留获胜源中的原始字符串，并避免环境变量扩展。
+	verbatim := cfg.verbatimValues
+	if len(cfg.verbatim) > 0 {
+		verbatim = snapshotVerbatim(cfg.sources, cfg.verbatim, cfg.strict, cfg.keyMatch)
+	}
+	if len(verbatim) > 0 {
+		merged, err = protectVerbatim(merged, verbatim, cfg.lookup != nil, cfg.keyMatch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.strictExpand && cfg.lookup != nil {
+		if err := checkReferences(merged.Bytes()); err != nil {
+			return nil, err
+		}
+	}
 
 	// Expand environment variables.
-	merged, err = expandVariables(cfg.lookup, merged)
+	var lookup LookupErrFunc
+	if cfg.lookupCtx != nil {
+		lookup = allowlisted(bindContext(ctx, cfg.lookupCtx), cfg.envAllowlist)
+	}
+	merged, err = expandVariables(lookup, merged)
 	if err != nil {
 		return nil, err
 	}
+	expandTime := watch.lap()
+
+	strictPaths := splitPaths(cfg.strictPaths)
 
 	y := &YAML{
-		name:   cfg.name,
-		raw:    sourceBytes,
-		lookup: cfg.lookup,
-		strict: cfg.strict,
+		name:         cfg.name,
+		raw:          sourceBytes,
+		origins:      origins,
+		defaults:     defaults,
+		lookup:       allowlisted(cfg.lookup, cfg.envAllowlist),
+		strict:       cfg.strict,
+		verbatim:     verbatim,
+		coerce:       cfg.coerce,
+		keyMatch:     cfg.keyMatch,
+		strictExpand: cfg.strictExpand,
+		strictPaths:  strictPaths,
+		replaceMaps:  replaceMaps,
+		tag:          cfg.tag,
+		defaultTags:  cfg.defaultTags,
+		envTags:      cfg.envTags,
+		rejectNulls:  cfg.rejectNulls,
+		strictScalar: cfg.strictScalars,
+		transform:    cfg.trimStrings,
+		registry:     cfg.registry,
+		observer:     cfg.observer,
+		deprecated:   cfg.deprecated,
+		mergeFunc:    cfg.mergeFunc,
+		normalize:    cfg.normalizeKeys,
+		noExpand:     cfg.noExpand,
+		preserve:     cfg.exactNumbers,
+		owned:        cfg.owned,
+		maxDepth:     cfg.maxDepth,
+		maxSeqLen:    cfg.maxSeqLen,
+		closers:      cfg.closers,
+		resolved:     &sync.Map{},
 	}
 
+	watch.restart()
+	expanded := merged.Bytes()
 	dec := yaml.NewDecoder(merged)
 	dec.SetStrict(cfg.strict)
 	if err := dec.Decode(&y.contents); err != nil {
+		if err != io.EOF && cfg.lookup != nil {
+			//键中的变量在合并后才展开，因此展开为相同字面值的两个键只能在这里检测到。
+			return nil, fmt.Errorf("couldn't decode merged YAML after expanding variables: %v", err)
+		}
 		if err != io.EOF {
 			return nil, fmt.Errorf("couldn't decode merged YAML: %v", err)
 		}
 		y.empty = true
 	}
+	if cfg.exactNumbers && !y.empty {
+		y.numbers = preciseNumbers(cfg.sources, expanded, y.contents, cfg.normalizeKeys, cfg.keyMatch)
+	}
+	if cfg.collectStats {
+		y.stats = &LoadStats{Merge: mergeTime, Expand: expandTime, Decode: watch.lap()}
+		for i, s := range cfg.sources {
+			y.stats.Sources = append(y.stats.Sources, SourceStats{Name: origins[i], Bytes: len(s.bytes)})
+		}
+	}
+	y.warnings = y.deprecationWarnings()
 
 	return y, nil
 }
 
+// splitPaths splits dotted paths into their segments. Root splits into an
+// empty path.
+func splitPaths(paths []string) [][]string {
+	split := make([][]string, 0, len(paths))
+	for _, p := range paths {
+		if p == Root {
+			split = append(split, nil)
+			continue
+		}
+		split = append(split, strings.Split(p, _separator))
+	}
+	return split
+}
+
+// isEmptySource reports whether a source contains no YAML documents. Sources
+// that fail to decode aren't empty; merging reports those errors.
+func isEmptySource(bs []byte) bool {
+	var contents interface{}
+	return yaml.NewDecoder(bytes.NewReader(bs)).Decode(&contents) == io.EOF
+}
+
+var (
+	_ Provider    = (*YAML)(nil)
+	_ io.Closer   = (*YAML)(nil)
+	_ io.WriterTo = (*YAML)(nil)
+)
+
+//Close释放提供者持有的所有资源（例如文件句柄或监视程序），并返回遇到的所有错误。
+//仅从内存中的源构建的提供者不持有任何资源，因此Close总是返回nil。
+//调用Close后使用提供者（或从中检索的值）的行为是未定义的。
+//通过WithDefault派生的提供者与原始提供者共享资源；只需关闭原始提供者。
+func (y *YAML) Close() error {
+	var err error
+	for _, c := range y.closers {
+		err = multierr.Append(err, c())
+	}
+	return err
+}
+
+//CanonicalBytes以规范形式重新序列化提供者的全部配置，使语义相同的文档得到相同的字节，便于去重、缓存和比较。
+//映射键按确定的顺序排序，标量根据gopkg.in/yaml.v2解码出的Go类型重新编码：
+//例如yes和on都变为true，0x10变为16，带引号与不带引号的等价字符串也归一化。
+//注意，整数和浮点数保持不同（1和1.0不同）。空提供者的规范形式是null。
+func (y *YAML) CanonicalBytes() ([]byte, error) {
+	bs, err := yaml.Marshal(y.contents)
+	if err != nil {
+		//提供者内容是由解编YAML生成的，这是不可能的。
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't marshal config to YAML: %v", err))
+	}
+	return bs, nil
+}
+
+//WriteTo把CanonicalBytes返回的规范形式写入w，并返回写入的字节数，实现了io.WriterTo。
+//提供者是不可变的，因此WriteTo可以与其他读取并发调用，例如在收到SIGUSR1等信号时由处理程序转储有效配置。
+func (y *YAML) WriteTo(w io.Writer) (int64, error) {
+	bs, err := y.CanonicalBytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(bs)
+	return int64(n), err
+}
+
+//Flatten返回配置中的每个叶子值，以点分隔路径为键，序列元素按从零开始的索引编号。例如
+//   foo:
+//     bar: [a, b]
+//     baz: null
+// flattens to
+//   {"foo.bar.0": "a", "foo.bar.1": "b", "foo.baz": nil}
+//显式null叶子会以nil值出现在结果中；空映射和空序列没有叶子，因此不会出现。
+//空提供者返回空映射（而不是nil）；如果整个配置是单个标量，结果以根常量为键。
+//返回的映射归调用者所有，修改它不会影响提供者。
+func (y *YAML) Flatten() map[string]interface{} {
+	leaves := make(map[string]interface{})
+	if !y.empty {
+		flatten(leaves, nil, y.contents)
+	}
+	return leaves
+}
+
+func countLeaves(node interface{}) int {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		count := 0
+		for _, v := range n {
+			count += countLeaves(v)
+		}
+		return count
+	case []interface{}:
+		count := 0
+		for _, v := range n {
+			count += countLeaves(v)
+		}
+		return count
+	default:
+		return 1
+	}
+}
+
+func flatten(leaves map[string]interface{}, path []string, node interface{}) {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			flatten(leaves, extend(path, fmt.Sprint(k)), v)
+		}
+	case []interface{}:
+		for i, v := range n {
+			flatten(leaves, extend(path, strconv.Itoa(i)), v)
+		}
+	default:
+		leaves[strings.Join(path, _separator)] = n
+	}
+}
+
 //Name返回提供程序的名称。默认为“YAML”。
 func (y *YAML) Name() string {
 	return y.name
@@ -121,14 +413,31 @@ func (y *YAML) Name() string {
 //   baz: hello
 //
 //要获取包含整个配置的值，请使用根常量作为键。
+//
+//对于序列，路径段是从零开始的索引：如果foo.bar是一个序列，Get("foo.bar.0")返回它的第一个元素。
+//
+//路径段与非字符串映射键（例如整数键）的匹配方式由KeyMatch选项控制。
 func (y *YAML) Get(key string) Value {
 	return y.get(strings.Split(key, _separator))
 }
 
+//GetOrError与Get类似，但如果键处没有任何配置，则返回*NotFoundError（同时指明提供者和键）。
+//这样读取必需的键只需一次调用，无需再检查HasValue。显式设置为null的键被视为存在。
+func (y *YAML) GetOrError(key string) (Value, error) {
+	v := y.Get(key)
+	if _, ok := y.at(v.path); !ok {
+		return Value{}, &NotFoundError{Provider: y.name, Key: key}
+	}
+	return v, nil
+}
+
 func (y *YAML) get(path []string) Value {
 	if len(path) == 1 && path[0] == Root {
 		path = nil
 	}
+	if y.observer != nil {
+		y.observer.OnGet(strings.Join(path, _separator))
+	}
 	return Value{
 		path:     path,
 		provider: y,
@@ -143,42 +452,123 @@ func (y *YAML) at(path []string) (interface{}, bool) {
 		return nil, false
 	}
 
-	cur := y.contents
+	key := cacheKey(path)
+	if val, ok := y.resolved.Load(key); ok {
+		return val, true
+	}
+	val, found := y.walk(path)
+	if found {
+		y.resolved.Store(key, val)
+	}
+	return val, found
+}
+
+// cacheKey identifies a path in the resolved cache. Most paths are cached by
+// their dotted form, but paths from GetPointer may have segments containing
+// periods, so paths whose dotted form could be ambiguous are quoted instead.
+func cacheKey(path []string) string {
+	for _, segment := range path {
+		if segment == "" || strings.ContainsAny(segment, _separator+"\x00") {
+			return fmt.Sprintf("\x00%q", path)
+		}
+	}
+	return strings.Join(path, _separator)
+}
+
+// walk resolves a path against the provider's contents without consulting
+// the cache.
+func (y *YAML) walk(path []string) (interface{}, bool) {
+	return lookup(y.contents, path, y.keyMatch)
+}
+
+// lookup resolves a path against unmarshaled YAML.
+func lookup(root interface{}, path []string, mode KeyMatchMode) (interface{}, bool) {
+	cur := root
 	for _, segment := range path {
+		//序列按从零开始的十进制索引访问。
+		if seq, ok := cur.([]interface{}); ok {
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(seq) {
+				return nil, false
+			}
+			cur = seq[idx]
+			continue
+		}
 		//转换为映射类型。如果这失败了，那么我们就得到了一条不以序列或标量终止的路径。
 		m, ok := cur.(map[interface{}]interface{})
 		if !ok {
 			return nil, false
 		}
-
-		//尝试将段解析为字符串，然后为可比较的键解组路径段。
-		//毕竟，YAML标量类型不仅仅是字符串（boolean、integer等）。我们希望使用字符串形式来解析不明确的路径。
-		if _, ok := m[segment]; !ok {
-			var key interface{}
-			if err := yaml.Unmarshal([]byte(segment), &key); err != nil {
-				return nil, false
-			}
-			if !merge.IsScalar(key) {
-				return nil, false
-			}
-			if _, ok := m[key]; !ok {
-				return nil, false
-			}
-			cur = m[key]
-		} else {
-			cur = m[segment]
+		key, ok := mode.resolve(m, segment)
+		if !ok {
+			return nil, false
 		}
+		cur = m[key]
 	}
 	return cur, true
 }
 
 func (y *YAML) populate(path []string, i interface{}) error {
+	return y.populateRecording(path, i, nil)
+}
+
+//populateRecording与populate相同，但如果applied不为nil，还会把从默认标签填充的字段路径追加到applied中。
+func (y *YAML) populateRecording(path []string, i interface{}, applied *[]string) error {
 	val, ok := y.at(path)
+	target := reflect.ValueOf(i)
 	if !ok {
+		if y.defaultTags {
+			if err := applyDefaults(nil, target, path, applied); err != nil {
+				return err
+			}
+		}
+		if y.envTags {
+			return applyEnvTags(target)
+		}
 		return nil
 	}
+	return y.decode(val, path, i, applied)
+}
+
+//decode将path处的配置节点val填充到i中，applied的含义参见populateRecording。
+func (y *YAML) decode(val interface{}, path []string, i interface{}, applied *[]string) error {
+	target := reflect.ValueOf(i)
+	if target.Kind() == reflect.Ptr && !target.IsNil() {
+		if err := y.checkStrictPaths(path, target.Type().Elem()); err != nil {
+			return err
+		}
+		var err error
+		if val, err = y.reshape(val, target.Type().Elem(), path); err != nil {
+			return err
+		}
+		if y.rejectNulls {
+			if err := rejectNulls(val, target.Type().Elem(), path); err != nil {
+				return err
+			}
+		}
+		if y.strictScalar {
+			if err := rejectNonStrings(val, target.Type().Elem(), path); err != nil {
+				return err
+			}
+		}
+		if y.transform != nil {
+			val = transformStrings(val, y.transform)
+		}
+		//严格模式下，gopkg.in/yaml.v2会拒绝目标映射中已存在的键，因此先删除配置将要设置的键，使映射按文档所述深度合并。
+		if err := visit(val, target.Elem(), dropConfiguredKeys); err != nil {
+			return err
+		}
+	}
+	//标记联合由resolveTypes单独填充。
+	encoded := val
+	if y.registry != nil && target.Kind() == reflect.Ptr && !target.IsNil() {
+		var err error
+		if encoded, err = y.registry.hide(val, target.Type().Elem()); err != nil {
+			return err
+		}
+	}
 	buf := &bytes.Buffer{}
-	if err := yaml.NewEncoder(buf).Encode(val); err != nil {
+	if err := yaml.NewEncoder(buf).Encode(encoded); err != nil {
 		//提供者内容是由解编YAML生成的，这是不可能的。
 		err := fmt.Errorf(
 			"couldn't marshal config at key %s to YAML: %v",
@@ -187,13 +577,58 @@ func (y *YAML) populate(path []string, i interface{}) error {
 		)
 		return unreachable.Wrap(err)
 	}
+	if y.numbers != nil {
+		var err error
+		if buf, err = y.restoreNumbers(buf, path); err != nil {
+			return err
+		}
+	}
+	strict := y.strictAt(path)
 	dec := yaml.NewDecoder(buf)
-	dec.SetStrict(y.strict)
+	dec.SetStrict(strict)
 	//解码永远不能返回EOF，因为编码任何值都保证生成非空YAML。
-	return dec.Decode(i)
+	if err := dec.Decode(i); err != nil {
+		if target.Kind() == reflect.Ptr && !target.IsNil() {
+			//gopkg.in/yaml.v2的错误只包含重新序列化后YAML的行号，因此逐个解码子节点以找到出错的键或序列索引。
+			return locateErrors(encoded, target.Type().Elem(), path, strict, err)
+		}
+		return err
+	}
+	if target.Kind() == reflect.Ptr && !target.IsNil() {
+		if err := visit(val, target.Elem(), markNulls); err != nil {
+			return err
+		}
+	}
+	if y.defaultTags {
+		if err := applyDefaults(val, target, path, applied); err != nil {
+			return err
+		}
+	}
+	if y.envTags {
+		if err := applyEnvTags(target); err != nil {
+			return err
+		}
+	}
+	if y.registry != nil && target.Kind() == reflect.Ptr && !target.IsNil() {
+		if err := y.resolveTypes(val, target.Elem(), path); err != nil {
+			return err
+		}
+	}
+	if n, ok := i.(Normalizer); ok {
+		n.Normalize()
+	}
+	if target.Kind() == reflect.Ptr && !target.IsNil() {
+		if err := validateTags(target.Elem(), path, y.tag); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (y *YAML) withDefault(d interface{}) (*YAML, error) {
+	if y.sealed {
+		return nil, fmt.Errorf("can't apply defaults to provider %s: provider is sealed", y.name)
+	}
 	rawDefault := &bytes.Buffer{}
 	if err := yaml.NewEncoder(rawDefault).Encode(d); err != nil {
 		return nil, fmt.Errorf("can't marshal default to YAML: %v", err)
@@ -205,19 +640,94 @@ func (y *YAML) withDefault(d interface{}) (*YAML, error) {
 //在这种情况下，合并所有源的结果是非空的。但是，显式空源应该覆盖withDefault提供的所有数据。
 //为了正确地处理这个问题，我们必须使用新的默认值作为最低优先级的源，并重新合并原始源。
 	opts := []YAMLOption{
-		Name(y.name),
-		Expand(y.lookup),
-		Source(rawDefault),
+		defaultSource(rawDefault.Bytes()),
 		//raw包含原始源，并对RawSources进行转义soappendsources不会对其进行双重扩展。
-		appendSources(y.raw),
+		appendSources(y.raw, y.origins, y.defaults),
+		verbatimValues(y.verbatim),
+	}
+	return NewYAML(append(opts, y.options()...)...)
+}
+
+//options返回重新构造提供者时保留其行为所需的选项，不包括源。
+func (y *YAML) options() []YAMLOption {
+	opts := []YAMLOption{
+		Name(y.name),
+		ExpandE(y.lookup),
 	}
 	if !y.strict {
 		opts = append(opts, Permissive())
 	}
-	return NewYAML(opts...)
+	if y.coerce {
+		opts = append(opts, CoerceScalars())
+	}
+	if y.strictExpand {
+		opts = append(opts, StrictExpand())
+	}
+	for _, p := range y.strictPaths {
+		opts = append(opts, StrictPaths(strings.Join(p, _separator)))
+	}
+	if y.tag != "" {
+		opts = append(opts, StructTag(y.tag))
+	}
+	if y.defaultTags {
+		opts = append(opts, UseDefaultTags())
+	}
+	if y.envTags {
+		opts = append(opts, UseEnvTags())
+	}
+	if y.rejectNulls {
+		opts = append(opts, RejectNullStructs())
+	}
+	if y.strictScalar {
+		opts = append(opts, StrictScalars())
+	}
+	if y.transform != nil {
+		opts = append(opts, TrimStringsFunc(y.transform))
+	}
+	if y.registry != nil {
+		opts = append(opts, TypeRegistry(y.registry))
+	}
+	if y.observer != nil {
+		opts = append(opts, WithObserver(y.observer))
+	}
+	if len(y.deprecated) > 0 {
+		opts = append(opts, DeprecateKeys(y.deprecated))
+	}
+	if y.mergeFunc != nil {
+		opts = append(opts, MergeFunc(y.mergeFunc))
+	}
+	if y.normalize != nil {
+		opts = append(opts, NormalizeKeys(y.normalize))
+	}
+	if y.preserve {
+		opts = append(opts, PreserveNumbers())
+	}
+	for _, re := range y.noExpand {
+		opts = append(opts, NoExpandPattern(re))
+	}
+	if len(y.owned) > 0 {
+		opts = append(opts, ownedTypes(y.owned))
+	}
+	for _, p := range y.replaceMaps {
+		opts = append(opts, ReplaceMaps(strings.Join(p, _separator)))
+	}
+	if y.keyMatch != PreferString {
+		opts = append(opts, KeyMatch(y.keyMatch))
+	}
+	if y.maxDepth != _defaultMaxDepth {
+		opts = append(opts, MaxDepth(y.maxDepth))
+	}
+	if y.maxSeqLen > 0 {
+		opts = append(opts, MaxSequenceLen(y.maxSeqLen))
+	}
+	if y.stats != nil {
+		opts = append(opts, CollectStats())
+	}
+	return opts
 }
 
 //值是提供者配置的子集。
+//值总是由*YAML支持，因此不能为自定义Provider实现构造值；测试中需要轻量级提供者时，请使用configtest.StaticProvider。
 type Value struct {
 	path     []string
 	provider *YAML
@@ -259,10 +769,187 @@ func (v Value) Source() string {
 
 //Populate将值解组到目标结构中，与json.Unmarshal文件或者yaml.解组. 
 //当用一些已经设置的字段填充结构时，数据将按照包级别中的描述进行深度合并文档。
+//
+//实现encoding.TextUnmarshaler的目标（例如net.IP）会收到标量的文本形式。
+//注意，合并后非字符串标量会被重新序列化为规范形式（例如0x10变为16），然后才传给UnmarshalText；如需保留原文，请给值加引号。
+//
+//目标映射的键可以是任何可比较的标量类型（例如map[int]float64或map[bool]string）；无法转换为键类型的键会导致指明完整路径的错误。
+//
+//如果目标实现了Normalizer，Populate会在成功解码后调用其Normalize方法。
 func (v Value) Populate(target interface{}) error {
-	return v.provider.populate(v.path, target)
+	err := v.provider.populate(v.path, target)
+	if o := v.provider.observer; o != nil {
+		o.OnPopulate(strings.Join(v.path, _separator), err)
+	}
+	return err
+}
+
+//PopulatePlan报告Populate会从配置中设置目标的哪些字段，但不修改目标。
+//它返回有配置值支持的字段的点分隔完整路径（按字段顺序），包括显式设置为null的键。
+//嵌套结构（包括指向结构的指针）会被逐字段展开；映射、序列以及实现自身解组的类型作为一个整体报告。
+//字段的键遵循与Populate相同的yaml标签规则，未标记的嵌入结构的字段也会被提升。
+func (v Value) PopulatePlan(target interface{}) ([]string, error) {
+	paths, err := v.provider.plan(v.path, target)
+	if err != nil {
+		return nil, err
+	}
+	joined := make([]string, len(paths))
+	for i, p := range paths {
+		joined[i] = strings.Join(p, _separator)
+	}
+	return joined, nil
+}
+
+//PopulateFields只填充目标结构中由点分隔字段路径指定的字段，其余字段保持不变，适合对正在使用的结构进行局部热更新。
+//字段路径按与Populate相同的yaml标签规则映射到结构字段（包括提升的嵌入字段），每个段都必须命名一个结构字段。
+//每个字段从配置中对应的键填充，就像对该键调用Populate一样；配置中不存在的键会保留字段的当前值。
+//只有在所有字段都成功填充后才会对其赋值，因此某个字段出错时不会留下其他字段的部分更新。
+func (v Value) PopulateFields(target interface{}, fieldPaths ...string) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || derefType(rv.Type()).Kind() != reflect.Struct {
+		return fmt.Errorf("can't populate fields of %T: target must be a non-nil pointer to a struct", target)
+	}
+	type update struct {
+		path  []string
+		value reflect.Value
+	}
+	updates := make([]update, 0, len(fieldPaths))
+	for _, fp := range fieldPaths {
+		if fp == Root {
+			return errors.New("can't populate fields: field path must not be empty")
+		}
+		path := strings.Split(fp, _separator)
+		t, err := structFieldType(rv.Type(), path, v.provider.tag)
+		if err != nil {
+			return fmt.Errorf("can't populate field %q: %v", fp, err)
+		}
+		child := v.Get(fp)
+		if _, ok := v.provider.at(child.path); !ok {
+			continue
+		}
+		//在副本上填充，以便出错时不留下部分更新。
+		cp := reflect.New(t)
+		if cur, ok := fieldAt(rv, path, v.provider.tag, false /* alloc */); ok {
+			cp.Elem().Set(cur)
+		}
+		if err := child.Populate(cp.Interface()); err != nil {
+			return err
+		}
+		updates = append(updates, update{path, cp.Elem()})
+	}
+	for _, u := range updates {
+		f, _ := fieldAt(rv, u.path, v.provider.tag, true /* alloc */)
+		f.Set(u.value)
+	}
+	return nil
+}
+
+//MergePopulate按顺序把多个值的配置深度合并后填充到目标中，以便由分散在不同路径（甚至不同提供者）的多个部分组成一个结构。
+//合并遵循包文档中描述的规则，就像这些值是按顺序提供的多个源一样：后面的值覆盖前面的值设置的标量，映射被逐键深度合并，序列被替换，显式null覆盖之前的配置。
+//不存在的值会被跳过。合并的结果与Populate一样深度合并到目标中，并且使用最后一个值的提供者的设置（例如严格模式和StructTag）填充，
+//因此range和oneof标签只验证合并后的结果，Normalize也只调用一次。错误中的键相对于目标。
+func MergePopulate(target interface{}, values ...Value) error {
+	if len(values) == 0 {
+		return nil
+	}
+	last := values[len(values)-1].provider
+	sources := make([][]byte, 0, len(values))
+	for _, v := range values {
+		val, ok := v.provider.at(v.path)
+		if !ok {
+			continue
+		}
+		bs, err := yaml.Marshal(val)
+		if err != nil {
+			//提供者内容是由解编YAML生成的，这是不可能的。
+			return unreachable.Wrap(fmt.Errorf("couldn't marshal config at key %s to YAML: %v", strings.Join(v.path, _separator), err))
+		}
+		sources = append(sources, bs)
+	}
+	err := mergePopulate(last, sources, target)
+	for _, v := range values {
+		if o := v.provider.observer; o != nil {
+			o.OnPopulate(strings.Join(v.path, _separator), err)
+		}
+	}
+	return err
+}
+
+func mergePopulate(y *YAML, sources [][]byte, target interface{}) error {
+	if len(sources) == 0 {
+		if y.defaultTags {
+			if err := applyDefaults(nil, reflect.ValueOf(target), nil, nil); err != nil {
+				return err
+			}
+		}
+		if y.envTags {
+			return applyEnvTags(reflect.ValueOf(target))
+		}
+		return nil
+	}
+	merged, err := merge.YAML(sources, y.strict, merge.MaxDepth(y.maxDepth), merge.MaxSequenceLen(y.maxSeqLen))
+	if err != nil {
+		return fmt.Errorf("couldn't merge values: %v", err)
+	}
+	var contents interface{}
+	if err := yaml.NewDecoder(merged).Decode(&contents); err != nil && err != io.EOF {
+		return unreachable.Wrap(fmt.Errorf("couldn't decode merged values: %v", err))
+	}
+	return y.decode(contents, nil, target, nil)
+}
+
+//LoadInto把推荐的用法打包为一次调用：目标结构预先填入默认值，然后用options构造提供者，并把全部配置填充到目标中。
+//与Populate一样，配置逐字段深度合并到默认值上：配置设置的字段覆盖默认值，其余字段保持不变，映射按键合并，序列被替换。
+//提供者默认启用严格模式，因此未知键等错误会像NewYAML和Populate一样返回；填充完成后提供者会被关闭。
+//目标必须是非nil指针。
+func LoadInto(target interface{}, options ...YAMLOption) error {
+	if v := reflect.ValueOf(target); v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("can't load configuration into %T: target must be a non-nil pointer", target)
+	}
+	p, err := NewYAML(options...)
+	if err != nil {
+		return err
+	}
+	err = p.Get(Root).Populate(target)
+	return multierr.Append(err, p.Close())
+}
+
+//PopulateCount与Populate相同，但还返回填充过程中使用的不同配置叶子值的数量，便于报告某个配置部分有多少由数据支持。
+//它统计PopulatePlan报告的每个路径下的叶子（与Flatten相同，显式null也算一个叶子）。
+//注意，计数反映的是源数据是否存在，而不是填充后的值是否与零值不同：例如显式配置的0也会被计入。
+func (v Value) PopulateCount(target interface{}) (int, error) {
+	paths, err := v.provider.plan(v.path, target)
+	if err != nil {
+		return 0, err
+	}
+	if err := v.Populate(target); err != nil {
+		return 0, err
+	}
+	leaves := 0
+	for _, p := range paths {
+		val, _ := v.provider.at(p)
+		leaves += countLeaves(val)
+	}
+	return leaves, nil
 }
 
+//PopulateAll与Populate类似，但会尽力收集所有错误，而不是在第一个错误处停止，这样一次运行就能找出配置中的所有问题。
+//如果Populate失败且目标是指向结构的指针，PopulateAll会逐个字段地重新填充目标：每个字段在自己的键处单独解码，并收集每个字段的错误（错误指明完整的键）。
+//字段的format、default和env标签仍然生效，与Populate相同。
+//嵌入结构的字段以及严格模式下没有对应字段的键会一起检查。成功解码的字段仍会被设置。
+//对其他目标，它返回Populate报告的错误。没有错误时返回nil。
+//
+//这是尽力而为的：出错时不会校验整个结构的validate标签，也不会调用Normalize，深层嵌套结构内部的校验可能只报告部分错误。
+func (v Value) PopulateAll(target interface{}) []error {
+	err := v.Populate(target)
+	if err == nil {
+		return nil
+	}
+	if errs := v.provider.populateEach(v.path, target); len(errs) > 0 {
+		return errs
+	}
+	return multierr.Errors(err)
+}
 
 //进一步深入到配置中，提取更深入的嵌套值。
 //提供的路径按句点拆分，并且每个段都被视为嵌套的映射键。例如，如果当前值包含YAML配置
@@ -286,11 +973,23 @@ func (v Value) Get(path string) Value {
 //它不区分在提供程序构造期间提供的配置和默认应用的配置。
 //如果该值已显式设置为nil，则HasValue为true。
 //不赞成：这个函数没有什么价值，而且常常令人困惑。与其检查值是否有任何可用的配置，不如用适当的默认值和零值填充结构。
+//如果确实需要检查键是否存在，请使用IsSet，它还能区分显式null。
 func (v Value) HasValue() bool {
 	_, ok := v.provider.at(v.path)
 	return ok
 }
 
+//IsSet报告此键是否存在于配置中，以及（如果存在）其值是否为显式null（在YAML中写为~或null）。
+//它在一次调用中区分三种状态：缺失（false, false）、显式null（true, true）和具体值（true, false）。
+//与HasValue一样，它不区分构造期间提供的配置和默认应用的配置。对于存在性检查，请使用IsSet而不是已弃用的HasValue。
+func (v Value) IsSet() (set bool, isNull bool) {
+	val, ok := v.provider.at(v.path)
+	if !ok {
+		return false, false
+	}
+	return true, val == nil
+}
+
 func (v Value) String() string {
 	return fmt.Sprint(v.Value())
 }
@@ -309,8 +1008,18 @@ func (v Value) Value() interface{} {
 	return i
 }
 
+//Seal返回提供者的密封副本：对其（或从中获取的值）调用WithDefault或ApplyMergePatch会返回错误，而不是产生令人意外的合并结果，从而明确“配置在此处已最终确定”的边界。
+//密封副本与原始提供者共享配置和资源（参见Close），原始提供者不受影响。
+//本包没有Clone方法；从原始源重新构造或通过Snapshot.Load恢复的提供者不会被密封。
+func (y *YAML) Seal() *YAML {
+	sealed := *y
+	sealed.sealed = true
+	return &sealed
+}
+
 //WithDefault为值提供默认配置。默认值被序列化为YAML，然后使用包级文档中描述的合并逻辑将现有配置源深度合并到其中。
 //ni请注意，应用默认值需要重新扩展环境变量，如果在提供程序构造之后环境发生更改，则可能会产生意外的结果。
+//使用CacheExpansion构造的提供者会沿用构造时的查找结果。
 
 //已弃用：WithDefault的深度合并行为非常复杂，尤其是在多次应用时。相反，创建一个Go结构，直接在结构上设置任何默认值，然后调用Populate。
 func (v Value) WithDefault(d interface{}) (Value, error) {
@@ -21,7 +21,13 @@
 package config
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -185,3 +191,512 @@ func TestSingleProviders(t *testing.T) {
 		run(t, p, err)
 	})
 }
+
+func TestPathCache(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader("a: {1: {b: c}}")))
+	require.NoError(t, err, "couldn't construct provider")
+
+	for i := 0; i < 2; i++ {
+		assert.True(t, p.Get("a.1.b").HasValue(), "expected value at deep path")
+		assert.Equal(t, "c", p.Get("a.1.b").Value(), "unexpected value at deep path")
+		assert.False(t, p.Get("a.2.b").HasValue(), "expected no value at missing path")
+	}
+
+	_, ok := p.resolved.Load("a.1.b")
+	assert.True(t, ok, "expected resolved path to be cached")
+	_, ok = p.resolved.Load("a.2.b")
+	assert.False(t, ok, "expected missing path not to be cached")
+
+	defaulted, err := p.Get("a").WithDefault(map[int]interface{}{2: map[string]string{"b": "d"}})
+	require.NoError(t, err, "couldn't set default")
+	assert.Equal(t, "d", defaulted.Get("2.b").Value(), "defaulted provider shouldn't share cache")
+	assert.False(t, p.Get("a.2.b").HasValue(), "original provider shouldn't see defaults")
+}
+
+func BenchmarkDeepPathLookup(b *testing.B) {
+	p, err := NewYAML(Source(strings.NewReader("a: {1: {2: {3: {b: c}}}}")))
+	require.NoError(b, err, "couldn't construct provider")
+	path := []string{"a", "1", "2", "3", "b"}
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p.at(path)
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			p.walk(path)
+		}
+	})
+}
+
+func TestConcurrentReads(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader("foo: {bar: [1, 2, 3], baz: quux}")))
+	require.NoError(t, err, "couldn't construct provider")
+
+	const goroutines = 16
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v := p.Get("foo")
+				assert.True(t, v.Get("baz").HasValue(), "expected value")
+				assert.Equal(t, "quux", v.Get("baz").String(), "unexpected value")
+				var cfg struct {
+					Bar []int
+					Baz string
+				}
+				assert.NoError(t, v.Populate(&cfg), "couldn't populate struct")
+				assert.Equal(t, []int{1, 2, 3}, cfg.Bar, "unexpected populated value")
+				_, err := v.WithDefault(map[string]string{"qux": "corge"})
+				assert.NoError(t, err, "couldn't set default")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkConcurrentReads(b *testing.B) {
+	p, err := NewYAML(Source(strings.NewReader("foo: {bar: {baz: quux}}")))
+	require.NoError(b, err, "couldn't construct provider")
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			var s string
+			if err := p.Get("foo.bar.baz").Populate(&s); err != nil {
+				b.Fatalf("couldn't populate string: %v", err)
+			}
+		}
+	})
+}
+
+// uuid is a minimal UUID implementation that only supports text
+// unmarshaling.
+type uuid [16]byte
+
+func (u *uuid) UnmarshalText(text []byte) error {
+	s := strings.Replace(string(text), "-", "", -1)
+	if len(s) != 2*len(u) {
+		return errors.New("UUIDs must have 32 hex digits")
+	}
+	_, err := hex.Decode(u[:], []byte(s))
+	return err
+}
+
+func TestPopulateTextUnmarshalers(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader(`
+net:
+  ip: 10.0.0.1
+  ips: ["::1", 192.168.0.1]
+  hosts:
+    db: 10.0.0.2
+ids:
+  id: 123e4567-e89b-12d3-a456-426614174000
+  bad_id: 12
+`)))
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("net.IP", func(t *testing.T) {
+		var cfg struct {
+			IP    net.IP
+			IPs   []net.IP
+			Hosts map[string]*net.IP
+		}
+		require.NoError(t, p.Get("net").Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, net.ParseIP("10.0.0.1"), cfg.IP, "unexpected IP")
+		assert.Equal(t, []net.IP{net.ParseIP("::1"), net.ParseIP("192.168.0.1")}, cfg.IPs, "unexpected IPs")
+		require.Contains(t, cfg.Hosts, "db", "missing map entry")
+		assert.Equal(t, net.ParseIP("10.0.0.2"), *cfg.Hosts["db"], "unexpected IP in map")
+
+		var ip net.IP
+		require.NoError(t, p.Get("net.ip").Populate(&ip), "couldn't populate top-level IP")
+		assert.Equal(t, net.ParseIP("10.0.0.1"), ip, "unexpected top-level IP")
+	})
+
+	t.Run("UUID", func(t *testing.T) {
+		var id uuid
+		require.NoError(t, p.Get("ids.id").Populate(&id), "couldn't populate UUID")
+		assert.Equal(t, "123e4567e89b12d3a456426614174000", hex.EncodeToString(id[:]), "unexpected UUID")
+
+		var cfg struct {
+			ID    uuid
+			BadID *uuid `yaml:"bad_id"`
+		}
+		err := p.Get("ids").Populate(&cfg)
+		require.Error(t, err, "expected UnmarshalText error")
+		assert.Contains(t, err.Error(), "32 hex digits", "unexpected error message")
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Run("in-memory", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("foo: bar")))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.NoError(t, p.Close(), "closing an in-memory provider should succeed")
+	})
+
+	t.Run("registered cleanup", func(t *testing.T) {
+		var closed []string
+		closer := func(name string, err error) YAMLOption {
+			return onClose(func() error {
+				closed = append(closed, name)
+				return err
+			})
+		}
+		p, err := NewYAML(
+			Source(strings.NewReader("foo: bar")),
+			closer("first", nil),
+			closer("second", errors.New("second failed")),
+			closer("third", errors.New("third failed")),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+
+		v, err := p.Get(Root).WithDefault(map[string]string{"baz": "quux"})
+		require.NoError(t, err, "couldn't set default")
+		assert.NoError(t, v.provider.Close(), "derived providers shouldn't own resources")
+		assert.Empty(t, closed, "closing a derived provider shouldn't run cleanup")
+
+		err = p.Close()
+		require.Error(t, err, "expected errors from cleanup")
+		assert.Contains(t, err.Error(), "second failed", "missing first error")
+		assert.Contains(t, err.Error(), "third failed", "missing second error")
+		assert.Equal(t, []string{"first", "second", "third"}, closed, "expected all cleanup to run in order")
+	})
+}
+
+func TestCanonicalBytes(t *testing.T) {
+	canonical := func(t testing.TB, opts ...YAMLOption) string {
+		p, err := NewYAML(opts...)
+		require.NoError(t, err, "couldn't construct provider")
+		bs, err := p.CanonicalBytes()
+		require.NoError(t, err, "couldn't canonicalize config")
+		return string(bs)
+	}
+
+	t.Run("equivalent documents", func(t *testing.T) {
+		a := canonical(t, Source(strings.NewReader(`
+zone: us-east
+enabled: yes
+limits:
+  memory: 0x10
+  cpu: 2
+tags: ["a", 'b']
+`)))
+		b := canonical(t, Source(strings.NewReader(`
+limits: {cpu: 2, memory: 16}
+tags: [a, b]
+enabled: true
+zone: "us-east"
+`)))
+		assert.Equal(t, a, b, "expected equivalent documents to canonicalize identically")
+		assert.Equal(t, "enabled: true\nlimits:\n  cpu: 2\n  memory: 16\ntags:\n- a\n- b\nzone: us-east\n", a, "unexpected canonical form")
+	})
+
+	t.Run("merged sources", func(t *testing.T) {
+		merged := canonical(t, Source(strings.NewReader("a: 1\nb: 2")), Source(strings.NewReader("b: 3")))
+		single := canonical(t, Source(strings.NewReader("b: 3\na: 1")))
+		assert.Equal(t, single, merged, "expected canonical form to reflect merged config")
+	})
+
+	t.Run("different documents", func(t *testing.T) {
+		assert.NotEqual(
+			t,
+			canonical(t, Source(strings.NewReader("port: 1"))),
+			canonical(t, Source(strings.NewReader("port: '1'"))),
+			"integers and strings should remain distinct",
+		)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "null\n", canonical(t, Source(strings.NewReader(""))), "unexpected canonical form of empty provider")
+	})
+}
+
+func TestWriteTo(t *testing.T) {
+	p, err := NewYAML(
+		Source(strings.NewReader("b: {d: on, c: 0x10}\na: [1, '1']")),
+		Source(strings.NewReader("b: {e: ~}")),
+	)
+	require.NoError(t, err, "couldn't construct provider")
+	canonical, err := p.CanonicalBytes()
+	require.NoError(t, err, "couldn't get canonical bytes")
+
+	var buf bytes.Buffer
+	n, err := p.WriteTo(&buf)
+	require.NoError(t, err, "couldn't write config")
+	assert.Equal(t, int64(len(canonical)), n, "unexpected byte count")
+	assert.Equal(t, string(canonical), buf.String(), "expected output to match canonical form")
+	assert.Equal(t, "a:\n- 1\n- \"1\"\nb:\n  c: 16\n  d: true\n  e: null\n", buf.String(), "unexpected output")
+
+	t.Run("io.WriterTo", func(t *testing.T) {
+		var wt io.WriterTo = p
+		var copied bytes.Buffer
+		_, err := wt.WriteTo(&copied)
+		require.NoError(t, err, "couldn't write config")
+		assert.Equal(t, string(canonical), copied.String(), "expected identical output on repeated writes")
+	})
+
+	t.Run("writer errors", func(t *testing.T) {
+		_, err := p.WriteTo(failingWriter{})
+		assert.Error(t, err, "expected writer error to be returned")
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestFlatten(t *testing.T) {
+	flatten := func(t testing.TB, src string) map[string]interface{} {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		return p.Flatten()
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{
+			"name":               "svc",
+			"db.port":            5432,
+			"db.replicas.0":      "a",
+			"db.replicas.1":      "b",
+			"routes.0.path":      "/",
+			"routes.0.methods.0": "GET",
+			"routes.1.path":      "/admin",
+			"timeout":            nil,
+			"ports.80":           "http",
+		}, flatten(t, `
+name: svc
+db:
+  port: 5432
+  replicas: [a, b]
+routes:
+  - path: /
+    methods: [GET]
+  - path: /admin
+timeout: null
+ports:
+  80: http
+empty_map: {}
+empty_seq: []
+`), "unexpected flattened config")
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{Root: "foo"}, flatten(t, "foo"), "unexpected flattened scalar")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		leaves := flatten(t, "")
+		assert.NotNil(t, leaves, "expected non-nil map")
+		assert.Empty(t, leaves, "expected no leaves")
+	})
+}
+
+func TestGetOrError(t *testing.T) {
+	p, err := NewYAML(Name("test"), Source(strings.NewReader("foo: {bar: baz}\nnull_key: ~")))
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("present", func(t *testing.T) {
+		v, err := p.GetOrError("foo.bar")
+		require.NoError(t, err, "unexpected error for present key")
+		assert.Equal(t, "baz", v.Value(), "unexpected value")
+
+		v, err = p.GetOrError("null_key")
+		require.NoError(t, err, "explicit nulls should count as present")
+		assert.Nil(t, v.Value(), "unexpected value")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := p.GetOrError("foo.quux")
+		require.Error(t, err, "expected error for missing key")
+		assert.Equal(t, `no configuration at key "foo.quux" in provider test`, err.Error(), "unexpected error message")
+
+		var nf *NotFoundError
+		require.True(t, errors.As(err, &nf), "expected a *NotFoundError")
+		assert.Equal(t, &NotFoundError{Provider: "test", Key: "foo.quux"}, nf, "unexpected error fields")
+	})
+
+	t.Run("empty provider", func(t *testing.T) {
+		empty, err := NewYAML(Source(strings.NewReader("")))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = empty.GetOrError(Root)
+		assert.Error(t, err, "expected error for empty provider")
+	})
+}
+
+func TestReplaceMaps(t *testing.T) {
+	const (
+		base     = "labels: {team: core, tier: 1}\nlimits: {cpu: 1, memory: 2}"
+		override = "labels: {team: edge}\nlimits: {cpu: 4}"
+	)
+	provider := func(t testing.TB, opts ...YAMLOption) *YAML {
+		opts = append([]YAMLOption{
+			Source(strings.NewReader(base)),
+			Source(strings.NewReader(override)),
+		}, opts...)
+		p, err := NewYAML(opts...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("deep merge", func(t *testing.T) {
+		p := provider(t)
+		assert.Equal(t, map[interface{}]interface{}{"team": "edge", "tier": 1}, p.Get("labels").Value(), "expected labels to be deep-merged")
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		p := provider(t, ReplaceMaps("labels"))
+		assert.Equal(t, map[interface{}]interface{}{"team": "edge"}, p.Get("labels").Value(), "expected labels to be replaced")
+		assert.Equal(t, map[interface{}]interface{}{"cpu": 4, "memory": 2}, p.Get("limits").Value(), "expected limits to be deep-merged")
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		p := provider(t, ReplaceMaps("labels"))
+		withDefault, err := p.Get(Root).WithDefault(map[string]interface{}{
+			"labels": map[string]interface{}{"env": "prod"},
+		})
+		require.NoError(t, err, "couldn't add default")
+		assert.Equal(t, map[interface{}]interface{}{"team": "edge"}, withDefault.Get("labels").Value(), "expected configured labels to replace defaults")
+	})
+}
+
+func TestSeal(t *testing.T) {
+	p, err := NewYAML(Name("test"), Source(strings.NewReader("foo: {bar: baz}")))
+	require.NoError(t, err, "couldn't construct provider")
+	sealed := p.Seal()
+
+	t.Run("rejects defaults", func(t *testing.T) {
+		_, err := sealed.Get("foo").WithDefault(map[string]string{"quux": "default"})
+		require.Error(t, err, "expected sealed provider to reject defaults")
+		assert.Contains(t, err.Error(), "provider test: provider is sealed", "unexpected error message")
+
+		_, err = sealed.withDefault(nil)
+		assert.Error(t, err, "expected sealed provider to reject defaults")
+	})
+
+	t.Run("reads unchanged", func(t *testing.T) {
+		assert.Equal(t, "baz", sealed.Get("foo.bar").Value(), "unexpected value")
+		assert.Equal(t, p.Name(), sealed.Name(), "unexpected name")
+		var cfg struct{ Foo struct{ Bar string } }
+		require.NoError(t, sealed.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, "baz", cfg.Foo.Bar, "unexpected populated value")
+	})
+
+	t.Run("original unaffected", func(t *testing.T) {
+		v, err := p.Get("foo").WithDefault(map[string]string{"quux": "default"})
+		require.NoError(t, err, "expected original provider to accept defaults")
+		assert.Equal(t, "default", v.Get("quux").Value(), "unexpected default")
+	})
+
+	t.Run("snapshots aren't sealed", func(t *testing.T) {
+		loaded, err := sealed.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		_, err = loaded.Get("foo").WithDefault(map[string]string{"quux": "default"})
+		assert.NoError(t, err, "expected loaded provider to accept defaults")
+	})
+}
+
+func TestEqual(t *testing.T) {
+	provider := func(t testing.TB, srcs ...string) *YAML {
+		opts := []YAMLOption{}
+		for _, src := range srcs {
+			opts = append(opts, Source(strings.NewReader(src)))
+		}
+		p, err := NewYAML(opts...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+	assertEqual := func(t testing.TB, expect bool, a, b *YAML, msg string) {
+		eq, err := a.Equal(b)
+		require.NoError(t, err, "couldn't compare providers")
+		assert.Equal(t, expect, eq, msg)
+		eq, err = b.Equal(a)
+		require.NoError(t, err, "couldn't compare providers")
+		assert.Equal(t, expect, eq, "expected comparison to be symmetric: %s", msg)
+	}
+
+	t.Run("differently written", func(t *testing.T) {
+		assertEqual(t, true,
+			provider(t, "enabled: yes\nlimits: {memory: 0x10, cpu: 2}\nname: 'api'"),
+			provider(t, "name: api\nlimits:\n  cpu: 2\n  memory: 16\nenabled: true"),
+			"expected equivalent configuration to be equal",
+		)
+		assertEqual(t, true,
+			provider(t, "a: 1\nb: 2", "b: 3"),
+			provider(t, "b: 3\na: 1"),
+			"expected merged configuration to be compared",
+		)
+	})
+
+	t.Run("different", func(t *testing.T) {
+		assertEqual(t, false, provider(t, "a: 1"), provider(t, "a: 2"), "expected different values to differ")
+		assertEqual(t, false, provider(t, "a: 1"), provider(t, "a: '1'"), "expected different types to differ")
+		assertEqual(t, false, provider(t, "a: [1, 2]"), provider(t, "a: [2, 1]"), "expected sequence order to matter")
+		assertEqual(t, false, provider(t, "a: 1"), provider(t, "a: 1\nb: ~"), "expected extra keys to differ")
+	})
+
+	t.Run("empty providers", func(t *testing.T) {
+		assertEqual(t, true, provider(t), provider(t, "# just a comment"), "expected empty providers to be equal")
+		assertEqual(t, false, provider(t), provider(t, "~"), "expected empty and null providers to differ")
+		assertEqual(t, true, provider(t, "~"), provider(t, "null"), "expected null providers to be equal")
+		assertEqual(t, false, provider(t), provider(t, "a: 1"), "expected empty and non-empty providers to differ")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var none *YAML
+		eq, err := none.Equal(nil)
+		require.NoError(t, err, "couldn't compare providers")
+		assert.True(t, eq, "expected nil providers to be equal")
+		assertEqual(t, false, none, provider(t, "a: 1"), "expected nil and non-nil providers to differ")
+	})
+}
+
+func TestValueIsSet(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader("a: {b: 1, c: ~, d: null}\nlist: [~, x]")))
+	require.NoError(t, err, "couldn't construct provider")
+
+	tests := []struct {
+		path   string
+		set    bool
+		isNull bool
+	}{
+		{"a.b", true, false},
+		{"a", true, false},
+		{"a.c", true, true},
+		{"a.d", true, true},
+		{"list.0", true, true},
+		{"list.1", true, false},
+		{"a.missing", false, false},
+		{"a.b.deeper", false, false},
+		{"list.2", false, false},
+	}
+	for _, tt := range tests {
+		set, isNull := p.Get(tt.path).IsSet()
+		assert.Equal(t, tt.set, set, "unexpected set for %q", tt.path)
+		assert.Equal(t, tt.isNull, isNull, "unexpected isNull for %q", tt.path)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		v, err := p.Get("a").WithDefault(map[string]interface{}{"e": 2, "c": 3})
+		require.NoError(t, err, "couldn't apply defaults")
+		set, isNull := v.Get("e").IsSet()
+		assert.True(t, set, "expected defaults to be set")
+		assert.False(t, isNull, "expected default to be a concrete value")
+		set, isNull = v.Get("c").IsSet()
+		assert.True(t, set && isNull, "expected explicit null to override default")
+	})
+
+	t.Run("empty provider", func(t *testing.T) {
+		empty, err := NewYAML(Source(strings.NewReader("")))
+		require.NoError(t, err, "couldn't construct provider")
+		set, isNull := empty.Get(Root).IsSet()
+		assert.False(t, set, "expected nothing to be set in an empty provider")
+		assert.False(t, isNull, "expected absent root not to be null")
+	})
+}
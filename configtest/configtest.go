@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package configtest provides utilities for testing code that depends on
+// configuration providers.
+package configtest
+
+import (
+	"fmt"
+
+	"go.uber.org/config"
+)
+
+// StaticProvider returns a provider backed by an in-memory Go data structure,
+// which is convenient for tests of code that accepts a config.Provider. The
+// data is serialized to YAML and loaded with config.NewYAML, so it's merged,
+// expanded, and populated exactly as production configuration would be. The
+// provider is strict and has the supplied name.
+//
+// Since the provider's values must be config.Values, there's no lighter-weight
+// mock: a config.Value is always backed by a *config.YAML. If the data can't
+// be represented as YAML, StaticProvider panics.
+func StaticProvider(name string, data interface{}) config.Provider {
+	p, err := config.NewYAML(config.StaticNamed(name, data))
+	if err != nil {
+		panic(fmt.Sprintf("configtest: can't construct static provider %q: %v", name, err))
+	}
+	return p
+}
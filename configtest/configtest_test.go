@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package configtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/config"
+)
+
+func TestStaticProvider(t *testing.T) {
+	var p config.Provider = StaticProvider("test", map[string]interface{}{
+		"server": map[string]interface{}{"port": 8080},
+	})
+	assert.Equal(t, "test", p.Name(), "unexpected provider name")
+
+	v := p.Get("server")
+	assert.Equal(t, "test", v.Source(), "unexpected value source")
+
+	var cfg struct{ Port int }
+	require.NoError(t, v.Populate(&cfg), "couldn't populate struct")
+	assert.Equal(t, 8080, cfg.Port, "unexpected port")
+
+	scoped := config.NewScopedProvider("server", p)
+	assert.Equal(t, 8080, scoped.Get("port").Value(), "unexpected value from scoped provider")
+}
+
+func TestStaticProviderPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		StaticProvider("bad", func() {})
+	}, "expected unrepresentable data to panic")
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeprecateKeys reports the use of deprecated configuration without failing,
+// which supports gradual migrations from old keys to new ones. The supplied
+// map associates dotted paths with guidance, for example
+//   DeprecateKeys(map[string]string{
+//     "server.addr": "use server.host and server.port instead",
+//   })
+// After merging and expanding all sources, NewYAML records a warning for each
+// deprecated path that's present in the configuration, even if it's
+// explicitly null. See YAML.Warnings. Multiple DeprecateKeys options are
+// combined; if they mention the same path, later guidance wins.
+func DeprecateKeys(keys map[string]string) YAMLOption {
+	return optionFunc(func(c *config) {
+		if c.deprecated == nil {
+			c.deprecated = make(map[string]string, len(keys))
+		}
+		for k, v := range keys {
+			c.deprecated[k] = v
+		}
+	})
+}
+
+// Warnings returns a message for each deprecated key present in the
+// provider's configuration, ordered by key. Providers without deprecated keys
+// (see DeprecateKeys) never have warnings. Providers created with WithDefault
+// check the combined configuration, so they also warn about deprecated keys
+// set only by the defaults.
+func (y *YAML) Warnings() []string {
+	if len(y.warnings) == 0 {
+		return nil
+	}
+	return append([]string(nil), y.warnings...)
+}
+
+func (y *YAML) deprecationWarnings() []string {
+	if y.empty || len(y.deprecated) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(y.deprecated))
+	for k := range y.deprecated {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var warnings []string
+	for _, k := range keys {
+		if _, ok := y.walk(strings.Split(k, _separator)); ok {
+			warnings = append(warnings, fmt.Sprintf("key %q is deprecated: %s", k, y.deprecated[k]))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecateKeys(t *testing.T) {
+	deprecated := DeprecateKeys(map[string]string{
+		"server.addr":  "use server.host and server.port instead",
+		"legacy":       "remove it",
+		"routes.0.url": "use routes.N.target instead",
+		"absent.key":   "you won't see this",
+	})
+
+	t.Run("present keys", func(t *testing.T) {
+		src := "server: {addr: ':80'}\nlegacy: null\nroutes: [{url: /}]"
+		p, err := NewYAML(Source(strings.NewReader(src)), deprecated)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, []string{
+			`key "legacy" is deprecated: remove it`,
+			`key "routes.0.url" is deprecated: use routes.N.target instead`,
+			`key "server.addr" is deprecated: use server.host and server.port instead`,
+		}, p.Warnings(), "unexpected warnings")
+	})
+
+	t.Run("absent keys", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("server: {host: example.com}")), deprecated)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Empty(t, p.Warnings(), "expected no warnings without deprecated keys")
+
+		empty, err := NewYAML(deprecated)
+		require.NoError(t, err, "couldn't construct empty provider")
+		assert.Empty(t, empty.Warnings(), "expected no warnings for an empty provider")
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("server: {host: example.com}")), deprecated)
+		require.NoError(t, err, "couldn't construct provider")
+		d, err := p.withDefault(map[string]string{"legacy": "yes"})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.Equal(t, []string{`key "legacy" is deprecated: remove it`}, d.Warnings(), "expected defaults to be checked")
+	})
+}
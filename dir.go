@@ -0,0 +1,183 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A DirOption customizes Dir.
+type DirOption interface {
+	apply(*dirOptions)
+}
+
+type dirOptionFunc func(*dirOptions)
+
+func (f dirOptionFunc) apply(d *dirOptions) { f(d) }
+
+type dirOptions struct {
+	recursive bool
+	fileKeys  bool
+}
+
+// IncludeSubdirs makes Dir read files in subdirectories too. Files are
+// ordered by their path relative to the directory, with each directory's
+// contents in place of its name, so dir/a/b.yaml takes priority over
+// dir/a.yaml.
+func IncludeSubdirs() DirOption {
+	return dirOptionFunc(func(d *dirOptions) {
+		d.recursive = true
+	})
+}
+
+// FileKeys makes Dir wrap each file's contents in a top-level key named
+// after the file, as Kubernetes does when it projects a ConfigMap or Secret
+// into a volume. For example, a directory holding the files port
+// (containing 8080) and tls.yaml (containing {enabled: true}) contributes
+//   port: 8080
+//   tls.yaml: {enabled: true}
+// With IncludeSubdirs, subdirectories become nested mappings. File contents
+// are still parsed as YAML, so files with multiple lines of plain text (like
+// certificates) should use a YAML block scalar.
+//
+// Since Get splits keys on dots, keys for file names with extensions (like
+// tls.yaml above) can only be reached by populating a struct field tagged
+// with the full name or a map.
+func FileKeys() DirOption {
+	return dirOptionFunc(func(d *dirOptions) {
+		d.fileKeys = true
+	})
+}
+
+// Dir reads every file in a directory, in sorted name order, and adds each as
+// a source of YAML configuration, so files whose names sort later take
+// priority. Subdirectories are skipped unless the IncludeSubdirs option is
+// supplied. Hidden files and directories (those whose names begin with a
+// dot) are always skipped, which ignores the ..data and timestamped
+// directories Kubernetes creates in mounted volumes; symbolic links to files
+// are followed. Like File, Dir resolves a relative path against the directory
+// set by BaseDir. Priority, merge, and expansion logic are otherwise
+// identical to File.
+func Dir(path string, opts ...DirOption) YAMLOption {
+	var d dirOptions
+	for _, o := range opts {
+		o.apply(&d)
+	}
+	return optionFunc(func(c *config) {
+		path := c.resolvePath(path)
+		c.sources = append(c.sources, source{name: path, load: func(limit int64) ([]source, error) {
+			return d.read(path, limit)
+		}})
+	})
+}
+
+func (d dirOptions) read(root string, limit int64) ([]source, error) {
+	var sources []source
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			if !entry.IsDir() {
+				return fmt.Errorf("%s is not a directory", root)
+			}
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if d.recursive {
+				return nil
+			}
+			return filepath.SkipDir
+		}
+		if entry.Type()&fs.ModeSymlink != 0 {
+			// Follow links to files, but not to directories.
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+		}
+		all, err := readFile(path, limit)
+		if errors.Is(err, errSourceTooLarge) {
+			return fmt.Errorf("source %q is larger than the maximum of %d bytes", path, limit)
+		}
+		if err != nil {
+			return err
+		}
+		if d.fileKeys {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			all = wrapKeys(strings.Split(filepath.ToSlash(rel), "/"), all)
+		}
+		sources = append(sources, source{bytes: all, name: path, file: path})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// wrapKeys nests YAML contents under the supplied keys by indenting them, so
+// that the merge (rather than this package) parses the contents and reports
+// any errors, including duplicate keys in strict mode.
+func wrapKeys(keys []string, contents []byte) []byte {
+	// A leading document marker can't be indented.
+	if bytes.HasPrefix(contents, []byte("---\n")) {
+		contents = contents[len("---\n"):]
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		key, err := yaml.Marshal(keys[i])
+		if err != nil {
+			panic(unreachable.Wrap(fmt.Errorf("couldn't marshal key %q to YAML: %v", keys[i], err)).Error())
+		}
+		var buf bytes.Buffer
+		buf.Write(bytes.TrimRight(key, "\n"))
+		buf.WriteString(":\n")
+		for _, line := range bytes.SplitAfter(contents, []byte("\n")) {
+			if len(bytes.TrimRight(line, "\r\n")) > 0 {
+				buf.WriteString("  ")
+			}
+			buf.Write(line)
+		}
+		contents = buf.Bytes()
+	}
+	return contents
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDir(t testing.TB, files map[string]string) string {
+	dir, err := ioutil.TempDir("" /* dir */, "test-dir" /* prefix */)
+	require.NoError(t, err, "couldn't create temporary directory")
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755), "couldn't create directory for %s", name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644), "couldn't write %s", name)
+	}
+	return dir
+}
+
+func TestDir(t *testing.T) {
+	files := map[string]string{
+		"10-base.yaml":          "name: base\nport: 80\ntls: {enabled: false}",
+		"20-override.yaml":      "---\nport: 8080\n",
+		"30-empty.yaml":         "# nothing here\n",
+		".hidden.yaml":          "name: hidden",
+		"..data/10-base.yaml":   "name: data",
+		"sub/40-nested.yaml":    "name: nested",
+		"sub/zz/deeper.yaml":    "tls: {enabled: true}",
+		"sub/.secret/skip.yaml": "name: secret",
+	}
+
+	t.Run("flat merge", func(t *testing.T) {
+		p, err := NewYAML(Dir(writeDir(t, files)))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, map[interface{}]interface{}{
+			"name": "base",
+			"port": 8080,
+			"tls":  map[interface{}]interface{}{"enabled": false},
+		}, p.Get(Root).Value(), "expected later files to override earlier ones")
+	})
+
+	t.Run("subdirectories", func(t *testing.T) {
+		p, err := NewYAML(Dir(writeDir(t, files), IncludeSubdirs()))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "nested", p.Get("name").Value(), "expected subdirectory files to be merged")
+		assert.Equal(t, true, p.Get("tls.enabled").Value(), "expected nested subdirectories to be merged")
+	})
+
+	t.Run("file keys", func(t *testing.T) {
+		dir := writeDir(t, map[string]string{
+			"port":      "8080\n",
+			"name":      "api",
+			"tls.yaml":  "---\nenabled: true\nciphers:\n  - a\n  - b\n",
+			"cert":      "|\n  line one\n  line two\n",
+			"empty":     "",
+			"..data/x":  "ignored",
+			"sub/inner": "value",
+		})
+		p, err := NewYAML(Dir(dir, FileKeys()))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("port").Value(), "unexpected scalar file")
+		assert.Equal(t, "api", p.Get("name").Value(), "unexpected file without trailing newline")
+		root := p.Get(Root).Value().(map[interface{}]interface{})
+		assert.Equal(t, map[interface{}]interface{}{
+			"enabled": true,
+			"ciphers": []interface{}{"a", "b"},
+		}, root["tls.yaml"], "unexpected YAML file")
+		assert.Equal(t, "line one\nline two\n", p.Get("cert").Value(), "unexpected block scalar")
+		assert.True(t, p.Get("empty").HasValue(), "expected empty file to set a null")
+		assert.False(t, p.Get("sub").HasValue(), "expected subdirectories to be skipped")
+
+		p, err = NewYAML(Dir(dir, FileKeys(), IncludeSubdirs()))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "value", p.Get("sub.inner").Value(), "expected subdirectories to nest")
+	})
+
+	t.Run("file keys merge with other sources", func(t *testing.T) {
+		dir := writeDir(t, map[string]string{"port": "8080"})
+		p, err := NewYAML(Source(strings.NewReader("port: 80\nname: base")), Dir(dir, FileKeys()))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("port").Value(), "expected directory to take priority")
+		assert.Equal(t, "base", p.Get("name").Value(), "expected other keys to remain")
+	})
+
+	t.Run("symlinks", func(t *testing.T) {
+		// Mimic the layout of a Kubernetes ConfigMap volume.
+		dir := writeDir(t, map[string]string{"..2021_01_01/port": "8080"})
+		require.NoError(t, os.Symlink("..2021_01_01", filepath.Join(dir, "..data")), "couldn't link data directory")
+		require.NoError(t, os.Symlink(filepath.Join("..data", "port"), filepath.Join(dir, "port")), "couldn't link file")
+		p, err := NewYAML(Dir(dir, FileKeys(), IncludeSubdirs()))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, map[interface{}]interface{}{"port": 8080}, p.Get(Root).Value(), "unexpected contents")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		dir := writeDir(t, map[string]string{"dup": "a: 1\na: 2"})
+		_, err := NewYAML(Dir(dir, FileKeys()))
+		assert.Error(t, err, "expected duplicate keys to fail in strict mode")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewYAML(Dir(filepath.Join(writeDir(t, nil), "missing")))
+		assert.Error(t, err, "expected missing directory to fail")
+
+		dir := writeDir(t, map[string]string{"file.yaml": "foo: bar"})
+		_, err = NewYAML(Dir(filepath.Join(dir, "file.yaml")))
+		require.Error(t, err, "expected file to fail")
+		assert.Contains(t, err.Error(), "is not a directory", "unexpected error message")
+
+		_, err = NewYAML(Dir(dir), MaxSourceBytes(4))
+		require.Error(t, err, "expected oversized file to fail")
+		assert.Contains(t, err.Error(), "file.yaml\" is larger than the maximum of 4 bytes", "unexpected error message")
+	})
+}
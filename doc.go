@@ -74,6 +74,16 @@
 //	  baz: quux     # from override.yaml
 //	  foos: [3, 4]  # from override.yaml
 //
+// To replace the mappings at particular keys instead of deep-merging them, use
+// the ReplaceMaps option.
+//
+// Populating Go structs uses the same rules. Populating a struct or map that
+// already has some fields or keys set deep-merges configuration into it, but
+// populating a slice that already has elements replaces them: the slice is
+// reallocated with one zero-valued element per configured element. To
+// supply defaults for individual elements of a sequence, populate the
+// sequence first and then fill in any zero-valued fields.
+//
 // In all cases, explicit nils (represented in YAML with a tilde) override any
 // pre-existing configuration. For example,
 //   # base.yaml
@@ -85,6 +95,84 @@
 //   # merged output
 //   foo: ~
 //
+// Merging doesn't depend on how sources were written. Since JSON is a
+// subset of YAML, JSON files can be supplied with Source or File, and Go
+// values supplied with Static are serialized to YAML; sources in any mix of
+// these forms merge exactly as if all had been written in YAML. Numbers are
+// normalized the same way regardless of format: integers become ints (or
+// int64s or uint64s, if they're too large), and because merging re-serializes
+// configuration, floating-point numbers without a fractional part, like 2.0,
+// become integers too. Both kinds of number populate float fields, but code
+// inspecting interface{} values should expect either type. JSON embedded in
+// strings (see Embedded Structs) is normalized identically. Numbers that a
+// float64 can't represent exactly are rounded, unless the PreserveNumbers
+// option is used.
+//
+// Optional Values
+//
+// Populating a pointer-to-pointer field (for example, a **int) distinguishes
+// all three states a key can be in. For a struct with a Timeout **int field,
+//   {}          # absent: Timeout is nil
+//   timeout: ~  # explicit null: Timeout is a non-nil pointer to a nil *int
+//   timeout: 5  # present: Timeout is a pointer to a pointer to 5
+// Single pointers can't tell an absent key from an explicit null; both leave
+// the pointer nil.
+//
+// Embedded Structs
+//
+// When populating a struct, the fields of untagged embedded structs (and
+// pointers to structs) are promoted, just as they are in Go: given
+//   type Common struct { Host string }
+//   type Server struct {
+//     Common
+//     Port int
+//   }
+// a Server populates from {host: foo, port: 80}. For backward compatibility,
+// the nested form {common: {host: foo}, port: 80} also works. Strict mode
+// treats promoted fields as known keys.
+//
+// Because of limitations in gopkg.in/yaml.v2, embedded structs whose types
+// are unexported can only be populated if they're tagged with
+// `yaml:",inline"`, and pointers can't be inlined at all.
+//
+// Some configuration embeds JSON in a string. Fields tagged format:"json",
+// like
+//   type Server struct {
+//     Extra Extra `yaml:"extra" format:"json"`
+//   }
+// populate from such strings as if the JSON had been written as YAML, so
+// both {extra: '{"retries": 3}'} and {extra: {retries: 3}} work. Malformed
+// JSON is an error that names the field's key.
+//
+// Similarly, fields tagged format:"tuple" let tuple-like structs be written
+// as sequences, whose elements populate the struct's fields in declaration
+// order. Given
+//   type Point struct{ X, Y, Z int }
+//   type Shape struct {
+//     Origin Point   `yaml:"origin" format:"tuple"`
+//     Path   []Point `yaml:"path" format:"tuple"`
+//   }
+// both {origin: [1, 2, 3]} and {origin: {x: 1, "y": 2, z: 3}} populate Origin,
+// and each element of Path may be written either way. A sequence must have
+// exactly one element per field. Because a struct's field order is rarely
+// part of its contract, this is opt-in: untagged struct fields never
+// populate from sequences.
+//
+// Populate also checks two validation tags. The range tag bounds numeric
+// fields, inclusively, and either bound may be omitted; the oneof tag lists,
+// separated by spaces, the values a string field may take:
+//   type Server struct {
+//     Port    int    `yaml:"port" range:"1,65535"`
+//     Workers int    `yaml:"workers" range:"1,"`
+//     Level   string `yaml:"level" oneof:"debug info warn error"`
+//   }
+// On pointer, slice, and array fields, the tags apply to each value held.
+// Validation runs on the populated value, after defaults are applied and
+// after the target's Normalize method (see Normalizer), so fields the
+// configuration doesn't set must also be valid. Violations are
+// errors that name the offending key, for example
+//   invalid value for key "server.port": 70000 is greater than the maximum of 65535
+//
 // Strict Unmarshalling
 //
 // By default, the NewYAML constructor enables gopkg.in/yaml.v2's strict
@@ -93,7 +181,8 @@
 // providers throw errors if keys are duplicated in the same configuration
 // source, all keys aren't used when populating a struct, or a merge
 // encounters incompatible data types. This behavior can be disabled with the
-// Permissive option.
+// Permissive option. To check for unused keys only in the parts of the
+// configuration you control, use the StrictPaths option.
 //
 // To maintain backward compatibility, all other constructors default to
 // permissive unmarshalling.
@@ -117,7 +206,17 @@
 //   foo: yes  # before merge
 //   foo: true # after merge
 //
-// Quoting special-cased strings prevents this surprising behavior.
+// Quoting special-cased strings prevents this surprising behavior. To catch
+// unquoted Booleans and numbers that populate string fields, use the
+// StrictScalars option.
+//
+// Concurrency
+//
+// Providers are immutable once constructed, so a single provider (and any
+// Values retrieved from it) may be read from many goroutines at once. Methods
+// that appear to modify configuration, like WithDefault, return new providers
+// rather than mutating existing ones. To swap populated configuration
+// atomically when it's reloaded, use a Holder.
 //
 // Deprecated APIs
 //
@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// An EnvFileOption customizes ExpandFromFile.
+type EnvFileOption interface {
+	apply(*envFile)
+}
+
+type envFileOptionFunc func(*envFile)
+
+func (f envFileOptionFunc) apply(e *envFile) { f(e) }
+
+// PreferOS makes variables in the process environment take precedence over
+// those defined in the file passed to ExpandFromFile. By default, the file
+// takes precedence.
+func PreferOS() EnvFileOption {
+	return envFileOptionFunc(func(e *envFile) {
+		e.preferOS = true
+	})
+}
+
+type envFile struct {
+	vars     map[string]string
+	preferOS bool
+	lookupOS LookupFunc
+}
+
+func (e *envFile) lookup(key string) (string, bool) {
+	first, second := e.lookupFile, e.lookupOS
+	if e.preferOS {
+		first, second = second, first
+	}
+	if val, ok := first(key); ok {
+		return val, true
+	}
+	return second(key)
+}
+
+func (e *envFile) lookupFile(key string) (string, bool) {
+	val, ok := e.vars[key]
+	return val, ok
+}
+
+// ExpandFromFile enables variable expansion (see Expand), looking variables up
+// in a dotenv file layered over the process environment. The file's
+// variables are never added to the process environment, which keeps secrets
+// out of child processes. By default, variables defined in the file take
+// precedence; use PreferOS to reverse this.
+//
+// Each non-blank line of the file defines one variable:
+//   # Comments and blank lines are ignored.
+//   export REGION=us-east-1  # the export prefix is optional
+//   GREETING="hello\nworld"  # double quotes support Go escape sequences
+//   PATTERN='$literal'       # single quotes preserve their contents
+// Variable names must adhere to shell naming rules. Values can't span
+// multiple lines. Malformed lines make provider construction fail with an
+// error naming the file and line.
+func ExpandFromFile(name string, opts ...EnvFileOption) YAMLOption {
+	contents, err := ioutil.ReadFile(name)
+	if err != nil {
+		return failed(err)
+	}
+	vars, err := parseEnvFile(contents)
+	if err != nil {
+		return failed(fmt.Errorf("can't parse %s: %v", name, err))
+	}
+	e := &envFile{vars: vars, lookupOS: os.LookupEnv}
+	for _, o := range opts {
+		o.apply(e)
+	}
+	return Expand(e.lookup)
+}
+
+func parseEnvFile(contents []byte) (map[string]string, error) {
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		eq := strings.IndexByte(line, '=')
+		if eq == -1 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if !isShellName(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", n, key)
+		}
+		val, err := parseEnvValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		vars[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+func parseEnvValue(val string) (string, error) {
+	if val == "" {
+		return "", nil
+	}
+	switch quote := val[0]; quote {
+	case '"', '\'':
+		end := closingQuote(val)
+		if end == -1 {
+			return "", fmt.Errorf("missing closing %c", quote)
+		}
+		if rest := strings.TrimSpace(val[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %q after closing %c", rest, quote)
+		}
+		if quote == '\'' {
+			return val[1:end], nil
+		}
+		unquoted, err := strconv.Unquote(val[:end+1])
+		if err != nil {
+			return "", fmt.Errorf("invalid double-quoted value: %v", err)
+		}
+		return unquoted, nil
+	}
+	if comment := strings.Index(val, " #"); comment != -1 {
+		val = strings.TrimSpace(val[:comment])
+	}
+	return val, nil
+}
+
+// closingQuote finds the quote that closes a value beginning with a quote.
+// Within double quotes, quotes may be escaped with a backslash.
+func closingQuote(val string) int {
+	quote := val[0]
+	for i := 1; i < len(val); i++ {
+		switch {
+		case quote == '"' && val[i] == '\\':
+			i++
+		case val[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func isShellName(s string) bool {
+	return s != "" && isShellNameFirstChar(s[0]) && bytesIndexCFunc([]byte(s), isShellNameChar) == -1
+}
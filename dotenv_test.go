@@ -0,0 +1,134 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("" /* dir */, "test-expand-from-file" /* prefix */)
+	require.NoError(t, err, "couldn't create temporary directory")
+	defer os.RemoveAll(dir)
+
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0644), "couldn't write %s", name)
+		return path
+	}
+
+	const (
+		shared = "CONFIG_TEST_DOTENV_SHARED"
+		osOnly = "CONFIG_TEST_DOTENV_OS_ONLY"
+	)
+	for k, v := range map[string]string{shared: "from-os", osOnly: "os-value"} {
+		require.NoError(t, os.Setenv(k, v), "couldn't set %s", k)
+		defer os.Unsetenv(k)
+	}
+
+	env := write(".env", strings.Join([]string{
+		"# comment",
+		"",
+		shared + "=from-file",
+		"export FILE_ONLY=file-value  # trailing comment",
+		`QUOTED="hello\u0021"`,
+		`LITERAL='$NOT_EXPANDED' # "quoted" comment`,
+		"EMPTY=",
+	}, "\n"))
+	const src = `
+shared: ${` + shared + `}
+os_only: ${` + osOnly + `}
+file_only: ${FILE_ONLY}
+quoted: ${QUOTED}
+literal: ${LITERAL}
+`
+
+	t.Run("file overrides OS", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), ExpandFromFile(env))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "from-file", p.Get("shared").Value(), "expected file to take precedence")
+		assert.Equal(t, "os-value", p.Get("os_only").Value(), "expected fallback to OS")
+		assert.Equal(t, "file-value", p.Get("file_only").Value(), "unexpected value with export prefix")
+		assert.Equal(t, "hello!", p.Get("quoted").Value(), "unexpected double-quoted value")
+		assert.Equal(t, "$NOT_EXPANDED", p.Get("literal").Value(), "unexpected single-quoted value")
+	})
+
+	t.Run("OS overrides file", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), ExpandFromFile(env, PreferOS()))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "from-os", p.Get("shared").Value(), "expected OS to take precedence")
+		assert.Equal(t, "file-value", p.Get("file_only").Value(), "expected fallback to file")
+	})
+
+	t.Run("process environment untouched", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader(src)), ExpandFromFile(env))
+		require.NoError(t, err, "couldn't construct provider")
+		_, ok := os.LookupEnv("FILE_ONLY")
+		assert.False(t, ok, "file variables shouldn't leak into the process environment")
+	})
+
+	t.Run("parsing", func(t *testing.T) {
+		vars, err := parseEnvFile([]byte(strings.Join([]string{
+			`DOUBLE="a # not a comment\t\"b\""  # comment`,
+			`SINGLE='a \t b'`,
+			"PLAIN = spaced value # comment",
+			"EMPTY=",
+			`EMPTY_QUOTED=""`,
+		}, "\n")))
+		require.NoError(t, err, "couldn't parse dotenv file")
+		assert.Equal(t, map[string]string{
+			"DOUBLE":       "a # not a comment\t\"b\"",
+			"SINGLE":       `a \t b`,
+			"PLAIN":        "spaced value",
+			"EMPTY":        "",
+			"EMPTY_QUOTED": "",
+		}, vars, "unexpected variables")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		tests := []struct {
+			contents string
+			err      string
+		}{
+			{"FOO=bar\nnot a definition", "line 2: expected KEY=VALUE"},
+			{"1FOO=bar", `line 1: invalid variable name "1FOO"`},
+			{`FOO="unclosed`, "line 1: missing closing \""},
+			{`FOO='a' b`, `line 1: unexpected "b" after closing '`},
+		}
+		for _, tt := range tests {
+			_, err := NewYAML(Source(strings.NewReader("{}")), ExpandFromFile(write("bad.env", tt.contents)))
+			require.Error(t, err, "expected provider construction to fail for %q", tt.contents)
+			assert.Contains(t, err.Error(), "bad.env: "+tt.err, "unexpected error message")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader("{}")), ExpandFromFile(filepath.Join(dir, "not_there.env")))
+		require.Error(t, err, "expected provider construction to fail")
+	})
+}
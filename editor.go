@@ -0,0 +1,209 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// An Editor changes individual values in a single YAML file while preserving
+// its comments and the order of its keys. It's intended for tools that
+// rewrite configuration, such as bumping a version number, and is entirely
+// separate from providers: it doesn't merge sources, expand variables, or
+// apply defaults.
+//
+// Editors rewrite the document in a normalized layout, with two-space
+// indentation and without blank lines, so whitespace may change even where
+// values don't. The quoting of replaced strings is kept.
+// They aren't safe for concurrent use.
+type Editor struct {
+	name string
+	doc  *yaml3.Node
+}
+
+// OpenEditor reads a YAML file for editing. The file must contain at most one
+// YAML document. Empty files, files containing only comments, and documents
+// that are null are treated as empty mappings, keeping any comments.
+func OpenEditor(path string) (*Editor, error) {
+	all, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeDocument(all)
+	if err == errMultipleDocuments {
+		return nil, fmt.Errorf("can't edit %s: file must contain a single YAML document", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decode %s: %v", path, err)
+	}
+	if doc == nil {
+		// gopkg.in/yaml.v3 discards the comments in files without a
+		// document, so decode the file with an explicit null for its
+		// comments to attach to.
+		withNull := append([]byte(nil), all...)
+		if len(withNull) > 0 && withNull[len(withNull)-1] != '\n' {
+			withNull = append(withNull, '\n')
+		}
+		withNull = append(withNull, "~\n"...)
+		if doc, err = decodeDocument(withNull); err != nil || doc == nil {
+			return nil, unreachable.Wrap(fmt.Errorf("couldn't decode %s: %v", path, err))
+		}
+	}
+	if root := doc.Content[0]; root.Kind == yaml3.ScalarNode && root.ShortTag() == "!!null" {
+		doc.Content[0] = &yaml3.Node{
+			Kind:        yaml3.MappingNode,
+			Tag:         "!!map",
+			HeadComment: root.HeadComment,
+			LineComment: root.LineComment,
+			FootComment: root.FootComment,
+		}
+	}
+	return &Editor{name: path, doc: doc}, nil
+}
+
+var errMultipleDocuments = errors.New("multiple YAML documents")
+
+// decodeDocument decodes a single YAML document, returning nil if there's
+// no document at all.
+func decodeDocument(bs []byte) (*yaml3.Node, error) {
+	dec := yaml3.NewDecoder(bytes.NewReader(bs))
+	var doc yaml3.Node
+	if err := dec.Decode(&doc); err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var extra yaml3.Node
+	if err := dec.Decode(&extra); err != io.EOF {
+		return nil, errMultipleDocuments
+	}
+	return &doc, nil
+}
+
+// Set replaces the value at a dot-separated key with the YAML representation
+// of value. Sequence elements are addressed by zero-based index, as in Get.
+// Missing mapping keys are created, appended after any existing keys. Comments
+// attached to a replaced value are kept.
+func (e *Editor) Set(key string, value interface{}) error {
+	if key == Root {
+		return errors.New("can't set the root of the document")
+	}
+	replacement, err := encodeNode(value)
+	if err != nil {
+		return fmt.Errorf("can't marshal value for key %q to YAML: %v", key, err)
+	}
+
+	path := strings.Split(key, _separator)
+	node := e.doc.Content[0]
+	for i, segment := range path {
+		for node.Kind == yaml3.AliasNode {
+			node = node.Alias
+		}
+		var child **yaml3.Node
+		switch node.Kind {
+		case yaml3.MappingNode:
+			child = mappingValue(node, segment)
+			if child == nil {
+				node.Content = append(node.Content,
+					&yaml3.Node{Kind: yaml3.ScalarNode, Tag: "!!str", Value: segment},
+					&yaml3.Node{Kind: yaml3.MappingNode, Tag: "!!map"},
+				)
+				child = &node.Content[len(node.Content)-1]
+			}
+		case yaml3.SequenceNode:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return fmt.Errorf("can't set key %q: no element %q in sequence at %q", key, segment, strings.Join(path[:i], _separator))
+			}
+			child = &node.Content[idx]
+		default:
+			return fmt.Errorf("can't set key %q: value at %q isn't a mapping or sequence", key, strings.Join(path[:i], _separator))
+		}
+		if i == len(path)-1 {
+			keepComments(*child, replacement)
+			*child = replacement
+			return nil
+		}
+		node = *child
+	}
+	return unreachable.Wrap(fmt.Errorf("couldn't set key %q", key))
+}
+
+// encodeNode is like yaml.v3's Node.Encode, but returns an error rather than
+// panicking on types YAML can't represent; see marshal.
+func encodeNode(val interface{}) (n *yaml3.Node, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	n = &yaml3.Node{}
+	return n, n.Encode(val)
+}
+
+// mappingValue returns the value slot for a key in a mapping node, or nil if
+// the mapping doesn't contain the key.
+func mappingValue(m *yaml3.Node, key string) **yaml3.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if k := m.Content[i]; k.Kind == yaml3.ScalarNode && k.Value == key {
+			return &m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// keepComments carries the comments, and for strings the quoting style, of an old
+// value over to its replacement.
+func keepComments(old, replacement *yaml3.Node) {
+	replacement.HeadComment = old.HeadComment
+	replacement.LineComment = old.LineComment
+	replacement.FootComment = old.FootComment
+	if old.Kind == yaml3.ScalarNode && replacement.Kind == yaml3.ScalarNode &&
+		old.ShortTag() == "!!str" && replacement.ShortTag() == "!!str" {
+		replacement.Style = old.Style
+	}
+}
+
+// Bytes returns the edited document.
+func (e *Editor) Bytes() []byte {
+	buf := &bytes.Buffer{}
+	enc := yaml3.NewEncoder(buf)
+	enc.SetIndent(2)
+	err := enc.Encode(e.doc)
+	if err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		// The document was decoded from YAML and edited only with encoded
+		// values, so it always encodes.
+		panic(unreachable.Wrap(fmt.Errorf("couldn't encode %s: %v", e.name, err)).Error())
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditor(t *testing.T) {
+	const original = `# Service configuration.
+name: api # the service name
+version: "1.2.3"
+
+server:
+  # Port to listen on.
+  port: 80
+  hosts:
+    - a.example.com # primary
+    - b.example.com
+`
+	open := func(t testing.TB, contents string) *Editor {
+		dir := writeDir(t, map[string]string{"config.yaml": contents})
+		e, err := OpenEditor(filepath.Join(dir, "config.yaml"))
+		require.NoError(t, err, "couldn't open editor")
+		return e
+	}
+
+	t.Run("comments survive", func(t *testing.T) {
+		e := open(t, original)
+		require.NoError(t, e.Set("version", "1.3.0"), "couldn't set version")
+		require.NoError(t, e.Set("server.port", 8080), "couldn't set port")
+		require.NoError(t, e.Set("server.hosts.0", "c.example.com"), "couldn't set sequence element")
+		assert.Equal(t, `# Service configuration.
+name: api # the service name
+version: "1.3.0"
+server:
+  # Port to listen on.
+  port: 8080
+  hosts:
+    - c.example.com # primary
+    - b.example.com
+`, string(e.Bytes()), "expected comments, order, and quoting to be preserved")
+	})
+
+	t.Run("new keys", func(t *testing.T) {
+		e := open(t, original)
+		require.NoError(t, e.Set("server.tls.cert", "server.pem"), "couldn't set new nested key")
+		require.NoError(t, e.Set("debug", map[string]bool{"enabled": true}), "couldn't set new mapping")
+
+		p, err := NewYAML(Source(bytes.NewReader(e.Bytes())))
+		require.NoError(t, err, "edited document should be valid configuration")
+		assert.Equal(t, "server.pem", p.Get("server.tls.cert").String(), "unexpected new value")
+		assert.Equal(t, true, p.Get("debug.enabled").Value(), "unexpected new value")
+		assert.Equal(t, 80, p.Get("server.port").Value(), "expected existing values to remain")
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		e := open(t, "")
+		require.NoError(t, e.Set("a.b", 1), "couldn't set key in empty file")
+		assert.Equal(t, "a:\n  b: 1\n", string(e.Bytes()), "unexpected document")
+	})
+
+	t.Run("comments only", func(t *testing.T) {
+		e := open(t, "# only a comment\n")
+		require.NoError(t, e.Set("a.b", 1), "couldn't set key in file with only comments")
+		assert.Equal(t, "# only a comment\na:\n  b: 1\n", string(e.Bytes()), "expected comment to survive")
+	})
+
+	t.Run("null document", func(t *testing.T) {
+		for _, contents := range []string{"---\n", "~\n", "# comment\nnull\n"} {
+			e := open(t, contents)
+			require.NoError(t, e.Set("a", 1), "couldn't set key in null document %q", contents)
+			assert.Contains(t, string(e.Bytes()), "a: 1\n", "unexpected document for %q", contents)
+		}
+		e := open(t, "# comment\nnull\n")
+		require.NoError(t, e.Set("a", 1), "couldn't set key in null document")
+		assert.Equal(t, "# comment\na: 1\n", string(e.Bytes()), "expected comment to survive")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		e := open(t, original)
+		assert.Error(t, e.Set("name.first", "x"), "expected error setting key beneath a scalar")
+		assert.Error(t, e.Set("server.hosts.5", "x"), "expected error setting out-of-range element")
+		assert.Error(t, e.Set(Root, "x"), "expected error setting root")
+		assert.Error(t, e.Set("bad", func() {}), "expected error setting unmarshalable value")
+
+		dir := writeDir(t, map[string]string{"multi.yaml": "a: 1\n---\nb: 2\n", "bad.yaml": "a: [1"})
+		_, err := OpenEditor(filepath.Join(dir, "multi.yaml"))
+		assert.Error(t, err, "expected error opening multi-document file")
+		_, err = OpenEditor(filepath.Join(dir, "bad.yaml"))
+		assert.Error(t, err, "expected error opening malformed file")
+		_, err = OpenEditor(filepath.Join(dir, "missing.yaml"))
+		assert.Error(t, err, "expected error opening missing file")
+	})
+}
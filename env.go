@@ -0,0 +1,143 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvSource builds a source of configuration from environment variables
+// whose names begin with prefix. The prefix is stripped, the remainder of
+// each name is lowercased and split on delimiter into a path, and each value
+// is interpreted using YAML's rules for scalars, as in SetSource. For
+// example, with the environment
+//   APP_SERVER__PORT=9090
+//   APP_SERVER__TLS__ENABLED=true
+//   APP_HOSTS__0=a.example.com
+//   APP_HOSTS__1=b.example.com
+// EnvSource("APP_", "__") is equivalent to the YAML
+//   server: {port: 9090, tls: {enabled: true}}
+//   hosts: [a.example.com, b.example.com]
+// As in SetSource, path segments that are non-negative integers index into
+// sequences, whose elements must be numbered consecutively from zero.
+//
+// Unlike Expand, which substitutes variables referenced in other sources,
+// EnvSource contributes configuration on its own, at its position in the
+// priority order. The environment is read when the provider is constructed,
+// and since its values are configuration rather than YAML documents, they're
+// never subject to variable expansion. Variables named just prefix are
+// ignored. Without any matching variables, EnvSource contributes nothing.
+func EnvSource(prefix, delimiter string) YAMLOption {
+	if prefix == "" {
+		return failed(errors.New("environment prefix must not be empty"))
+	}
+	if delimiter == "" {
+		return failed(errors.New("environment delimiter must not be empty"))
+	}
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{name: "EnvSource", load: func(int64) ([]source, error) {
+			bs, err := envSource(os.Environ(), prefix, delimiter)
+			return []source{{bytes: bs, raw: true, name: "EnvSource"}}, err
+		}})
+	})
+}
+
+// Base64EnvSource reads the named environment variable, decodes it as
+// standard base64 (see RFC 4648), and uses the result as a source of YAML
+// configuration. It suits CI/CD systems that inject an entire configuration
+// file as a single variable, without writing it to disk. Line breaks in the
+// encoded value are ignored. Priority, merge, and expansion logic are
+// identical to Source.
+//
+// The variable is read when the provider is constructed. It's an error if
+// the variable isn't set or isn't valid base64, and the two cases are
+// reported differently; a variable that's set but empty contributes
+// nothing. MaxSourceBytes limits the decoded size of the source.
+func Base64EnvSource(varName string) YAMLOption {
+	if varName == "" {
+		return failed(errors.New("environment variable name must not be empty"))
+	}
+	return optionFunc(func(c *config) {
+		name := fmt.Sprintf("environment variable %s", varName)
+		c.sources = append(c.sources, source{name: name, load: func(limit int64) ([]source, error) {
+			encoded, ok := os.LookupEnv(varName)
+			if !ok {
+				return nil, fmt.Errorf("environment variable %s isn't set", varName)
+			}
+			all, err := readAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader([]byte(encoded))), limit)
+			if errors.Is(err, errSourceTooLarge) {
+				return nil, err
+			}
+			if err != nil {
+				return nil, fmt.Errorf("environment variable %s isn't valid base64: %v", varName, err)
+			}
+			return []source{{bytes: all, name: name}}, nil
+		}})
+	})
+}
+
+func envSource(environ []string, prefix, delimiter string) ([]byte, error) {
+	var settings []setting
+	for _, kv := range environ {
+		eq := strings.IndexByte(kv, '=')
+		// A variable named just the prefix has no path, so it can't be
+		// configuration.
+		if eq < 0 || eq == len(prefix) || !strings.HasPrefix(kv[:eq], prefix) {
+			continue
+		}
+		name := kv[:eq]
+		settings = append(settings, setting{
+			name:  name,
+			path:  strings.Split(strings.ToLower(name[len(prefix):]), delimiter),
+			value: kv[eq+1:],
+		})
+	}
+	// Set sequence elements in numeric order, so that FOO_10 follows FOO_9.
+	sort.Slice(settings, func(i, j int) bool {
+		return lessPath(settings[i].path, settings[j].path)
+	})
+
+	return settingsYAML(settings, "invalid environment variable %s")
+}
+
+// lessPath orders paths segment by segment, comparing integer segments
+// numerically.
+func lessPath(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		ai, aerr := strconv.Atoi(a[i])
+		bi, berr := strconv.Atoi(b[i])
+		if aerr == nil && berr == nil {
+			return ai < bi
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
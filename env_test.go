@@ -0,0 +1,205 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"encoding/base64"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvSource(t *testing.T) {
+	t.Run("nested keys and types", func(t *testing.T) {
+		bs, err := envSource([]string{
+			"APP_SERVER__PORT=9090",
+			"APP_SERVER__TLS__ENABLED=true",
+			"APP_SERVER__NAME=api",
+			"APP_RATIO=0.5",
+			"APP_EMPTY=",
+			"APP_HOSTS__1=b",
+			"APP_HOSTS__0=a",
+			"APP_SHARDS__10=k",
+			"APP_SHARDS__0=a", "APP_SHARDS__1=b", "APP_SHARDS__2=c", "APP_SHARDS__3=d", "APP_SHARDS__4=e",
+			"APP_SHARDS__5=f", "APP_SHARDS__6=g", "APP_SHARDS__7=h", "APP_SHARDS__8=i", "APP_SHARDS__9=j",
+			"OTHER_SERVER__PORT=1",
+			"APPLICATION=unrelated",
+		}, "APP_", "__")
+		require.NoError(t, err, "couldn't build source")
+		p, err := NewYAML(RawSource(strings.NewReader(string(bs))))
+		require.NoError(t, err, "couldn't construct provider")
+
+		var cfg struct {
+			Server struct {
+				Port int
+				Name string
+				TLS  struct{ Enabled bool }
+			}
+			Ratio  float64
+			Empty  string
+			Hosts  []string
+			Shards []string
+		}
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, 9090, cfg.Server.Port, "expected integer to be inferred")
+		assert.Equal(t, "api", cfg.Server.Name, "unexpected string")
+		assert.True(t, cfg.Server.TLS.Enabled, "expected Boolean to be inferred")
+		assert.Equal(t, 0.5, cfg.Ratio, "expected float to be inferred")
+		assert.Equal(t, "", cfg.Empty, "expected empty value to be an empty string")
+		assert.Equal(t, []string{"a", "b"}, cfg.Hosts, "unexpected sequence")
+		assert.Equal(t, strings.Split("abcdefghijk", ""), cfg.Shards, "expected sequence indices to sort numerically")
+		assert.Equal(t, []string{"empty", "hosts", "ratio", "server", "shards"}, sortedKeys(p.Get(Root).Value()), "expected other prefixes to be ignored")
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		_, err := envSource([]string{"APP_SERVER=x", "APP_SERVER__PORT=1"}, "APP_", "__")
+		require.Error(t, err, "expected conflicting variables to fail")
+		assert.Contains(t, err.Error(), "invalid environment variable APP_SERVER", "expected error to name the variable")
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		bs, err := envSource([]string{"APP_COLOR=#ff0000", "APP_MESSAGE=hi # there"}, "APP_", "__")
+		require.NoError(t, err, "couldn't build source")
+		p, err := NewYAML(RawSource(strings.NewReader(string(bs))))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "#ff0000", p.Get("color").Value(), "expected comment-like value to be kept")
+		assert.Equal(t, "hi # there", p.Get("message").Value(), "expected inline comment to be kept")
+	})
+
+	t.Run("prefix alone", func(t *testing.T) {
+		bs, err := envSource([]string{"APP_=x", "APP_PORT=1"}, "APP_", "__")
+		require.NoError(t, err, "expected variable named just the prefix to be ignored")
+		assert.Equal(t, "port: 1\n", string(bs), "unexpected YAML")
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		for k, v := range map[string]string{
+			"CONFIG_TEST_ENVSOURCE__SERVER__PORT": "8080",
+			"CONFIG_TEST_ENVSOURCE__PASSWORD":     "pa$$word",
+		} {
+			require.NoError(t, os.Setenv(k, v), "couldn't set %s", k)
+			defer os.Unsetenv(k)
+		}
+
+		p, err := NewYAML(
+			Source(strings.NewReader("server: {port: 80, host: localhost}")),
+			EnvSource("CONFIG_TEST_ENVSOURCE__", "__"),
+			Expand(func(string) (string, bool) { return "expanded", true }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("server.port").Value(), "expected environment to override earlier sources")
+		assert.Equal(t, "localhost", p.Get("server.host").Value(), "expected earlier sources to be merged")
+		assert.Equal(t, "pa$$word", p.Get("password").Value(), "expected environment values not to be expanded")
+
+		empty, err := NewYAML(EnvSource("CONFIG_TEST_ENVSOURCE_UNSET__", "__"))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.False(t, empty.Get(Root).HasValue(), "expected no configuration without matching variables")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := NewYAML(EnvSource("", "__"))
+		assert.Error(t, err, "expected error for empty prefix")
+		_, err = NewYAML(EnvSource("APP_", ""))
+		assert.Error(t, err, "expected error for empty delimiter")
+	})
+}
+
+func sortedKeys(v interface{}) []string {
+	m, _ := v.(map[interface{}]interface{})
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k.(string))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestBase64EnvSource(t *testing.T) {
+	const envVar = "CONFIG_TEST_BASE64ENVSOURCE"
+	setenv := func(t *testing.T, v string) {
+		require.NoError(t, os.Setenv(envVar, v), "couldn't set %s", envVar)
+		t.Cleanup(func() { os.Unsetenv(envVar) })
+	}
+
+	t.Run("valid payload", func(t *testing.T) {
+		setenv(t, base64.StdEncoding.EncodeToString([]byte("server:\n  port: ${PORT}\n  host: example.com\n")))
+		p, err := NewYAML(
+			Static(map[string]interface{}{"server": map[string]interface{}{"port": 80, "tls": true}}),
+			Base64EnvSource(envVar),
+			Expand(func(string) (string, bool) { return "8080", true }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("server.port").Value(), "expected decoded source to be expanded and override earlier sources")
+		assert.Equal(t, "example.com", p.Get("server.host").Value(), "unexpected value from decoded source")
+		assert.Equal(t, true, p.Get("server.tls").Value(), "expected earlier sources to be merged")
+	})
+
+	t.Run("wrapped lines", func(t *testing.T) {
+		encoded := base64.StdEncoding.EncodeToString([]byte("greeting: a fairly long value that wraps\n"))
+		setenv(t, encoded[:20]+"\n"+encoded[20:])
+		p, err := NewYAML(Base64EnvSource(envVar))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "a fairly long value that wraps", p.Get("greeting").Value(), "unexpected value from wrapped payload")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		setenv(t, "")
+		p, err := NewYAML(Base64EnvSource(envVar))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.False(t, p.Get(Root).HasValue(), "expected no configuration from an empty variable")
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		_, err := NewYAML(Base64EnvSource(envVar + "_UNSET"))
+		require.Error(t, err, "expected an error for an unset variable")
+		assert.Contains(t, err.Error(), "environment variable "+envVar+"_UNSET isn't set", "unexpected error message")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		setenv(t, "not base64!")
+		_, err := NewYAML(Base64EnvSource(envVar))
+		require.Error(t, err, "expected an error for malformed base64")
+		assert.Contains(t, err.Error(), "environment variable "+envVar+" isn't valid base64", "unexpected error message")
+		assert.NotContains(t, err.Error(), "isn't set", "malformed values shouldn't be reported as unset")
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		setenv(t, base64.StdEncoding.EncodeToString([]byte("foo: [")))
+		_, err := NewYAML(Base64EnvSource(envVar))
+		require.Error(t, err, "expected an error for invalid YAML")
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		setenv(t, base64.StdEncoding.EncodeToString([]byte("foo: bar\n")))
+		_, err := NewYAML(Base64EnvSource(envVar), MaxSourceBytes(4))
+		require.Error(t, err, "expected an error for an oversized payload")
+		assert.Contains(t, err.Error(), "larger than the maximum of 4 bytes", "unexpected error message")
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		_, err := NewYAML(Base64EnvSource(""))
+		assert.Error(t, err, "expected an error for an empty variable name")
+	})
+}
@@ -27,9 +27,28 @@ import (
 	yaml "gopkg.in/yaml.v2"
 )
 
+// Equal reports whether two providers hold semantically identical
+// configuration: that is, whether their canonical forms match (see
+// CanonicalBytes), regardless of how the sources spelled keys and scalars or
+// ordered mappings. Only the configuration is compared, not the providers'
+// names or other options. A provider with no configuration at all is equal
+// only to other empty providers, not to one whose configuration is an
+// explicit top-level null.
+//
+// Equal is useful for skipping reloads that don't change anything.
+func (y *YAML) Equal(other *YAML) (bool, error) {
+	if y == nil || other == nil {
+		return y == other, nil
+	}
+	if y.empty || other.empty {
+		return y.empty == other.empty, nil
+	}
+	return areSameYAML(y.contents, other.contents)
+}
+
 // areSameYAML checks whether two values represent the same YAML data. It's
-// only called from NewValue, where we must validate that the user-supplied
-// value matches the contents of the user-supplied provider.
+// used by NewValue, where we must validate that the user-supplied value
+// matches the contents of the user-supplied provider, and by YAML.Equal.
 func areSameYAML(fromProvider, fromUser interface{}) (bool, error) {
 	p, err := yaml.Marshal(fromProvider)
 	if err != nil {
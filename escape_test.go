@@ -3,9 +3,11 @@ package config
 import (
 	"bytes"
 	"io/ioutil"
+	"strings"
 	"testing"
 	"testing/quick"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/transform"
 )
@@ -27,3 +29,60 @@ func TestEscapeVariablesQuick(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestEscapingEdgeCases(t *testing.T) {
+	lookup := func(k string) (string, bool) {
+		if k == "FOO" {
+			return "foo", true
+		}
+		return "", false
+	}
+	tests := []struct {
+		desc      string
+		value     string
+		expanded  string // from a source with expansion enabled
+		unchanged bool   // whether expansion leaves the value as-is
+	}{
+		{"lone dollar", "costs $", "costs $", true},
+		{"dollar before digit", "costs $5", "costs $5", true},
+		{"dollar before space", "$ 5", "$ 5", true},
+		{"double dollar", "$$FOO", "$FOO", false},
+		{"unclosed brace", "${FOO", "${FOO", true},
+		{"unmatched brace", "FOO}", "FOO}", true},
+		{"variable", "${FOO}-$FOO", "foo-foo", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			src := "key: '" + tt.value + "'"
+
+			t.Run("source", func(t *testing.T) {
+				p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup))
+				require.NoError(t, err, "couldn't construct provider")
+				assert.Equal(t, tt.expanded, p.Get("key").Value(), "unexpected expanded value")
+
+				s, err := ExpandString(lookup, tt.value)
+				require.NoError(t, err, "couldn't expand string")
+				assert.Equal(t, tt.expanded, s, "ExpandString should match provider expansion")
+				if tt.unchanged {
+					assert.Equal(t, tt.value, s, "expected value to be unchanged")
+				}
+			})
+
+			t.Run("raw source", func(t *testing.T) {
+				p, err := NewYAML(RawSource(strings.NewReader(src)), Expand(lookup))
+				require.NoError(t, err, "couldn't construct provider")
+				assert.Equal(t, tt.value, p.Get("key").Value(), "raw sources shouldn't be expanded")
+			})
+
+			t.Run("raw source without expansion", func(t *testing.T) {
+				p, err := NewYAML(RawSource(strings.NewReader(src)))
+				require.NoError(t, err, "couldn't construct provider")
+				assert.Equal(t, tt.value, p.Get("key").Value(), "raw sources shouldn't be escaped")
+
+				v, err := p.Get("other").WithDefault("default")
+				require.NoError(t, err, "couldn't set default")
+				assert.Equal(t, tt.value, v.provider.Get("key").Value(), "raw sources shouldn't be escaped by WithDefault")
+			})
+		})
+	}
+}
@@ -22,11 +22,18 @@ package config
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"go.uber.org/config/internal/unreachable"
 	"golang.org/x/text/transform"
+	yaml "gopkg.in/yaml.v2"
 )
 
 const (
@@ -39,20 +46,273 @@ const (
 // present.
 type LookupFunc = func(string) (string, bool)
 
-func expandVariables(f LookupFunc, buf *bytes.Buffer) (*bytes.Buffer, error) {
+// A LookupErrFunc is like a LookupFunc, but can also report that the lookup
+// itself failed (for example, because a secret store was unreachable). Unlike
+// a missing key, a failed lookup never falls back to a default value.
+type LookupErrFunc = func(string) (string, bool, error)
+
+// A LookupContextFunc is like a LookupErrFunc, but also accepts a context
+// that bounds the lookup. See ExpandContext.
+type LookupContextFunc = func(context.Context, string) (string, bool, error)
+
+// ignoreContext adapts a LookupErrFunc to a LookupContextFunc.
+func ignoreContext(f LookupErrFunc) LookupContextFunc {
+	if f == nil {
+		return nil
+	}
+	return func(_ context.Context, key string) (string, bool, error) {
+		return f(key)
+	}
+}
+
+// bindContext adapts a LookupContextFunc to a LookupErrFunc that uses the
+// supplied context, failing without calling f once the context is done.
+func bindContext(ctx context.Context, f LookupContextFunc) LookupErrFunc {
+	return func(key string) (string, bool, error) {
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
+		return f(ctx, key)
+	}
+}
+
+// cachedLookup implements CacheExpansion, wrapping f so that each variable
+// returns the result of its first successful lookup from then on. It's safe
+// for concurrent use.
+func cachedLookup(f LookupContextFunc) LookupContextFunc {
+	type result struct {
+		value string
+		found bool
+	}
+	var (
+		mu    sync.Mutex
+		cache = make(map[string]result)
+	)
+	return func(ctx context.Context, key string) (string, bool, error) {
+		mu.Lock()
+		r, ok := cache[key]
+		mu.Unlock()
+		if ok {
+			return r.value, r.found, nil
+		}
+		value, found, err := f(ctx, key)
+		if err != nil {
+			return "", false, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		// If a concurrent lookup finished first, agree with it.
+		if r, ok := cache[key]; ok {
+			return r.value, r.found, nil
+		}
+		cache[key] = result{value: value, found: found}
+		return value, found, nil
+	}
+}
+
+// withoutErrors adapts a LookupFunc to a LookupErrFunc that never fails.
+func withoutErrors(f LookupFunc) LookupErrFunc {
+	if f == nil {
+		return nil
+	}
+	return func(key string) (string, bool, error) {
+		val, ok := f(key)
+		return val, ok, nil
+	}
+}
+
+// allowlisted wraps a lookup function so that looking up any variable not in
+// the allowlist fails. A nil allowlist allows every variable.
+func allowlisted(f LookupErrFunc, allowed map[string]struct{}) LookupErrFunc {
+	if f == nil || allowed == nil {
+		return f
+	}
+	return func(key string) (string, bool, error) {
+		if _, ok := allowed[key]; !ok {
+			return "", false, fmt.Errorf("variable %q isn't in the allowlist", key)
+		}
+		return f(key)
+	}
+}
+
+func expandVariables(f LookupErrFunc, buf *bytes.Buffer) (*bytes.Buffer, error) {
 	if f == nil {
 		return buf, nil
 	}
-	exp, err := ioutil.ReadAll(transform.NewReader(buf, newExpandTransformer(f)))
+	exp, err := ioutil.ReadAll(transform.NewReader(buf, newExpandTransformerE(f)))
 	if err != nil {
-		return nil, fmt.Errorf("couldn't expand environment: %v", err)
+		return nil, fmt.Errorf("couldn't expand environment: %w", err)
 	}
 	return bytes.NewBuffer(exp), nil
 }
 
+// ExpandString expands variable references in a string, using the same
+// syntax and lookup semantics as the Expand option. It's useful for
+// previewing how a snippet of configuration will be expanded. It's an error
+// if lookup is nil.
+func ExpandString(lookup LookupFunc, s string) (string, error) {
+	if lookup == nil {
+		return "", errors.New("lookup function must not be nil")
+	}
+	exp, _, err := transform.String(newExpandTransformer(lookup), s)
+	if err != nil {
+		return "", fmt.Errorf("couldn't expand environment: %v", err)
+	}
+	return exp, nil
+}
+
+// RequiredEnvVars lists the variables that the configuration's sources
+// reference without a default, sorted and without duplicates. These are the
+// variables that must be set for provider construction to succeed, so
+// RequiredEnvVars supports pre-flight checks in deployment pipelines. Both
+// ${VAR} and $VAR references are found, in keys and values alike; references
+// with a default, like ${VAR:fallback}, and escaped references, like $${VAR},
+// aren't required. Raw sources, values exempted by NoExpandPattern, and
+// values at Verbatim paths aren't expanded, so references in them are
+// ignored, as are references in YAML comments.
+//
+// Each source is scanned separately, so a reference is reported even if a
+// higher-priority source overrides the value holding it. If the provider
+// doesn't expand variables (see Expand), nothing is required.
+func (y *YAML) RequiredEnvVars() []string {
+	if y.lookup == nil {
+		return nil
+	}
+	seen := make(map[string]struct{})
+	collect := &expandTransformer{expand: func(in string) (string, error) {
+		key, def := in, ""
+		if sep := strings.Index(in, _envSeparator); sep != -1 {
+			key, def = in[:sep], in[sep+1:]
+		}
+		if def == "" {
+			seen[key] = struct{}{}
+		}
+		return "", nil
+	}}
+	for _, src := range y.raw {
+		var contents interface{}
+		if err := yaml.Unmarshal(src, &contents); err != nil {
+			// Construction already decoded every source.
+			continue
+		}
+		contents = replaceMatching(contents, y.noExpand, func(string) string { return "" })
+		for _, v := range y.verbatim {
+			contents = replaceAt(contents, v.path, "", y.keyMatch)
+		}
+		eachString(contents, func(s string) {
+			transform.String(collect, s)
+		})
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	vars := make([]string, 0, len(seen))
+	for v := range seen {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return vars
+}
+
+// eachString calls f for every string key and value in a YAML node.
+func eachString(node interface{}, f func(string)) {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			eachString(k, f)
+			eachString(v, f)
+		}
+	case []interface{}:
+		for _, v := range n {
+			eachString(v, f)
+		}
+	case string:
+		f(n)
+	}
+}
+
+// checkReferences validates the syntax of every variable reference in the
+// merged YAML, reporting the first malformed reference and the key holding it.
+func checkReferences(merged []byte) error {
+	var contents interface{}
+	if err := yaml.Unmarshal(merged, &contents); err != nil {
+		return unreachable.Wrap(fmt.Errorf("couldn't decode merged YAML: %v", err))
+	}
+	return checkNode(nil, contents)
+}
+
+func checkNode(path []string, node interface{}) error {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			child := extend(path, fmt.Sprint(k))
+			if err := checkNode(child, k); err != nil {
+				return err
+			}
+			if err := checkNode(child, v); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, v := range n {
+			if err := checkNode(extend(path, strconv.Itoa(i)), v); err != nil {
+				return err
+			}
+		}
+	case string:
+		if ref, problem := malformedReference(n); problem != "" {
+			return fmt.Errorf(
+				"malformed variable reference %q at key %q: %s",
+				ref,
+				strings.Join(path, _separator),
+				problem,
+			)
+		}
+	}
+	return nil
+}
+
+// malformedReference finds the first bracketed variable reference in s that
+// StrictExpand rejects, returning it and a description of the problem. If all
+// references are well-formed, the problem is empty.
+func malformedReference(s string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			continue
+		}
+		if s[i+1] == '$' {
+			i++ // skip the escaped $
+			continue
+		}
+		if s[i+1] != '{' {
+			continue
+		}
+		end := strings.IndexByte(s[i+2:], '}')
+		if end == -1 {
+			return s[i:], "missing closing brace"
+		}
+		ref := s[i : i+end+3]
+		key := s[i+2 : i+end+2]
+		if sep := strings.Index(key, _envSeparator); sep != -1 {
+			key = key[:sep]
+		}
+		if key == "" {
+			return ref, "empty variable name"
+		}
+		if !isShellNameFirstChar(key[0]) {
+			return ref, fmt.Sprintf("variable name can't start with %q", key[0])
+		}
+		if bad := bytesIndexCFunc([]byte(key), isShellNameChar); bad != -1 {
+			return ref, fmt.Sprintf("invalid character %q in variable name", key[bad])
+		}
+		i += end + 2
+	}
+	return "", ""
+}
+
 // Given a function with the same signature as os.LookupEnv, return a function
 // that expands expressions of the form ${ENV_VAR:default_value}.
-func replace(lookUp LookupFunc) func(in string) (string, error) {
+func replace(lookUp LookupErrFunc) func(in string) (string, error) {
 	return func(in string) (string, error) {
 		sep := strings.Index(in, _envSeparator)
 		var key string
@@ -67,7 +327,11 @@ func replace(lookUp LookupFunc) func(in string) (string, error) {
 			def = in[sep+1:]
 		}
 
-		if envVal, ok := lookUp(key); ok {
+		envVal, ok, err := lookUp(key)
+		if err != nil {
+			return "", fmt.Errorf("couldn't look up %q: %w", key, err)
+		}
+		if ok {
 			return envVal, nil
 		}
 
@@ -89,6 +353,10 @@ type expandTransformer struct {
 }
 
 func newExpandTransformer(lookup LookupFunc) *expandTransformer {
+	return newExpandTransformerE(withoutErrors(lookup))
+}
+
+func newExpandTransformerE(lookup LookupErrFunc) *expandTransformer {
 	return &expandTransformer{expand: replace(lookup)}
 }
 
@@ -22,12 +22,14 @@ package config
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -278,3 +280,505 @@ func TestTransformLimit(t *testing.T) {
 		)
 	}
 }
+
+func TestExpandString(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "FOO" {
+			return "bar", true
+		}
+		return "", false
+	}
+
+	t.Run("success", func(t *testing.T) {
+		s, err := ExpandString(lookup, `$FOO ${FOO:x} ${MISSING:default} $$FOO $`)
+		require.NoError(t, err, "couldn't expand string")
+		assert.Equal(t, "bar bar default $FOO $", s, "unexpected expansion")
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		_, err := ExpandString(lookup, "$MISSING")
+		require.Error(t, err, "expected expansion to fail")
+		assert.Contains(t, err.Error(), `"MISSING"`, "expected error to name the variable")
+	})
+
+	t.Run("nil lookup", func(t *testing.T) {
+		_, err := ExpandString(nil, "${FOO}")
+		require.Error(t, err, "expected nil lookup to fail")
+		assert.Contains(t, err.Error(), "must not be nil", "unexpected error message")
+	})
+}
+
+func TestStrictExpand(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "FOO" {
+			return "bar", true
+		}
+		return "", false
+	}
+	provider := func(src string, opts ...YAMLOption) (*YAML, error) {
+		opts = append([]YAMLOption{Source(strings.NewReader(src)), Expand(lookup)}, opts...)
+		return NewYAML(opts...)
+	}
+
+	t.Run("valid references", func(t *testing.T) {
+		p, err := provider(`
+a: ${FOO}
+b: ${MISSING:default}
+c: $FOO and $$ and $$${FOO}
+d: cost is $5
+`, StrictExpand())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "bar", p.Get("a").Value(), "unexpected value")
+		assert.Equal(t, "default", p.Get("b").Value(), "unexpected value")
+		assert.Equal(t, "bar and $ and $bar", p.Get("c").Value(), "unexpected value")
+		assert.Equal(t, "cost is $5", p.Get("d").Value(), "unexpected value")
+	})
+
+	tests := []struct {
+		desc string
+		src  string
+		err  string
+	}{
+		{
+			desc: "unclosed brace",
+			src:  "db:\n  password: ${UNCLOSED\nother: '}'",
+			err:  `malformed variable reference "${UNCLOSED" at key "db.password": missing closing brace`,
+		},
+		{
+			desc: "empty reference",
+			src:  "hosts: [ok, '${}']",
+			err:  `malformed variable reference "${}" at key "hosts.1": empty variable name`,
+		},
+		{
+			desc: "empty name with default",
+			src:  "a: ${:default}",
+			err:  `malformed variable reference "${:default}" at key "a": empty variable name`,
+		},
+		{
+			desc: "invalid character",
+			src:  "a: prefix-${BAD NAME}",
+			err:  `malformed variable reference "${BAD NAME}" at key "a": invalid character ' ' in variable name`,
+		},
+		{
+			desc: "leading digit",
+			src:  "a: ${1FOO:x}",
+			err:  `malformed variable reference "${1FOO:x}" at key "a": variable name can't start with '1'`,
+		},
+		{
+			desc: "malformed key",
+			src:  "${BAD-KEY:x}: value",
+			err:  `malformed variable reference "${BAD-KEY:x}" at key "${BAD-KEY:x}": invalid character '-' in variable name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := provider(tt.src, StrictExpand())
+			require.Error(t, err, "expected provider construction to fail")
+			assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+		})
+	}
+
+	t.Run("lenient by default", func(t *testing.T) {
+		p, err := provider("a: ${UNCLOSED")
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "${UNCLOSED", p.Get("a").Value(), "unexpected value")
+	})
+
+	t.Run("raw sources", func(t *testing.T) {
+		p, err := NewYAML(RawSource(strings.NewReader("a: ${BAD NAME}")), Expand(lookup), StrictExpand())
+		require.NoError(t, err, "raw sources shouldn't be checked")
+		assert.Equal(t, "${BAD NAME}", p.Get("a").Value(), "unexpected value")
+	})
+}
+
+func TestEnvAllowlist(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		switch key {
+		case "FOO":
+			return "bar", true
+		case "SECRET":
+			return "hunter2", true
+		}
+		return "", false
+	}
+	provider := func(src string, opts ...YAMLOption) (*YAML, error) {
+		opts = append([]YAMLOption{Source(strings.NewReader(src)), Expand(lookup)}, opts...)
+		return NewYAML(opts...)
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		p, err := provider("a: ${FOO}\nb: $FOO\nc: ${UNSET:fallback}\nd: $${SECRET}", EnvAllowlist("FOO"), EnvAllowlist("UNSET"))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "bar", p.Get("a").Value(), "unexpected value")
+		assert.Equal(t, "bar", p.Get("b").Value(), "unexpected value")
+		assert.Equal(t, "fallback", p.Get("c").Value(), "expected default for allowed, unset variable")
+		assert.Equal(t, "${SECRET}", p.Get("d").Value(), "expected escaped reference to be ignored")
+
+		_, err = provider("a: ${UNSET}", EnvAllowlist("UNSET"))
+		require.Error(t, err, "expected allowed, unset variable without default to fail")
+		assert.Contains(t, err.Error(), `default is empty for "UNSET"`, "unexpected error message")
+	})
+
+	t.Run("disallowed", func(t *testing.T) {
+		tests := []struct {
+			desc string
+			src  string
+		}{
+			{"set", "a: ${SECRET}"},
+			{"unset", "a: ${MISSING}"},
+			{"with default", "a: ${SECRET:fallback}"},
+			{"unbraced", "a: $SECRET"},
+			{"in key", "${SECRET}: a"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.desc, func(t *testing.T) {
+				_, err := provider(tt.src, EnvAllowlist("FOO"))
+				require.Error(t, err, "expected disallowed reference to fail")
+				assert.Contains(t, err.Error(), "isn't in the allowlist", "unexpected error message")
+				assert.NotContains(t, err.Error(), "hunter2", "error must not leak the variable's value")
+			})
+		}
+
+		_, err := provider("a: ${FOO}", EnvAllowlist())
+		assert.Error(t, err, "expected empty allowlist to reject every reference")
+	})
+
+	t.Run("unexpanded sources", func(t *testing.T) {
+		p, err := NewYAML(
+			RawSource(strings.NewReader("a: ${SECRET}")),
+			Expand(lookup),
+			EnvAllowlist("FOO"),
+		)
+		require.NoError(t, err, "expected raw sources to be exempt")
+		assert.Equal(t, "${SECRET}", p.Get("a").Value(), "unexpected value")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		p, err := provider("a: ${FOO}", EnvAllowlist("FOO"))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.Get(Root).WithDefault(map[string]string{"b": "${SECRET}"})
+		require.Error(t, err, "expected allowlist to apply to defaults")
+		assert.Contains(t, err.Error(), "isn't in the allowlist", "unexpected error message")
+	})
+}
+
+func TestExpandKeys(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		switch key {
+		case "REGION", "FALLBACK":
+			return "us-east", true
+		case "PORT":
+			return "8080", true
+		}
+		return "", false
+	}
+	provider := func(sources ...string) (*YAML, error) {
+		opts := []YAMLOption{Expand(lookup)}
+		for _, s := range sources {
+			opts = append(opts, Source(strings.NewReader(s)))
+		}
+		return NewYAML(opts...)
+	}
+
+	t.Run("expanded keys", func(t *testing.T) {
+		p, err := provider(`
+regions:
+  ${REGION}:
+    replicas: 3
+  ${MISSING:eu-west}:
+    replicas: 1
+ports:
+  $PORT: http
+`)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 3, p.Get("regions.us-east.replicas").Value(), "expected key to be expanded")
+		assert.Equal(t, 1, p.Get("regions.eu-west.replicas").Value(), "expected key to use default")
+		assert.Equal(t, "http", p.Get("ports.8080").Value(), "expected expanded key to be addressable")
+		assert.False(t, p.Get("regions.${REGION}").HasValue(), "unexpanded key shouldn't exist")
+	})
+
+	tests := []struct {
+		desc    string
+		sources []string
+	}{
+		{
+			desc:    "two variables",
+			sources: []string{"${REGION}: 1\n${FALLBACK}: 2"},
+		},
+		{
+			desc:    "variable and literal",
+			sources: []string{"zones:\n  ${REGION}: {a: 1}\n  us-east: {b: 2}"},
+		},
+		{
+			desc:    "across sources",
+			sources: []string{"zones: {us-east: {a: 1}}", "zones:\n  ${REGION}: {b: 2}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("duplicate after expansion/"+tt.desc, func(t *testing.T) {
+			_, err := provider(tt.sources...)
+			require.Error(t, err, "expected duplicate keys to conflict")
+			assert.Contains(t, err.Error(), "after expanding variables", "unexpected error message")
+			assert.Contains(t, err.Error(), `key "us-east" already set`, "unexpected error message")
+		})
+	}
+
+	t.Run("duplicate after expansion/permissive", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("${REGION}: 1\n${FALLBACK}: 2")), Expand(lookup), Permissive())
+		require.NoError(t, err, "permissive mode should tolerate duplicate keys")
+		assert.True(t, p.Get("us-east").HasValue(), "expected expanded key")
+	})
+}
+
+func TestExpandE(t *testing.T) {
+	errUnavailable := errors.New("secret store unavailable")
+	lookup := func(key string) (string, bool, error) {
+		switch key {
+		case "FOO":
+			return "bar", true, nil
+		case "VAULT_TOKEN":
+			return "", false, errUnavailable
+		}
+		return "", false, nil
+	}
+
+	t.Run("success", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("a: $FOO\nb: ${MISSING:default}")), ExpandE(lookup))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "bar", p.Get("a").Value(), "unexpected value")
+		assert.Equal(t, "default", p.Get("b").Value(), "unexpected value")
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		// Failed lookups don't fall back to defaults.
+		_, err := NewYAML(Source(strings.NewReader("token: ${VAULT_TOKEN:fallback}")), ExpandE(lookup))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.True(t, errors.Is(err, errUnavailable), "expected lookup error to be wrapped")
+		assert.Contains(t, err.Error(), `couldn't look up "VAULT_TOKEN": secret store unavailable`, "expected error to name the variable")
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("a: $FOO")), ExpandE(lookup))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.Get(Root).WithDefault(map[string]string{"token": "$VAULT_TOKEN"})
+		assert.True(t, errors.Is(err, errUnavailable), "expected WithDefault to use the same lookup")
+	})
+}
+
+func TestNewYAMLContext(t *testing.T) {
+	// slow blocks until its context is done, like a lookup against an
+	// unresponsive secret store.
+	slow := func(ctx context.Context, key string) (string, bool, error) {
+		if key == "FAST" {
+			return "fast", true, nil
+		}
+		<-ctx.Done()
+		return "", false, ctx.Err()
+	}
+
+	t.Run("canceled lookup", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := NewYAMLContext(ctx, Source(strings.NewReader("token: $VAULT_TOKEN")), ExpandContext(slow))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected context error to be wrapped")
+		assert.Contains(t, err.Error(), "VAULT_TOKEN", "expected error to name the variable")
+	})
+
+	t.Run("already canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		lookup := func(string) (string, bool, error) {
+			calls++
+			return "", false, nil
+		}
+		_, err := NewYAMLContext(ctx, Source(strings.NewReader("a: $FOO")), ExpandE(lookup))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.True(t, errors.Is(err, context.Canceled), "expected context error to be wrapped")
+		assert.Zero(t, calls, "expected no lookups after cancellation")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		p, err := NewYAMLContext(context.Background(), Source(strings.NewReader("a: $FAST")), ExpandContext(slow))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "fast", p.Get("a").Value(), "unexpected value")
+
+		_, err = p.Get(Root).WithDefault(map[string]string{"b": "$FAST"})
+		assert.NoError(t, err, "expected WithDefault to use the context lookup")
+	})
+}
+
+func TestRequiredEnvVars(t *testing.T) {
+	lookup := func(string) (string, bool) { return "x", true }
+
+	p, err := NewYAML(
+		Source(strings.NewReader(`
+braced: ${BRACED}
+unbraced: $UNBRACED and ${BRACED}
+defaulted: ${DEFAULTED:fallback}
+empty_default: ${EMPTY_DEFAULT:""}
+missing_default: ${MISSING_DEFAULT:}
+escaped: $${ESCAPED}
+${KEY_VAR}: value
+list: [$IN_LIST]
+# comment: ${IN_COMMENT}
+`)),
+		RawSource(strings.NewReader("raw: ${RAW}")),
+		Source(strings.NewReader("braced: overridden ${OVERRIDE}")),
+		Expand(lookup),
+	)
+	require.NoError(t, err, "couldn't construct provider")
+	assert.Equal(t, []string{
+		"BRACED",
+		"IN_LIST",
+		"KEY_VAR",
+		"MISSING_DEFAULT",
+		"OVERRIDE",
+		"UNBRACED",
+	}, p.RequiredEnvVars(), "unexpected required variables")
+
+	t.Run("defaults", func(t *testing.T) {
+		withDefault, err := p.Get(Root).WithDefault(map[string]string{"extra": "${FROM_DEFAULT}"})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.Contains(t, withDefault.provider.RequiredEnvVars(), "FROM_DEFAULT", "expected references in defaults")
+	})
+
+	t.Run("verbatim", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("script: echo $SECRET\nname: $NAME")),
+			Verbatim("script"),
+			Expand(lookup),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, []string{"NAME"}, p.RequiredEnvVars(), "expected verbatim values to be ignored")
+	})
+
+	t.Run("without expansion", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("a: ${FOO}")))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Empty(t, p.RequiredEnvVars(), "expected no required variables without expansion")
+	})
+}
+
+func TestEscapedReferences(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "VAR" {
+			return "expanded", true
+		}
+		return "", false
+	}
+	src := strings.Join([]string{
+		`price: "$$5"`,
+		`literal: $${VAR}`,
+		`normal: ${VAR}`,
+		`mixed: $${VAR} is ${VAR}`,
+		`bare: $$VAR`,
+		`default: $${VAR:fallback}`,
+		`unset: $${UNSET}`,
+		`doubled: $$$${VAR}`,
+		`escaped then expanded: $$${VAR}`,
+	}, "\n")
+	expect := map[string]interface{}{
+		"price":                 "$5",
+		"literal":               "${VAR}",
+		"normal":                "expanded",
+		"mixed":                 "${VAR} is expanded",
+		"bare":                  "$VAR",
+		"default":               "${VAR:fallback}",
+		"unset":                 "${UNSET}",
+		"doubled":               "$${VAR}",
+		"escaped then expanded": "$expanded",
+	}
+
+	t.Run("source", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup), StrictExpand())
+		require.NoError(t, err, "escaped references shouldn't need their variables set")
+		var got map[string]interface{}
+		require.NoError(t, p.Get(Root).Populate(&got), "couldn't populate")
+		assert.Equal(t, expect, got, "unexpected expansion")
+
+		v, err := p.Get(Root).WithDefault(map[string]string{"extra": "x"})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.Equal(t, "${VAR}", v.Get("literal").String(), "escaped reference was expanded after WithDefault")
+	})
+
+	t.Run("raw source", func(t *testing.T) {
+		p, err := NewYAML(RawSource(strings.NewReader(src)), Expand(lookup))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "$${VAR}", p.Get("literal").String(), "expected raw sources to be preserved exactly")
+		assert.Equal(t, "${VAR}", p.Get("normal").String(), "expected raw sources not to be expanded")
+	})
+
+	t.Run("ExpandString", func(t *testing.T) {
+		s, err := ExpandString(lookup, `$${VAR} is ${VAR}`)
+		require.NoError(t, err, "couldn't expand string")
+		assert.Equal(t, "${VAR} is expanded", s, "expected ExpandString to match NewYAML")
+	})
+}
+
+func TestCacheExpansion(t *testing.T) {
+	env := map[string]string{"HOST": "original-host", "PORT": "80"}
+	var (
+		lookups int
+		broken  bool
+	)
+	lookup := func(key string) (string, bool, error) {
+		lookups++
+		if key == "FLAKY" && broken {
+			return "", false, errors.New("backend unavailable")
+		}
+		v, ok := env[key]
+		return v, ok, nil
+	}
+	src := Source(strings.NewReader("host: ${HOST}\nport: ${PORT}\nname: ${NAME:default-name}"))
+
+	t.Run("derived providers reuse results", func(t *testing.T) {
+		lookups = 0
+		p, err := NewYAML(src, ExpandE(lookup), CacheExpansion())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 3, lookups, "expected each variable to be looked up once")
+
+		env["HOST"], env["NAME"] = "changed-host", "changed-name"
+		defer func() { env["HOST"] = "original-host"; delete(env, "NAME") }()
+
+		v, err := p.Get("timeout").WithDefault("${TIMEOUT:1s}")
+		require.NoError(t, err, "couldn't apply default")
+		assert.Equal(t, "original-host", v.provider.Get("host").Value(), "expected cached value after the environment changed")
+		assert.Equal(t, "default-name", v.provider.Get("name").Value(), "expected cached absence after the environment changed")
+		assert.Equal(t, "1s", v.Value(), "expected new variables to be looked up")
+		assert.Equal(t, 4, lookups, "expected only the new variable to be looked up")
+
+		patched, err := v.provider.ApplyMergePatch([]byte(`{"port": 81}`))
+		require.NoError(t, err, "couldn't apply patch")
+		_, err = patched.Get("extra").WithDefault("${HOST}")
+		require.NoError(t, err, "couldn't apply default")
+		assert.Equal(t, 4, lookups, "expected derived providers to share the cache")
+	})
+
+	t.Run("without caching", func(t *testing.T) {
+		p, err := NewYAML(src, ExpandE(lookup))
+		require.NoError(t, err, "couldn't construct provider")
+		env["HOST"] = "changed-host"
+		defer func() { env["HOST"] = "original-host" }()
+
+		v, err := p.Get("timeout").WithDefault("1s")
+		require.NoError(t, err, "couldn't apply default")
+		assert.Equal(t, "changed-host", v.provider.Get("host").Value(), "expected WithDefault to re-read the environment")
+	})
+
+	t.Run("errors aren't cached", func(t *testing.T) {
+		p, err := NewYAML(src, ExpandE(lookup), CacheExpansion())
+		require.NoError(t, err, "couldn't construct provider")
+
+		broken = true
+		_, err = p.Get("secret").WithDefault("${FLAKY:fallback}")
+		require.Error(t, err, "expected failed lookup to fail")
+		broken = false
+		v, err := p.Get("secret").WithDefault("${FLAKY:fallback}")
+		require.NoError(t, err, "expected failed lookup to be retried")
+		assert.Equal(t, "fallback", v.Value(), "unexpected value after retry")
+	})
+}
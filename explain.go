@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// _defaultOrigin describes the defaults supplied to WithDefault.
+const _defaultOrigin = "WithDefault"
+
+// An ExplainResult describes where a configuration value comes from. See
+// YAML.Explain.
+type ExplainResult struct {
+	Key   string      // the dotted path that was explained
+	Value interface{} // the merged and expanded value, as returned by Value.Value
+
+	// Source describes the highest-priority source that sets the key: its
+	// name (for example, a file's path) if it has one, "WithDefault" for
+	// defaults, or its position among the provider's sources otherwise.
+	Source string
+	// Default reports whether Source holds defaults supplied to
+	// WithDefault.
+	Default bool
+	// Sources describes every source that sets the key, including those
+	// overridden by higher-priority sources, in order of increasing
+	// priority.
+	Sources []string
+}
+
+// Explain reports the value at a key and which sources it comes from, which
+// helps operators understand merged configuration; it's the programmatic
+// equivalent of asking "where does server.port come from?". If there's no
+// configuration at the key, Explain returns a *NotFoundError.
+//
+// Sources are matched against the key before variables are expanded, so
+// keys that are only present after expansion may not be attributed to any
+// source, and values chosen by a MergeFunc are attributed to the
+// highest-priority source regardless. Providers loaded from a Snapshot have a
+// single source.
+func (y *YAML) Explain(key string) (ExplainResult, error) {
+	v, err := y.GetOrError(key)
+	if err != nil {
+		return ExplainResult{}, err
+	}
+	res := ExplainResult{Key: key, Value: v.Value()}
+	for i, raw := range y.raw {
+		var root interface{}
+		if err := yaml.NewDecoder(bytes.NewReader(raw)).Decode(&root); err == io.EOF {
+			continue
+		} else if err != nil {
+			return ExplainResult{}, fmt.Errorf("couldn't decode %s: %v", y.origins[i], err)
+		}
+		if _, ok := lookup(root, v.path, y.keyMatch); ok {
+			res.Sources = append(res.Sources, y.origins[i])
+			res.Source, res.Default = y.origins[i], y.defaults[i]
+		}
+	}
+	return res, nil
+}
+
+// describeOrigin describes a source in YAML.Explain.
+func (s source) describeOrigin(idx int) string {
+	if s.origin != "" {
+		return s.origin
+	}
+	if s.name != "" {
+		return s.name
+	}
+	return fmt.Sprintf("source at index %d", idx)
+}
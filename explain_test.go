@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	base := StaticNamed("base.yaml", map[string]interface{}{
+		"server": map[string]interface{}{"host": "localhost", "port": 80},
+		"tags":   []string{"a"},
+	})
+	prod := StaticNamed("prod.yaml", map[string]interface{}{
+		"server": map[string]interface{}{"port": 443},
+	})
+
+	t.Run("single source", func(t *testing.T) {
+		p, err := NewYAML(base, prod)
+		require.NoError(t, err, "couldn't construct provider")
+		res, err := p.Explain("server.host")
+		require.NoError(t, err, "couldn't explain key")
+		assert.Equal(t, ExplainResult{
+			Key:     "server.host",
+			Value:   "localhost",
+			Source:  "base.yaml",
+			Sources: []string{"base.yaml"},
+		}, res, "unexpected explanation")
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		p, err := NewYAML(base, prod)
+		require.NoError(t, err, "couldn't construct provider")
+		res, err := p.Explain("server.port")
+		require.NoError(t, err, "couldn't explain key")
+		assert.Equal(t, 443, res.Value, "unexpected value")
+		assert.Equal(t, "prod.yaml", res.Source, "expected highest-priority source to win")
+		assert.False(t, res.Default, "expected value not to be a default")
+		assert.Equal(t, []string{"base.yaml", "prod.yaml"}, res.Sources, "unexpected contributing sources")
+
+		res, err = p.Explain("server")
+		require.NoError(t, err, "couldn't explain mapping")
+		assert.Equal(t, []string{"base.yaml", "prod.yaml"}, res.Sources, "expected both sources to contribute to a merged mapping")
+	})
+
+	t.Run("replaced parent", func(t *testing.T) {
+		p, err := NewYAML(base, prod, Source(strings.NewReader("server: {port: 8080}")), ReplaceMaps("server"))
+		require.NoError(t, err, "couldn't construct provider")
+		res, err := p.Explain("server.port")
+		require.NoError(t, err, "couldn't explain key")
+		assert.Equal(t, "source at index 2", res.Source, "expected unnamed source to be described by index")
+		assert.Equal(t, []string{"base.yaml", "prod.yaml", "source at index 2"}, res.Sources, "expected overridden sources to be listed")
+	})
+
+	t.Run("defaulted", func(t *testing.T) {
+		p, err := NewYAML(prod)
+		require.NoError(t, err, "couldn't construct provider")
+		d, err := p.withDefault(map[string]interface{}{
+			"server": map[string]interface{}{"port": 8080, "timeout": "5s"},
+		})
+		require.NoError(t, err, "couldn't apply defaults")
+
+		res, err := d.Explain("server.timeout")
+		require.NoError(t, err, "couldn't explain default")
+		assert.Equal(t, ExplainResult{
+			Key:     "server.timeout",
+			Value:   "5s",
+			Source:  "WithDefault",
+			Default: true,
+			Sources: []string{"WithDefault"},
+		}, res, "unexpected explanation of default")
+
+		res, err = d.Explain("server.port")
+		require.NoError(t, err, "couldn't explain overridden default")
+		assert.Equal(t, "prod.yaml", res.Source, "expected source to override default")
+		assert.False(t, res.Default, "expected overridden default not to be reported as a default")
+		assert.Equal(t, []string{"WithDefault", "prod.yaml"}, res.Sources, "unexpected contributing sources")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		p, err := NewYAML(base, Source(strings.NewReader("tags: ~")))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.Explain("server.missing")
+		var nf *NotFoundError
+		assert.True(t, errors.As(err, &nf), "expected NotFoundError, got %v", err)
+
+		res, err := p.Explain("tags")
+		require.NoError(t, err, "couldn't explain explicit null")
+		assert.Nil(t, res.Value, "expected null value")
+		assert.Equal(t, "source at index 1", res.Source, "expected null to override lower-priority sequence")
+		assert.Equal(t, []string{"base.yaml", "source at index 1"}, res.Sources, "unexpected contributing sources")
+	})
+}
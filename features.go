@@ -0,0 +1,68 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+// Bool returns the Boolean at a dotted path, or fallback if the path is
+// absent, explicitly null, or holds something that doesn't populate a bool.
+// It's a best-effort convenience for optional switches, and it never fails;
+// to report malformed configuration, use Get and Populate instead.
+func (y *YAML) Bool(path string, fallback bool) bool {
+	return y.Get(path).boolOr(fallback)
+}
+
+// A FeatureSet reads feature flags from a mapping of flag names to Booleans,
+// like
+//   features: {new_ui: true, beta_search: false}
+// Flags that the mapping doesn't list, or that are set to null, use the
+// FeatureSet's default. Like Bool, a FeatureSet is best-effort: flags set to
+// anything other than a Boolean also use the default.
+type FeatureSet struct {
+	value    Value
+	fallback bool
+}
+
+// NewFeatureSet wraps the mapping held by a Value. The mapping doesn't need
+// to exist, in which case every flag uses the fallback.
+func NewFeatureSet(v Value, fallback bool) FeatureSet {
+	return FeatureSet{value: v, fallback: fallback}
+}
+
+// Enabled reports whether the named flag is enabled. The name is a single
+// mapping key, so it may contain dots.
+func (f FeatureSet) Enabled(name string) bool {
+	if f.value.provider == nil {
+		return f.fallback
+	}
+	path := make([]string, len(f.value.path), len(f.value.path)+1)
+	copy(path, f.value.path)
+	return f.value.provider.get(append(path, name)).boolOr(f.fallback)
+}
+
+func (v Value) boolOr(fallback bool) bool {
+	if set, isNull := v.IsSet(); !set || isNull {
+		return fallback
+	}
+	var b bool
+	if err := v.Populate(&b); err != nil {
+		return fallback
+	}
+	return b
+}
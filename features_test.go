@@ -0,0 +1,79 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBool(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader("server: {tls: true, debug: false, mode: fast, unset: ~}")))
+	require.NoError(t, err, "couldn't construct provider")
+
+	assert.True(t, p.Bool("server.tls", false), "expected present true")
+	assert.False(t, p.Bool("server.debug", true), "expected present false")
+	assert.True(t, p.Bool("server.missing", true), "expected fallback for absent key")
+	assert.False(t, p.Bool("server.missing", false), "expected fallback for absent key")
+	assert.True(t, p.Bool("server.unset", true), "expected fallback for explicit null")
+	assert.True(t, p.Bool("server.mode", true), "expected fallback for non-Boolean value")
+	assert.True(t, p.Bool("server", true), "expected fallback for mapping")
+}
+
+func TestFeatureSet(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader(strings.Join([]string{
+		"features:",
+		"  new_ui: true",
+		"  beta_search: false",
+		"  search.v2: true",
+		"  broken: sometimes",
+		"  cleared: ~",
+	}, "\n"))))
+	require.NoError(t, err, "couldn't construct provider")
+
+	for _, fallback := range []bool{true, false} {
+		f := NewFeatureSet(p.Get("features"), fallback)
+		assert.True(t, f.Enabled("new_ui"), "expected present-true flag to be enabled")
+		assert.False(t, f.Enabled("beta_search"), "expected present-false flag to be disabled")
+		assert.True(t, f.Enabled("search.v2"), "expected flag names to be single keys")
+		assert.Equal(t, fallback, f.Enabled("unlisted"), "expected absent flag to use the default")
+		assert.Equal(t, fallback, f.Enabled("broken"), "expected non-Boolean flag to use the default")
+		assert.Equal(t, fallback, f.Enabled("cleared"), "expected null flag to use the default")
+	}
+
+	t.Run("missing mapping", func(t *testing.T) {
+		f := NewFeatureSet(p.Get("nope"), true)
+		assert.True(t, f.Enabled("new_ui"), "expected every flag to use the default")
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		assert.False(t, FeatureSet{}.Enabled("anything"), "expected zero FeatureSet to disable every flag")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		v, err := p.Get("features").WithDefault(map[string]bool{"from_default": true})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.True(t, NewFeatureSet(v, false).Enabled("from_default"), "expected flags from defaults to be visible")
+	})
+}
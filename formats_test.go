@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestMixedFormatMerge(t *testing.T) {
+	layers := []map[string]interface{}{
+		{
+			"name":  "base",
+			"port":  80,
+			"ratio": 0.25,
+			"scale": 2.0,
+			"big":   uint64(math.MaxUint64),
+			"tiny":  1.5e-300,
+			"neg":   -7,
+			"hosts": []interface{}{"a", "b"},
+			"limits": map[string]interface{}{
+				"cpu":    1,
+				"memory": 512,
+				"nested": map[string]interface{}{"keep": true, "drop": "x"},
+			},
+			"tags": map[string]interface{}{"team": "core"},
+		},
+		{
+			"port":  8080,
+			"ratio": 1,
+			"limits": map[string]interface{}{
+				"memory": 1024.5,
+				"nested": map[string]interface{}{"drop": nil},
+			},
+			"tags": nil,
+		},
+		{
+			"name":  "top",
+			"hosts": []interface{}{map[string]interface{}{"addr": "c", "weight": 3}},
+			"limits": map[string]interface{}{
+				"disk": "10GB",
+			},
+		},
+	}
+
+	formats := map[string]func(testing.TB, interface{}) YAMLOption{
+		"yaml": func(t testing.TB, v interface{}) YAMLOption {
+			bs, err := yaml.Marshal(v)
+			require.NoError(t, err, "couldn't marshal YAML")
+			return Source(bytes.NewReader(bs))
+		},
+		"json": func(t testing.TB, v interface{}) YAMLOption {
+			bs, err := json.Marshal(v)
+			require.NoError(t, err, "couldn't marshal JSON")
+			return Source(bytes.NewReader(bs))
+		},
+		"indented json": func(t testing.TB, v interface{}) YAMLOption {
+			bs, err := json.MarshalIndent(v, "", "\t")
+			require.NoError(t, err, "couldn't marshal JSON")
+			return Source(bytes.NewReader(bs))
+		},
+		"static": func(_ testing.TB, v interface{}) YAMLOption {
+			return Static(v)
+		},
+	}
+	names := []string{"yaml", "json", "indented json", "static"}
+
+	expected := func(t testing.TB) map[string]interface{} {
+		p, err := NewYAML(Static(layers[0]), Static(layers[1]), Static(layers[2]))
+		require.NoError(t, err, "couldn't construct reference provider")
+		return p.Flatten()
+	}(t)
+	assert.Equal(t, map[string]interface{}{
+		"name":               "top",
+		"port":               8080,
+		"ratio":              1,
+		"scale":              2,
+		"big":                uint64(math.MaxUint64),
+		"tiny":               1.5e-300,
+		"neg":                -7,
+		"hosts.0.addr":       "c",
+		"hosts.0.weight":     3,
+		"limits.cpu":         1,
+		"limits.memory":      1024.5,
+		"limits.disk":        "10GB",
+		"limits.nested.keep": true,
+		"limits.nested.drop": nil,
+		"tags":               nil,
+	}, expected, "unexpected reference merge")
+
+	for _, base := range names {
+		for _, middle := range names {
+			for _, top := range names {
+				desc := fmt.Sprintf("%s+%s+%s", base, middle, top)
+				t.Run(desc, func(t *testing.T) {
+					p, err := NewYAML(
+						formats[base](t, layers[0]),
+						formats[middle](t, layers[1]),
+						formats[top](t, layers[2]),
+						Permissive(),
+					)
+					require.NoError(t, err, "couldn't construct provider")
+					assert.Equal(t, expected, p.Flatten(), "expected merge to be independent of source formats")
+
+					var cfg struct {
+						Scale float64 `yaml:"scale"`
+						Ratio float64 `yaml:"ratio"`
+						Big   uint64  `yaml:"big"`
+					}
+					require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+					assert.Equal(t, 2.0, cfg.Scale, "expected integral float to populate a float field")
+					assert.Equal(t, 1.0, cfg.Ratio, "expected integer to populate a float field")
+					assert.Equal(t, uint64(math.MaxUint64), cfg.Big, "expected large integers to survive")
+				})
+			}
+		}
+	}
+
+	t.Run("embedded JSON", func(t *testing.T) {
+		bs, err := json.Marshal(layers[0]["big"])
+		require.NoError(t, err, "couldn't marshal JSON")
+		p, err := NewYAML(Static(map[string]string{"blob": fmt.Sprintf(`{"big": %s, "scale": 2.0}`, bs)}))
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg struct {
+			Blob map[string]interface{} `yaml:"blob" format:"json"`
+		}
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, map[string]interface{}{
+			"big":   uint64(math.MaxUint64),
+			"scale": 2,
+		}, cfg.Blob, "expected embedded JSON numbers to match YAML's")
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// FriendlyErrors replaces gopkg.in/yaml.v2's terse syntax errors with
+// clearer, line-numbered explanations of common YAML mistakes. Most notably,
+// YAML forbids tabs in indentation, which otherwise produces the baffling
+//   yaml: line 2: found character that cannot start any token
+// With FriendlyErrors, provider construction instead fails with
+//   source "config.yaml": line 2: indentation contains a tab; YAML requires spaces for indentation, so replace the tab with spaces
+// Sources are only examined after they fail to parse, so valid configuration
+// (including tabs inside strings) is never rejected and costs nothing extra.
+// Line numbers count both Unix and Windows line endings. Errors that aren't
+// recognized are reported unchanged.
+func FriendlyErrors() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.friendly = true
+	})
+}
+
+// lintSources reports the first recognized mistake in any source that fails
+// to parse.
+func lintSources(srcs []source) error {
+	for i, s := range srcs {
+		if parses(s.bytes) {
+			continue
+		}
+		if line, problem := lint(s.bytes); problem != "" {
+			return fmt.Errorf("%s: line %d: %s", s.describe(i), line, problem)
+		}
+	}
+	return nil
+}
+
+// parses reports whether every document in a source decodes.
+func parses(bs []byte) bool {
+	dec := yaml.NewDecoder(bytes.NewReader(bs))
+	for {
+		var doc interface{}
+		switch err := dec.Decode(&doc); err {
+		case nil:
+		case io.EOF:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// lint looks for common YAML mistakes, returning the one-based line number
+// and a description of the first one it finds.
+func lint(bs []byte) (int, string) {
+	for i, line := range bytes.Split(bs, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+		switch {
+		case len(indent) == len(line) && bytes.IndexByte(line, '\t') >= 0:
+			return i + 1, "blank line contains a tab; YAML doesn't allow tabs in indentation, so remove the tab"
+		case bytes.IndexByte(indent, '\t') >= 0:
+			return i + 1, "indentation contains a tab; YAML requires spaces for indentation, so replace the tab with spaces"
+		case bytes.HasPrefix(line[len(indent):], []byte("-\t")):
+			return i + 1, `tab follows the sequence indicator "-"; use a space instead`
+		}
+	}
+	return 0, ""
+}
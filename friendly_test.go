@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFriendlyErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		src  string
+		err  string
+	}{
+		{
+			desc: "tab indentation",
+			src:  "server:\n\tport: 80\n",
+			err:  "line 2: indentation contains a tab; YAML requires spaces for indentation",
+		},
+		{
+			desc: "tab after spaces",
+			src:  "server:\n  \tport: 80\n",
+			err:  "line 2: indentation contains a tab",
+		},
+		{
+			desc: "tab in block scalar",
+			src:  "motd: |\n  hello\n\tworld\n",
+			err:  "line 3: indentation contains a tab",
+		},
+		{
+			desc: "blank line with tab",
+			src:  "server:\n  port: 80\t\n\t\n",
+			err:  "line 3: blank line contains a tab",
+		},
+		{
+			desc: "tab after dash",
+			src:  "hosts:\n- a\n-\tb\n",
+			err:  `line 3: tab follows the sequence indicator "-"`,
+		},
+		{
+			desc: "Windows line endings",
+			src:  "server:\r\n  port: 80\r\n\thost: a\r\n",
+			err:  "line 3: indentation contains a tab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := NewYAML(Source(strings.NewReader(tt.src)))
+			require.Error(t, err, "expected invalid YAML to fail")
+			assert.NotContains(t, err.Error(), tt.err, "expected friendly errors to be opt-in")
+
+			_, err = NewYAML(StaticNamed("base", nil), Source(strings.NewReader(tt.src)), FriendlyErrors())
+			require.Error(t, err, "expected invalid YAML to fail")
+			assert.Contains(t, err.Error(), "source at index 1: "+tt.err, "unexpected error message")
+		})
+	}
+
+	t.Run("valid tabs", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("a: \"x\ty\"\nb: c\t# comment\r\nd:\t1\n")),
+			FriendlyErrors(),
+		)
+		require.NoError(t, err, "expected valid YAML with tabs to be accepted")
+		assert.Equal(t, "x\ty", p.Get("a").Value(), "unexpected value")
+		assert.Equal(t, 1, p.Get("d").Value(), "unexpected value")
+	})
+
+	t.Run("unrecognized", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader("a: [1")), FriendlyErrors())
+		require.Error(t, err, "expected invalid YAML to fail")
+		assert.Contains(t, err.Error(), "yaml: line 1", "expected unrecognized errors to be reported unchanged")
+	})
+}
@@ -1,6 +1,6 @@
 module go.uber.org/config
 
-go 1.13
+go 1.16
 
 require (
 	github.com/stretchr/testify v1.4.0
@@ -9,4 +9,5 @@ require (
 	golang.org/x/text v0.3.2
 	golang.org/x/tools v0.0.0-20191104232314-dc038396d1f0 // indirect
 	gopkg.in/yaml.v2 v2.2.5
+	gopkg.in/yaml.v3 v3.0.1
 )
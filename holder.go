@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// A Holder keeps the most recently populated version of some configuration,
+// so that it can be swapped atomically when configuration is reloaded. Any
+// number of goroutines may call Load concurrently with Store; Load never
+// blocks.
+//
+// A typical hot-reloading service stores each new provider and loads the
+// configuration whenever it needs it:
+//   h := config.NewHolder("server", func() interface{} { return &ServerConfig{} })
+//   if err := h.Store(provider); err != nil {
+//     // The previous configuration is still in use.
+//   }
+//   cfg := h.Load().(*ServerConfig)
+type Holder struct {
+	key    string
+	target func() interface{}
+	value  atomic.Value // holds a held
+}
+
+// held gives every value stored in the atomic.Value the same concrete type,
+// as atomic.Value requires.
+type held struct {
+	target interface{}
+}
+
+// NewHolder creates a Holder for the configuration at the supplied key
+// (which may be Root). Each call to Store populates a fresh target returned
+// by newTarget, which must return a non-nil pointer; to supply defaults,
+// return a pointer to a struct with those fields already set.
+func NewHolder(key string, newTarget func() interface{}) *Holder {
+	return &Holder{key: key, target: newTarget}
+}
+
+// Store populates a fresh target from the provider and, if populating
+// succeeds, makes it the value returned by Load. If populating fails, Store
+// returns the error and Load continues to return the previous value.
+//
+// Targets are never modified once stored, so callers must not modify the
+// values returned by Load. Concurrent calls to Store are safe, but it's
+// unspecified which value wins.
+func (h *Holder) Store(p *YAML) error {
+	if p == nil {
+		return fmt.Errorf("can't populate configuration at key %q: nil provider", h.key)
+	}
+	target := h.target()
+	if err := p.Get(h.key).Populate(target); err != nil {
+		return fmt.Errorf("couldn't populate configuration at key %q: %v", h.key, err)
+	}
+	h.value.Store(held{target})
+	return nil
+}
+
+// Load returns the most recently stored target, or nil if Store has never
+// succeeded.
+func (h *Holder) Load() interface{} {
+	v, ok := h.value.Load().(held)
+	if !ok {
+		return nil
+	}
+	return v.target
+}
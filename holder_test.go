@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type holderConfig struct {
+	Name string
+	Port int
+}
+
+func TestHolder(t *testing.T) {
+	provider := func(t testing.TB, yaml string) *YAML {
+		p, err := NewYAML(Source(strings.NewReader(yaml)))
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+	newHolder := func() *Holder {
+		return NewHolder("server", func() interface{} { return &holderConfig{Port: 80} })
+	}
+
+	t.Run("store and load", func(t *testing.T) {
+		h := newHolder()
+		assert.Nil(t, h.Load(), "expected nil before first store")
+
+		require.NoError(t, h.Store(provider(t, "server: {name: a}")), "store failed")
+		first := h.Load().(*holderConfig)
+		assert.Equal(t, &holderConfig{Name: "a", Port: 80}, first, "expected defaults and configuration")
+
+		require.NoError(t, h.Store(provider(t, "server: {name: b, port: 8080}")), "store failed")
+		assert.Equal(t, &holderConfig{Name: "b", Port: 8080}, h.Load(), "expected latest configuration")
+		assert.Equal(t, &holderConfig{Name: "a", Port: 80}, first, "expected previous target to be unchanged")
+	})
+
+	t.Run("failed store", func(t *testing.T) {
+		h := newHolder()
+		require.NoError(t, h.Store(provider(t, "server: {name: a}")), "store failed")
+
+		err := h.Store(provider(t, "server: {name: b, port: not-a-number}"))
+		require.Error(t, err, "expected populate to fail")
+		assert.Contains(t, err.Error(), `couldn't populate configuration at key "server"`, "unexpected error message")
+		assert.Equal(t, &holderConfig{Name: "a", Port: 80}, h.Load(), "expected previous value to be retained")
+
+		assert.Error(t, h.Store(nil), "expected nil provider to fail")
+		assert.Equal(t, &holderConfig{Name: "a", Port: 80}, h.Load(), "expected previous value to be retained")
+	})
+
+	t.Run("concurrent loads", func(t *testing.T) {
+		const stores = 100
+		providers := make([]*YAML, stores)
+		for i := range providers {
+			providers[i] = provider(t, fmt.Sprintf("server: {name: n%d, port: %d}", i, i))
+		}
+
+		h := newHolder()
+		require.NoError(t, h.Store(providers[0]), "store failed")
+
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					cfg := h.Load().(*holderConfig)
+					// Each loaded value must be one complete, consistent store.
+					if cfg.Name != fmt.Sprintf("n%d", cfg.Port) {
+						t.Errorf("inconsistent configuration: %+v", cfg)
+						return
+					}
+				}
+			}()
+		}
+		for _, p := range providers[1:] {
+			assert.NoError(t, h.Store(p), "store failed")
+		}
+		close(done)
+		wg.Wait()
+		assert.Equal(t, &holderConfig{Name: "n99", Port: 99}, h.Load(), "expected last store to win")
+	})
+}
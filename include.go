@@ -0,0 +1,165 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const _includeKey = "__include__"
+
+// EnableIncludes lets sources pull in other files with an __include__ key at
+// the top level of the source, whose value is a file name or a list of file
+// names:
+//   # base.yaml
+//   __include__: [defaults.yaml, local/overrides.yaml]
+//   port: 8080
+// Included files are read during construction and merged in place of the
+// directive, just before the including source, so the including source takes
+// priority over everything it includes and later includes take priority over
+// earlier ones. Included files may include other files, and include cycles
+// make construction fail. The __include__ key itself is removed.
+//
+// Relative names are resolved against the directory of the including file.
+// Sources that weren't read from files on disk (including those read by FS)
+// resolve relative names against the directory set by BaseDir, if any.
+// Included files are subject to MaxSourceBytes, inherit the including
+// source's variable expansion behavior (see RawSource), and must not use
+// variables in their names, since variables are expanded only after
+// merging.
+func EnableIncludes() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.includes = true
+	})
+}
+
+// resolveIncludes replaces each source containing an include directive with
+// the sources it includes, followed by the source itself without the
+// directive.
+func resolveIncludes(srcs []source, c *config) ([]source, error) {
+	var resolved []source
+	for i, s := range srcs {
+		var stack []string
+		if s.file != "" {
+			stack = []string{filepath.Clean(s.file)}
+		}
+		expanded, err := includeAll(s, c, stack)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't resolve includes in %s: %v", s.describe(i), err)
+		}
+		resolved = append(resolved, expanded...)
+	}
+	return resolved, nil
+}
+
+func includeAll(s source, c *config, stack []string) ([]source, error) {
+	names, stripped, err := stripInclude(s.bytes, c.strict)
+	if err != nil || names == nil {
+		return []source{s}, err
+	}
+	dir := c.baseDir
+	if s.file != "" {
+		dir = filepath.Dir(s.file)
+	}
+	var out []source
+	for _, name := range names {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Clean(path)
+		for _, f := range stack {
+			if f == path {
+				return nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack, path), " -> "))
+			}
+		}
+		all, err := readFile(path, c.maxSourceBytes)
+		if errors.Is(err, errSourceTooLarge) {
+			return nil, fmt.Errorf("%q is larger than the maximum of %d bytes", path, c.maxSourceBytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		included := source{bytes: all, raw: s.raw, name: path, file: path}
+		expanded, err := includeAll(included, c, append(stack[:len(stack):len(stack)], path))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, expanded...)
+	}
+	s.bytes = stripped
+	return append(out, s), nil
+}
+
+// stripInclude finds the names in a source's include directive and returns
+// them along with the source re-serialized without the directive. Sources
+// without a directive return nil names.
+func stripInclude(bs []byte, strict bool) ([]string, []byte, error) {
+	if !bytes.Contains(bs, []byte(_includeKey)) {
+		return nil, bs, nil
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(bs))
+	dec.SetStrict(strict)
+	var contents interface{}
+	if err := dec.Decode(&contents); err == io.EOF {
+		return nil, bs, nil
+	} else if err != nil {
+		// Leave reporting invalid YAML to the merge.
+		return nil, bs, nil
+	}
+	m, ok := contents.(map[interface{}]interface{})
+	if !ok {
+		return nil, bs, nil
+	}
+	directive, ok := m[_includeKey]
+	if !ok {
+		return nil, bs, nil
+	}
+	var names []string
+	switch d := directive.(type) {
+	case string:
+		names = []string{d}
+	case []interface{}:
+		for _, n := range d {
+			name, ok := n.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid %s: expected file names, got %v", _includeKey, n)
+			}
+			names = append(names, name)
+		}
+	default:
+		return nil, nil, fmt.Errorf("invalid %s: expected a file name or a list of file names", _includeKey)
+	}
+	delete(m, _includeKey)
+	stripped, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, nil, unreachable.Wrap(fmt.Errorf("couldn't re-serialize source: %v", err))
+	}
+	return names, stripped, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludes(t *testing.T) {
+	dir := writeDir(t, map[string]string{
+		"base.yaml":            "__include__: [defaults.yaml, local/overrides.yaml]\nport: 8080\nname: base",
+		"defaults.yaml":        "port: 80\nname: defaults\nlog: {level: info}\ntimeout: 1s",
+		"local/overrides.yaml": "__include__: extra.yaml\nlog: {level: debug}",
+		"local/extra.yaml":     "log: {format: json}\ntimeout: 5s",
+		"single.yaml":          "__include__: defaults.yaml\nname: single",
+		"only.yaml":            "__include__: defaults.yaml",
+		"cycle/a.yaml":         "__include__: b.yaml\na: true",
+		"cycle/b.yaml":         "__include__: [../defaults.yaml, a.yaml]\nb: true",
+		"self.yaml":            "__include__: self.yaml",
+		"bad.yaml":             "__include__: {file: defaults.yaml}",
+		"missing.yaml":         "__include__: nope.yaml",
+	})
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	t.Run("single include", func(t *testing.T) {
+		p, err := NewYAML(File(path("single.yaml")), EnableIncludes())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "single", p.Get("name").Value(), "expected including file to take priority")
+		assert.Equal(t, 80, p.Get("port").Value(), "expected included value")
+		assert.False(t, p.Get(_includeKey).HasValue(), "expected directive to be removed")
+	})
+
+	t.Run("nested includes", func(t *testing.T) {
+		p, err := NewYAML(File(path("base.yaml")), EnableIncludes())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, map[interface{}]interface{}{
+			"port":    8080,
+			"name":    "base",
+			"log":     map[interface{}]interface{}{"level": "debug", "format": "json"},
+			"timeout": "5s",
+		}, p.Get(Root).Value(), "unexpected merged contents")
+	})
+
+	t.Run("only includes", func(t *testing.T) {
+		p, err := NewYAML(File(path("only.yaml")), EnableIncludes(), NonEmptySources())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "defaults", p.Get("name").Value(), "expected included value")
+	})
+
+	t.Run("later sources take priority", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("name: first\nextra: true")),
+			File(path("single.yaml")),
+			Source(strings.NewReader("port: 9090")),
+			EnableIncludes(),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "single", p.Get("name").Value(), "expected includes to override earlier sources")
+		assert.Equal(t, true, p.Get("extra").Value(), "expected earlier source to remain")
+		assert.Equal(t, 9090, p.Get("port").Value(), "expected later source to override includes")
+	})
+
+	t.Run("reader sources use BaseDir", func(t *testing.T) {
+		p, err := NewYAML(BaseDir(dir), Source(strings.NewReader("__include__: defaults.yaml")), EnableIncludes())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "defaults", p.Get("name").Value(), "expected include relative to BaseDir")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p, err := NewYAML(File(path("single.yaml")))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "defaults.yaml", p.Get(_includeKey).Value(), "expected directive to be ordinary configuration")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			file string
+			err  string
+		}{
+			{"cycle/a.yaml", "include cycle: " + strings.Join([]string{
+				path("cycle/a.yaml"), path("cycle/b.yaml"), path("cycle/a.yaml"),
+			}, " -> ")},
+			{"self.yaml", "include cycle: " + path("self.yaml") + " -> " + path("self.yaml")},
+			{"bad.yaml", "expected a file name or a list of file names"},
+			{"missing.yaml", "nope.yaml"},
+		}
+		for _, tt := range tests {
+			_, err := NewYAML(File(path(tt.file)), EnableIncludes())
+			require.Error(t, err, "expected %s to fail", tt.file)
+			assert.Contains(t, err.Error(), "couldn't resolve includes in source", "unexpected error for %s", tt.file)
+			assert.Contains(t, err.Error(), tt.err, "unexpected error for %s", tt.file)
+		}
+
+		_, err := NewYAML(File(path("single.yaml")), EnableIncludes(), MaxSourceBytes(40))
+		require.Error(t, err, "expected oversized include to fail")
+		assert.Contains(t, err.Error(), "larger than the maximum of 40 bytes", "unexpected error message")
+	})
+}
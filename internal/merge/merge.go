@@ -24,6 +24,8 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"go.uber.org/config/internal/unreachable"
 
@@ -54,7 +56,11 @@ type (
 // value with the new.
 //
 // Enabling strict mode returns errors in both of the above cases.
-func YAML(sources [][]byte, strict bool) (*bytes.Buffer, error) {
+func YAML(sources [][]byte, strict bool, opts ...Option) (*bytes.Buffer, error) {
+	m := &merger{strict: strict}
+	for _, o := range opts {
+		o.apply(m)
+	}
 	var merged interface{}
 	var hasContent bool
 	for _, r := range sources {
@@ -70,8 +76,19 @@ func YAML(sources [][]byte, strict bool) (*bytes.Buffer, error) {
 			return nil, fmt.Errorf("couldn't decode source: %v", err)
 		}
 
+		if m.normalize != nil {
+			var err error
+			if contents, err = m.normalizeKeys(r); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := m.checkLimits(contents, nil /* path */); err != nil {
+			return nil, err
+		}
+
 		hasContent = true
-		pair, err := merge(merged, contents, strict)
+		pair, err := m.merge(merged, contents, nil /* path */)
 		if err != nil {
 			return nil, err // error is already descriptive enough
 		}
@@ -91,7 +108,210 @@ func YAML(sources [][]byte, strict bool) (*bytes.Buffer, error) {
 	return buf, nil
 }
 
+// An Option customizes how YAML merges sources.
+type Option interface {
+	apply(*merger)
+}
+
+type optionFunc func(*merger)
+
+func (f optionFunc) apply(m *merger) { f(m) }
+
+// ReplaceMappings makes mappings at the supplied paths replace lower-priority
+// mappings entirely, rather than deep-merging with them. Each path is a
+// sequence of mapping keys, which are compared to the keys in the sources
+// using their fmt.Sprint representation; an empty path addresses the root.
+func ReplaceMappings(paths ...[]string) Option {
+	return optionFunc(func(m *merger) {
+		m.replace = append(m.replace, paths...)
+	})
+}
+
+// MaxDepth limits how deeply mappings and sequences may nest in each source:
+// a source fails to merge if any value is more than max keys and indices
+// below the root. Checking each source before merging bounds the recursion
+// of the merge itself and of anything that later walks the merged result. A
+// non-positive max disables the limit.
+func MaxDepth(max int) Option {
+	return optionFunc(func(m *merger) {
+		m.maxDepth = max
+	})
+}
+
+// MaxSequenceLen limits the number of elements in each sequence in each
+// source: a source fails to merge if any sequence has more than max
+// elements. A non-positive max disables the limit.
+func MaxSequenceLen(max int) Option {
+	return optionFunc(func(m *merger) {
+		m.maxSeqLen = max
+	})
+}
+
+// MergeFunc lets the caller decide how conflicts are resolved. A conflict
+// occurs wherever a lower-priority and a higher-priority source both set a
+// non-null value, unless both values are mappings that would be deep-merged:
+// that is, for pairs of scalars, pairs of sequences, mappings replaced by
+// ReplaceMappings, and values of mismatched types. At each conflict, f
+// receives the path to the conflicting value (as in ReplaceMappings) and
+// both values. If f reports true, its result becomes the merged value;
+// otherwise, the conflict is resolved as usual. The path must not be
+// retained or modified.
+func MergeFunc(f func(path []string, lower, higher interface{}) (interface{}, bool)) Option {
+	return optionFunc(func(m *merger) {
+		m.resolve = f
+	})
+}
+
+// NormalizeKeys canonicalizes the string keys of every mapping in each
+// source with f, for example to merge keys that differ only in case. Keys are
+// normalized before sources are merged, so paths seen by ReplaceMappings and
+// MergeFunc are normalized too. Keys that aren't strings are left alone. If
+// normalizing makes two keys in the same mapping equal, strict mode reports
+// an error; otherwise, the key that appears later in the source wins, just as
+// for duplicate keys.
+func NormalizeKeys(f func(string) string) Option {
+	return optionFunc(func(m *merger) {
+		m.normalize = f
+	})
+}
+
+type merger struct {
+	strict    bool
+	replace   [][]string
+	maxDepth  int
+	maxSeqLen int
+	resolve   func([]string, interface{}, interface{}) (interface{}, bool)
+	normalize func(string) string
+}
+
+// normalizeKeys implements NormalizeKeys. Since unmarshaling into a map
+// discards the order of keys, it re-decodes the source preserving order.
+func (m *merger) normalizeKeys(source []byte) (interface{}, error) {
+	var ordered orderedNode
+	if err := yaml.Unmarshal(source, &ordered); err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't re-decode source: %v", err))
+	}
+	return m.normalizeNode(ordered.value, nil /* path */)
+}
+
+func (m *merger) normalizeNode(node interface{}, path []string) (interface{}, error) {
+	switch n := node.(type) {
+	case yaml.MapSlice:
+		normalized := make(mapping, len(n))
+		for _, item := range n {
+			k := item.Key
+			if s, ok := k.(string); ok {
+				k = m.normalize(s)
+				if _, dup := normalized[k]; dup && m.strict {
+					return nil, fmt.Errorf(
+						"duplicate key %q after normalizing keys at key %q",
+						k, strings.Join(path, "."),
+					)
+				}
+			}
+			v, err := m.normalizeNode(item.Value, append(path[:len(path):len(path)], fmt.Sprint(k)))
+			if err != nil {
+				return nil, err
+			}
+			normalized[k] = v
+		}
+		return normalized, nil
+	case []interface{}:
+		normalized := make(sequence, len(n))
+		for i, elem := range n {
+			v, err := m.normalizeNode(elem, append(path[:len(path):len(path)], strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = v
+		}
+		return normalized, nil
+	case []orderedNode:
+		normalized := make(sequence, len(n))
+		for i, elem := range n {
+			v, err := m.normalizeNode(elem.value, append(path[:len(path):len(path)], strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			normalized[i] = v
+		}
+		return normalized, nil
+	}
+	return node, nil
+}
+
+// orderedNode unmarshals YAML like interface{} does, except that mappings
+// become yaml.MapSlices, which preserve the order of keys.
+type orderedNode struct {
+	value interface{}
+}
+
+func (o *orderedNode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&o.value); err != nil {
+		return err
+	}
+	switch o.value.(type) {
+	case mapping:
+		var ms yaml.MapSlice
+		if err := unmarshal(&ms); err != nil {
+			return err
+		}
+		o.value = ms
+	case sequence:
+		var seq []orderedNode
+		if err := unmarshal(&seq); err != nil {
+			return err
+		}
+		o.value = seq
+	}
+	return nil
+}
+
+// checkLimits enforces MaxDepth and MaxSequenceLen, reporting the dotted
+// path to the first value nested too deeply or the first sequence that's too
+// long.
+func (m *merger) checkLimits(node interface{}, path []string) error {
+	if m.maxDepth <= 0 && m.maxSeqLen <= 0 {
+		return nil
+	}
+	if m.maxDepth > 0 && len(path) > m.maxDepth {
+		return fmt.Errorf(
+			"value at key %q exceeds the maximum nesting depth of %d",
+			strings.Join(path, "."),
+			m.maxDepth,
+		)
+	}
+	switch n := node.(type) {
+	case mapping:
+		for k, v := range n {
+			if err := m.checkLimits(v, append(path[:len(path):len(path)], fmt.Sprint(k))); err != nil {
+				return err
+			}
+		}
+	case sequence:
+		if m.maxSeqLen > 0 && len(n) > m.maxSeqLen {
+			return fmt.Errorf(
+				"sequence at key %q has %d elements, exceeding the maximum of %d",
+				strings.Join(path, "."),
+				len(n),
+				m.maxSeqLen,
+			)
+		}
+		for i, v := range n {
+			if err := m.checkLimits(v, append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func merge(into, from interface{}, strict bool) (interface{}, error) {
+	return (&merger{strict: strict}).merge(into, from, nil /* path */)
+}
+
+func (m *merger) merge(into, from interface{}, path []string) (interface{}, error) {
+	strict := m.strict
 	// It's possible to handle this with a mass of reflection, but we only need
 	// to merge whole YAML files. Since we're always unmarshaling into
 	// interface{}, we only need to handle a few types. This ends up being
@@ -103,6 +323,11 @@ func merge(into, from interface{}, strict bool) (interface{}, error) {
 		// Allow higher-priority YAML to explicitly nil out lower-priority entries.
 		return nil, nil
 	}
+	if m.resolve != nil && !(IsMapping(into) && IsMapping(from) && !m.replaces(path)) {
+		if v, ok := m.resolve(path, into, from); ok {
+			return v, nil
+		}
+	}
 	if IsScalar(into) && IsScalar(from) {
 		return from, nil
 	}
@@ -110,7 +335,10 @@ func merge(into, from interface{}, strict bool) (interface{}, error) {
 		return from, nil
 	}
 	if IsMapping(into) && IsMapping(from) {
-		return mergeMapping(into.(mapping), from.(mapping), strict)
+		if m.replaces(path) {
+			return from, nil
+		}
+		return m.mergeMapping(into.(mapping), from.(mapping), path)
 	}
 	// YAML types don't match, so no merge is possible. For backward
 	// compatibility, ignore mismatches unless we're in strict mode and return
@@ -121,21 +349,49 @@ func merge(into, from interface{}, strict bool) (interface{}, error) {
 	return nil, fmt.Errorf("can't merge a %s into a %s", describe(from), describe(into))
 }
 
-func mergeMapping(into, from mapping, strict bool) (mapping, error) {
+func (m *merger) mergeMapping(into, from mapping, path []string) (mapping, error) {
 	merged := make(mapping, len(into))
 	for k, v := range into {
 		merged[k] = v
 	}
 	for k := range from {
-		m, err := merge(merged[k], from[k], strict)
+		var child []string
+		if len(m.replace) > 0 || m.resolve != nil {
+			// Only track paths if some option needs them.
+			child = append(path[:len(path):len(path)], fmt.Sprint(k))
+		}
+		v, err := m.merge(merged[k], from[k], child)
 		if err != nil {
 			return nil, err
 		}
-		merged[k] = m
+		merged[k] = v
 	}
 	return merged, nil
 }
 
+// replaces reports whether the mapping at path should replace, rather than
+// merge with, lower-priority mappings.
+func (m *merger) replaces(path []string) bool {
+	for _, r := range m.replace {
+		if equal(r, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // IsMapping reports whether a type is a mapping in YAML, represented as a
 // map[interface{}]interface{}.
 func IsMapping(i interface{}) bool {
@@ -23,6 +23,7 @@ package merge
 import (
 	"bytes"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"testing"
 
@@ -229,3 +230,183 @@ func TestExplicitNil(t *testing.T) {
 	succeeds(t, true, base, override, expect)
 	succeeds(t, false, base, override, expect)
 }
+
+func TestReplaceMappings(t *testing.T) {
+	base := []byte("labels: {team: core, tier: 1}\nmeta: {owner: ops, region: us}\nlevels: {1: {a: b}}")
+	override := []byte("labels: {team: edge}\nmeta: {owner: dev}\nlevels: {1: {c: d}}")
+
+	tests := []struct {
+		desc   string
+		opts   []Option
+		expect string
+	}{
+		{
+			desc:   "deep merge by default",
+			expect: "labels: {team: edge, tier: 1}\nmeta: {owner: dev, region: us}\nlevels: {1: {a: b, c: d}}",
+		},
+		{
+			desc:   "replace labels",
+			opts:   []Option{ReplaceMappings([]string{"labels"})},
+			expect: "labels: {team: edge}\nmeta: {owner: dev, region: us}\nlevels: {1: {a: b, c: d}}",
+		},
+		{
+			desc:   "non-string keys",
+			opts:   []Option{ReplaceMappings([]string{"levels", "1"})},
+			expect: "labels: {team: edge, tier: 1}\nmeta: {owner: dev, region: us}\nlevels: {1: {c: d}}",
+		},
+		{
+			desc:   "root",
+			opts:   []Option{ReplaceMappings(nil)},
+			expect: string(override),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			merged, err := YAML([][]byte{base, override}, true /* strict */, tt.opts...)
+			require.NoError(t, err, "merge failed")
+			assert.Equal(t, canonicalize(t, tt.expect), canonicalize(t, merged.String()), "unexpected merged contents")
+		})
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	sources := [][]byte{
+		[]byte("a: {b: 1}"),
+		[]byte("a: {c: [x, {d: 2}]}"),
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		merged, err := YAML(sources, true /* strict */, MaxDepth(4))
+		require.NoError(t, err, "merge failed")
+		assert.Equal(t, canonicalize(t, "a: {b: 1, c: [x, {d: 2}]}"), canonicalize(t, merged.String()), "unexpected merged contents")
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		_, err := YAML(sources, true /* strict */, MaxDepth(3))
+		require.Error(t, err, "expected merge to fail")
+		assert.Contains(t, err.Error(), `value at key "a.c.1.d" exceeds the maximum nesting depth of 3`, "unexpected error message")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := YAML(sources, true /* strict */, MaxDepth(0))
+		require.NoError(t, err, "merge failed")
+	})
+}
+
+func TestMaxSequenceLen(t *testing.T) {
+	sources := [][]byte{
+		[]byte("a: [1, 2]"),
+		[]byte("b: {c: [1, 2, 3]}"),
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		merged, err := YAML(sources, true /* strict */, MaxSequenceLen(3))
+		require.NoError(t, err, "merge failed")
+		assert.Equal(t, canonicalize(t, "{a: [1, 2], b: {c: [1, 2, 3]}}"), canonicalize(t, merged.String()), "unexpected merged contents")
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		_, err := YAML(sources, true /* strict */, MaxSequenceLen(2))
+		require.Error(t, err, "expected merge to fail")
+		assert.Contains(t, err.Error(), `sequence at key "b.c" has 3 elements, exceeding the maximum of 2`, "unexpected error message")
+	})
+
+	t.Run("combined with depth", func(t *testing.T) {
+		_, err := YAML(sources, true /* strict */, MaxSequenceLen(3), MaxDepth(1))
+		require.Error(t, err, "expected merge to fail")
+		assert.Contains(t, err.Error(), "maximum nesting depth", "unexpected error message")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := YAML(sources, true /* strict */, MaxSequenceLen(0))
+		require.NoError(t, err, "merge failed")
+	})
+}
+
+func TestMergeFunc(t *testing.T) {
+	var conflicts []string
+	maxWins := func(path []string, lower, higher interface{}) (interface{}, bool) {
+		conflicts = append(conflicts, strings.Join(path, "."))
+		l, lok := lower.(int)
+		h, hok := higher.(int)
+		if !lok || !hok {
+			return nil, false
+		}
+		if l > h {
+			return l, true
+		}
+		return h, true
+	}
+
+	sources := [][]byte{
+		[]byte("limits: {cpu: 8, memory: 512}\nname: base\nonly: low\nhosts: [a]"),
+		[]byte("limits: {cpu: 4, memory: 1024, disk: 10}\nname: override\nhosts: [b]\nextra: high"),
+	}
+	merged, err := YAML(sources, true /* strict */, MergeFunc(maxWins))
+	require.NoError(t, err, "merge failed")
+	assert.Equal(
+		t,
+		canonicalize(t, "limits: {cpu: 8, memory: 1024, disk: 10}\nname: override\nonly: low\nhosts: [b]\nextra: high"),
+		canonicalize(t, merged.String()),
+		"unexpected merged contents",
+	)
+	sort.Strings(conflicts)
+	assert.Equal(t, []string{"hosts", "limits.cpu", "limits.memory", "name"}, conflicts, "resolver should only see conflicting paths")
+
+	t.Run("mismatched types", func(t *testing.T) {
+		keepMapping := func(_ []string, lower, _ interface{}) (interface{}, bool) {
+			return lower, IsMapping(lower)
+		}
+		merged, err := YAML([][]byte{
+			[]byte("a: {b: 1}"),
+			[]byte("a: 2"),
+		}, true /* strict */, MergeFunc(keepMapping))
+		require.NoError(t, err, "expected resolver to settle mismatched types")
+		assert.Equal(t, canonicalize(t, "a: {b: 1}"), canonicalize(t, merged.String()), "unexpected merged contents")
+
+		_, err = YAML([][]byte{
+			[]byte("a: 1"),
+			[]byte("a: {b: 2}"),
+		}, true /* strict */, MergeFunc(keepMapping))
+		assert.Error(t, err, "expected declined conflict to fail in strict mode")
+	})
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	sources := [][]byte{
+		[]byte("Server: {Port: 80, Hosts: [{Name: a}]}\n1: one"),
+		[]byte("server: {port: 8080, TLS: {Enabled: true}}"),
+		[]byte("SERVER: {name: api}"),
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		merged, err := YAML(sources, false /* strict */, NormalizeKeys(strings.ToLower))
+		require.NoError(t, err, "merge failed")
+		assert.Equal(
+			t,
+			canonicalize(t, "server: {port: 8080, hosts: [{name: a}], tls: {enabled: true}, name: api}\n1: one"),
+			canonicalize(t, merged.String()),
+			"expected equivalent keys to merge into one branch",
+		)
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		merged, err := YAML([][]byte{[]byte("Key: first\nkey: second\nKEY: third")}, false /* strict */, NormalizeKeys(strings.ToLower))
+		require.NoError(t, err, "merge failed")
+		assert.Equal(t, canonicalize(t, "key: third"), canonicalize(t, merged.String()), "expected last key to win")
+
+		_, err = YAML([][]byte{[]byte("a: {Key: 1, key: 2}")}, true /* strict */, NormalizeKeys(strings.ToLower))
+		require.Error(t, err, "expected collision to fail in strict mode")
+		assert.Contains(t, err.Error(), `duplicate key "key" after normalizing keys at key "a"`, "unexpected error message")
+
+		_, err = YAML(sources, true /* strict */, NormalizeKeys(strings.ToLower))
+		assert.NoError(t, err, "expected keys in different sources not to collide")
+	})
+
+	t.Run("null source", func(t *testing.T) {
+		merged, err := YAML([][]byte{[]byte("a: 1"), []byte("~")}, true /* strict */, NormalizeKeys(strings.ToLower))
+		require.NoError(t, err, "merge failed")
+		assert.Equal(t, canonicalize(t, "~"), canonicalize(t, merged.String()), "expected explicit null to remain null")
+	})
+}
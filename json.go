@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// _formatTagName is the struct tag that marks fields whose configuration
+// is written in an alternate form: "json" for JSON embedded in a string, or
+// "tuple" for structs written as sequences. See the package documentation.
+const _formatTagName = "format"
+
+// decodeJSONString replaces a string holding JSON with the equivalent
+// unmarshaled YAML.
+func decodeJSONString(node interface{}, path []string) (interface{}, error) {
+	s, ok := node.(string)
+	if !ok {
+		return node, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var val interface{}
+	if err := dec.Decode(&val); err != nil {
+		return nil, fmt.Errorf("couldn't populate key %q: invalid JSON: %v", strings.Join(path, _separator), err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("couldn't populate key %q: invalid JSON: unexpected data after top-level value", strings.Join(path, _separator))
+	}
+	return fromJSON(val), nil
+}
+
+// fromJSON converts JSON decoded with json.Decoder.UseNumber into the types
+// gopkg.in/yaml.v2 produces.
+func fromJSON(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for k, child := range v {
+			m[k] = fromJSON(child)
+		}
+		return m
+	case []interface{}:
+		seq := make([]interface{}, len(v))
+		for i, child := range v {
+			seq[i] = fromJSON(child)
+		}
+		return seq
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			if int64(int(i)) == i {
+				return int(i)
+			}
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return val
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopulateJSONString(t *testing.T) {
+	type extra struct {
+		Retries int      `yaml:"retries"`
+		Ratio   float64  `yaml:"ratio"`
+		Hosts   []string `yaml:"hosts"`
+		Nested  struct {
+			On bool `yaml:"on"`
+		} `yaml:"nested"`
+	}
+	type cfg struct {
+		Name  string                 `yaml:"name"`
+		Extra extra                  `yaml:"extra" format:"json"`
+		Blobs []map[string]int       `yaml:"blobs"`
+		Any   map[string]interface{} `yaml:"any" format:"json"`
+	}
+	populate := func(t testing.TB, src string) (cfg, error) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		err = p.Get(Root).Populate(&c)
+		return c, err
+	}
+
+	t.Run("nested struct", func(t *testing.T) {
+		c, err := populate(t, `
+name: api
+extra: '{"retries": 3, "ratio": 0.5, "hosts": ["a", "b"], "nested": {"on": true}}'
+any: '{"big": 12345678901234, "list": [1, "x"]}'
+`)
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, "api", c.Name, "unexpected name")
+		assert.Equal(t, 3, c.Extra.Retries, "unexpected integer from JSON")
+		assert.Equal(t, 0.5, c.Extra.Ratio, "unexpected float from JSON")
+		assert.Equal(t, []string{"a", "b"}, c.Extra.Hosts, "unexpected array from JSON")
+		assert.True(t, c.Extra.Nested.On, "unexpected nested object from JSON")
+		assert.Equal(t, map[string]interface{}{
+			"big":  12345678901234,
+			"list": []interface{}{1, "x"},
+		}, c.Any, "unexpected generic JSON")
+	})
+
+	t.Run("plain YAML", func(t *testing.T) {
+		c, err := populate(t, "extra: {retries: 4}")
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, 4, c.Extra.Retries, "expected YAML mappings to populate tagged fields too")
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		_, err := populate(t, `extra: '{"retries": 3,'`)
+		require.Error(t, err, "expected malformed JSON to fail")
+		assert.Contains(t, err.Error(), `couldn't populate key "extra": invalid JSON`, "expected error to name the key")
+
+		_, err = populate(t, `extra: '{"retries": 3} {}'`)
+		require.Error(t, err, "expected trailing data to fail")
+		assert.Contains(t, err.Error(), "unexpected data after top-level value", "unexpected error message")
+	})
+
+	t.Run("invalid contents", func(t *testing.T) {
+		_, err := populate(t, `extra: '{"retries": "many"}'`)
+		require.Error(t, err, "expected mismatched JSON to fail")
+		assert.Contains(t, err.Error(), `couldn't populate key "extra.retries"`, "expected error to name the nested key")
+
+		_, err = populate(t, `extra: '{"unknown": 1}'`)
+		require.Error(t, err, "expected strict mode to reject unknown JSON keys")
+		assert.Contains(t, err.Error(), "field unknown not found", "unexpected error message")
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+
+	"go.uber.org/config/internal/merge"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A KeyMatchMode controls how the segments of a dotted path are matched
+// against the keys of a YAML mapping. It matters only for mappings with
+// non-string keys: since paths are always strings, a segment like "10" could
+// address either the integer key 10 or the string key "10".
+type KeyMatchMode int
+
+const (
+	// PreferString matches a segment against string keys first. If there's
+	// no such string key, the segment is parsed as a YAML scalar and matched
+	// against keys of the resulting type (integers, Booleans, and so on). This
+	// is the default.
+	PreferString KeyMatchMode = iota
+	// StringOnly matches segments against string keys only, so keys of other
+	// types are unreachable.
+	StringOnly
+	// ScalarOnly parses each segment as a YAML scalar and matches only keys
+	// of the resulting type. Plain words still match string keys, but "10"
+	// matches only the integer key 10 and "true" only the Boolean key true.
+	ScalarOnly
+)
+
+func (m KeyMatchMode) String() string {
+	switch m {
+	case PreferString:
+		return "PreferString"
+	case StringOnly:
+		return "StringOnly"
+	case ScalarOnly:
+		return "ScalarOnly"
+	default:
+		return fmt.Sprintf("KeyMatchMode(%d)", int(m))
+	}
+}
+
+// KeyMatch sets the provider's KeyMatchMode, which controls how Get and
+// Verbatim paths are matched against mapping keys.
+func KeyMatch(mode KeyMatchMode) YAMLOption {
+	switch mode {
+	case PreferString, StringOnly, ScalarOnly:
+	default:
+		return failed(fmt.Errorf("unknown key match mode %v", mode))
+	}
+	return optionFunc(func(c *config) {
+		c.keyMatch = mode
+	})
+}
+
+// resolve finds the key in a mapping addressed by a path segment.
+func (m KeyMatchMode) resolve(mapping map[interface{}]interface{}, segment string) (interface{}, bool) {
+	if m != ScalarOnly {
+		if _, ok := mapping[segment]; ok {
+			return segment, true
+		}
+	}
+	if m == StringOnly {
+		return nil, false
+	}
+	//尝试将段解析为字符串，然后为可比较的键解组路径段。
+	//毕竟，YAML标量类型不仅仅是字符串（boolean、integer等）。我们希望使用字符串形式来解析不明确的路径。
+	var key interface{}
+	if err := yaml.Unmarshal([]byte(segment), &key); err != nil {
+		return nil, false
+	}
+	if !merge.IsScalar(key) {
+		return nil, false
+	}
+	if _, ok := mapping[key]; !ok {
+		return nil, false
+	}
+	return key, true
+}
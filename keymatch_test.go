@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyMatch(t *testing.T) {
+	const src = `
+ambiguous:
+  10: int
+  "10": string
+ints:
+  10: int
+strings:
+  "10": string
+bools:
+  true: bool
+words:
+  foo: bar
+`
+	tests := []struct {
+		mode KeyMatchMode
+		want map[string]interface{} // nil means no value
+	}{
+		{
+			mode: PreferString,
+			want: map[string]interface{}{
+				"ambiguous.10": "string",
+				"ints.10":      "int",
+				"strings.10":   "string",
+				"bools.true":   "bool",
+				"words.foo":    "bar",
+			},
+		},
+		{
+			mode: StringOnly,
+			want: map[string]interface{}{
+				"ambiguous.10": "string",
+				"ints.10":      nil,
+				"strings.10":   "string",
+				"bools.true":   nil,
+				"words.foo":    "bar",
+			},
+		},
+		{
+			mode: ScalarOnly,
+			want: map[string]interface{}{
+				"ambiguous.10": "int",
+				"ints.10":      "int",
+				"strings.10":   nil,
+				"bools.true":   "bool",
+				"words.foo":    "bar",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			p, err := NewYAML(Source(strings.NewReader(src)), KeyMatch(tt.mode))
+			require.NoError(t, err, "couldn't construct provider")
+			for key, want := range tt.want {
+				assert.Equal(t, want, p.Get(key).Value(), "unexpected value at key %q", key)
+			}
+
+			withDefault, err := p.Get(Root).WithDefault(map[string]string{"extra": "default"})
+			require.NoError(t, err, "couldn't add default")
+			for key, want := range tt.want {
+				assert.Equal(t, want, withDefault.Get(key).Value(), "unexpected value at key %q after WithDefault", key)
+			}
+		})
+	}
+
+	t.Run("verbatim", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("ids:\n  10: $int\n  \"10\": $string")),
+			KeyMatch(ScalarOnly),
+			Verbatim("ids.10"),
+			Expand(func(string) (string, bool) { return "expanded", true }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "$int", p.Get("ids.10").Value(), "verbatim path should follow key match mode")
+		assert.Equal(t, map[interface{}]interface{}{
+			10:   "$int",
+			"10": "expanded",
+		}, p.Get("ids").Value(), "only the integer key should be verbatim")
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := NewYAML(Static("foo"), KeyMatch(KeyMatchMode(42)))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), "unknown key match mode KeyMatchMode(42)", "unexpected error message")
+	})
+}
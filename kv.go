@@ -0,0 +1,85 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A KVLister lists the keys in a key-value store, like Consul or etcd, that
+// start with a prefix. List returns each key in full, including the prefix,
+// with its value.
+type KVLister interface {
+	List(prefix string) (map[string][]byte, error)
+}
+
+// KVSource adds the keys under a prefix in a key-value store as a source.
+// The prefix is removed from each key, and the rest is split on slashes to
+// form a path, so a store holding
+//   service/redis/host: localhost
+//   service/redis/port: 6379
+// under the prefix "service/" contributes the same configuration as the
+// YAML
+//   redis:
+//     host: localhost
+//     port: 6379
+// As with SetSource, each value is interpreted as a YAML scalar, and integer
+// segments address sequence elements. Keys ending in a slash, which some
+// stores use to represent folders, are ignored.
+//
+// The store is listed once, when the provider is constructed. Construction
+// fails if listing the keys fails or if two keys conflict.
+func KVSource(kv KVLister, prefix string) YAMLOption {
+	if kv == nil {
+		return failed(errors.New("key-value lister must not be nil"))
+	}
+	name := fmt.Sprintf("KV prefix %q", prefix)
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{name: name, load: func(int64) ([]source, error) {
+			pairs, err := kv.List(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't list keys with prefix %q: %v", prefix, err)
+			}
+			settings := make([]setting, 0, len(pairs))
+			for key, value := range pairs {
+				rel := strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/")
+				if rel == "" || strings.HasSuffix(rel, "/") {
+					continue
+				}
+				settings = append(settings, setting{
+					name:  key,
+					path:  strings.Split(rel, "/"),
+					value: string(value),
+				})
+			}
+			// Maps have no order, so sort the keys to make sequences and
+			// conflicts deterministic.
+			sort.Slice(settings, func(i, j int) bool {
+				return lessPath(settings[i].path, settings[j].path)
+			})
+			bs, err := settingsYAML(settings, "invalid key %q")
+			return []source{{bytes: bs, raw: true, name: name}}, err
+		}})
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKV struct {
+	pairs map[string]string
+	err   error
+	calls int
+}
+
+func (kv *fakeKV) List(prefix string) (map[string][]byte, error) {
+	kv.calls++
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	listed := make(map[string][]byte)
+	for key, value := range kv.pairs {
+		if strings.HasPrefix(key, prefix) {
+			listed[key] = []byte(value)
+		}
+	}
+	return listed, nil
+}
+
+func TestKVSource(t *testing.T) {
+	t.Run("nested keys", func(t *testing.T) {
+		kv := &fakeKV{pairs: map[string]string{
+			"service/":           "",
+			"service/redis/":     "",
+			"service/redis/host": "kv-host",
+			"service/redis/port": "6380",
+			"service/peers/0":    "alpha",
+			"service/peers/1":    "beta",
+			"service/peers/2":    "gamma",
+			"service/debug":      "true",
+			"other/redis/host":   "ignored",
+		}}
+		file := Source(strings.NewReader("redis: {host: file-host, db: 3}"))
+		p, err := NewYAML(file, KVSource(kv, "service/"))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 1, kv.calls, "expected keys to be listed once")
+
+		var cfg struct {
+			Redis struct {
+				Host string
+				Port int
+				DB   int
+			}
+			Peers []string
+			Debug bool
+		}
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, "kv-host", cfg.Redis.Host, "expected KV store to override file")
+		assert.Equal(t, 6380, cfg.Redis.Port, "expected values to be interpreted as YAML scalars")
+		assert.Equal(t, 3, cfg.Redis.DB, "expected keys missing from the KV store to come from file")
+		assert.Equal(t, []string{"alpha", "beta", "gamma"}, cfg.Peers, "expected integer segments to form a sequence")
+		assert.True(t, cfg.Debug, "expected Boolean value")
+	})
+
+	t.Run("prefix without trailing slash", func(t *testing.T) {
+		kv := &fakeKV{pairs: map[string]string{"app/name": "kv"}}
+		p, err := NewYAML(KVSource(kv, "app"))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "kv", p.Get("name").Value(), "expected leading slash to be trimmed")
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		kv := &fakeKV{}
+		p, err := NewYAML(KVSource(kv, "missing/"))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.False(t, p.Get(Root).HasValue(), "expected no configuration from an empty prefix")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewYAML(KVSource(nil, "service/"))
+		require.Error(t, err, "expected nil lister to fail")
+		assert.Contains(t, err.Error(), "must not be nil", "unexpected error message")
+
+		_, err = NewYAML(KVSource(&fakeKV{err: errors.New("connection refused")}, "service/"))
+		require.Error(t, err, "expected listing failure to abort construction")
+		assert.Contains(t, err.Error(), `couldn't list keys with prefix "service/": connection refused`, "unexpected error message")
+
+		kv := &fakeKV{pairs: map[string]string{
+			"service/redis":      "localhost",
+			"service/redis/port": "6379",
+		}}
+		_, err = NewYAML(KVSource(kv, "service/"))
+		require.Error(t, err, "expected conflicting keys to fail")
+		assert.Contains(t, err.Error(), `invalid key "service/redis/port"`, "unexpected error message")
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to the provider's
+// merged, expanded configuration and returns a new provider holding the
+// result. The receiver is unchanged. Following RFC 7386, objects in the patch
+// are merged into the configuration key by key, null values delete keys, and
+// everything else, including arrays, replaces the configuration it's applied
+// to. Unlike the merging of sources described in the package documentation,
+// patching can remove configuration, and it never merges sequences.
+//
+// A patch of null removes all configuration, but an empty patch is an error.
+// The patch is decoded as YAML, of which JSON is a subset, and its keys match
+// configuration keys as they do in Get. Variables in the patch aren't
+// expanded. The new provider keeps the receiver's options, but it has a
+// single source holding the patched configuration, so Explain attributes all
+// of its configuration to the patch. Like WithDefault, ApplyMergePatch
+// returns an error for sealed providers (see Seal).
+func (y *YAML) ApplyMergePatch(patch []byte) (*YAML, error) {
+	if y.sealed {
+		return nil, fmt.Errorf("can't apply merge patch to provider %s: provider is sealed", y.name)
+	}
+	if len(bytes.TrimSpace(patch)) == 0 {
+		return nil, errors.New("merge patch is empty")
+	}
+	var p interface{}
+	if err := yaml.UnmarshalStrict(patch, &p); err != nil {
+		return nil, fmt.Errorf("couldn't decode merge patch: %v", err)
+	}
+	var contents interface{}
+	if !y.empty {
+		contents = y.contents
+	}
+	patched := applyMergePatch(contents, p, y.keyMatch)
+
+	var bs []byte
+	if patched != nil {
+		var err error
+		if bs, err = yaml.Marshal(patched); err != nil {
+			return nil, unreachable.Wrap(fmt.Errorf("couldn't marshal patched config to YAML: %v", err))
+		}
+	}
+	opts := append([]YAMLOption{patchSource(bs)}, y.options()...)
+	return NewYAML(opts...)
+}
+
+// applyMergePatch implements RFC 7386's MergePatch function, copying rather
+// than modifying the target.
+func applyMergePatch(target, patch interface{}, mode KeyMatchMode) interface{} {
+	pm, ok := patch.(map[interface{}]interface{})
+	if !ok {
+		return patch
+	}
+	merged := make(map[interface{}]interface{})
+	if tm, ok := target.(map[interface{}]interface{}); ok {
+		for k, v := range tm {
+			merged[k] = v
+		}
+	}
+	for k, v := range pm {
+		key := k
+		if s, ok := k.(string); ok {
+			if existing, ok := mode.resolve(merged, s); ok {
+				key = existing
+			}
+		}
+		if v == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = applyMergePatch(merged[key], v, mode)
+	}
+	return merged
+}
+
+// patchSource adds the source holding the result of ApplyMergePatch. It's
+// already expanded, so it's raw.
+func patchSource(bs []byte) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{bytes: bs, raw: true, name: "merge patch"})
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	newProvider := func(t testing.TB) *YAML {
+		p, err := NewYAML(
+			Source(strings.NewReader(strings.Join([]string{
+				`name: ${NAME:svc}`,
+				`server: {host: localhost, port: 8080, tls: {enabled: false, cert: a.pem}}`,
+				`peers: [alpha, beta]`,
+				`debug: true`,
+			}, "\n"))),
+			Expand(func(string) (string, bool) { return "", false }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("RFC 7386 semantics", func(t *testing.T) {
+		p := newProvider(t)
+		patched, err := p.ApplyMergePatch([]byte(`{
+			"server": {"port": 9090, "tls": {"enabled": true, "cert": null}},
+			"peers": ["gamma"],
+			"debug": null,
+			"owner": {"team": "infra"}
+		}`))
+		require.NoError(t, err, "couldn't apply patch")
+
+		assert.Equal(t, map[interface{}]interface{}{
+			"name": "svc",
+			"server": map[interface{}]interface{}{
+				"host": "localhost",
+				"port": 9090,
+				"tls":  map[interface{}]interface{}{"enabled": true},
+			},
+			"peers": []interface{}{"gamma"},
+			"owner": map[interface{}]interface{}{"team": "infra"},
+		}, patched.Get(Root).Value(), "unexpected patched configuration")
+		assert.False(t, patched.Get("debug").HasValue(), "expected null to delete key")
+		assert.Equal(t, 8080, p.Get("server.port").Value(), "expected original provider to be unchanged")
+		assert.Equal(t, true, p.Get("debug").Value(), "expected original provider to be unchanged")
+	})
+
+	t.Run("scalar replaces mapping", func(t *testing.T) {
+		patched, err := newProvider(t).ApplyMergePatch([]byte(`{"server": "disabled", "peers": {"0": "x"}}`))
+		require.NoError(t, err, "couldn't apply patch")
+		assert.Equal(t, "disabled", patched.Get("server").Value(), "expected scalar to replace mapping")
+		assert.Equal(t, map[interface{}]interface{}{"0": "x"}, patched.Get("peers").Value(), "expected object to replace array")
+	})
+
+	t.Run("variables aren't expanded", func(t *testing.T) {
+		patched, err := newProvider(t).ApplyMergePatch([]byte(`{"name": "${NAME:patched}"}`))
+		require.NoError(t, err, "couldn't apply patch")
+		assert.Equal(t, "${NAME:patched}", patched.Get("name").Value(), "expected patch values to be kept verbatim")
+	})
+
+	t.Run("null patch", func(t *testing.T) {
+		patched, err := newProvider(t).ApplyMergePatch([]byte(`null`))
+		require.NoError(t, err, "couldn't apply patch")
+		assert.False(t, patched.Get(Root).HasValue(), "expected null patch to remove all configuration")
+	})
+
+	t.Run("options are kept", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("port: 80")), Name("svc"))
+		require.NoError(t, err, "couldn't construct provider")
+		patched, err := p.ApplyMergePatch([]byte(`{"port": 81}`))
+		require.NoError(t, err, "couldn't apply patch")
+		assert.Equal(t, "svc", patched.Name(), "expected provider name to be kept")
+		var cfg struct{ Host string }
+		assert.Error(t, patched.Get(Root).Populate(&cfg), "expected strict mode to be kept")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		p := newProvider(t)
+		_, err := p.ApplyMergePatch(nil)
+		require.Error(t, err, "expected empty patch to fail")
+		assert.Contains(t, err.Error(), "merge patch is empty", "unexpected error message")
+
+		_, err = p.ApplyMergePatch([]byte(`{"server": `))
+		require.Error(t, err, "expected malformed patch to fail")
+		assert.Contains(t, err.Error(), "couldn't decode merge patch", "unexpected error message")
+
+		_, err = p.Seal().ApplyMergePatch([]byte(`{"debug": false}`))
+		require.Error(t, err, "expected sealed provider to reject patches")
+		assert.Contains(t, err.Error(), "provider is sealed", "unexpected error message")
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Mount adds the contents of an independently constructed provider as a
+// source, nested under the given dot-separated path. This lets each module
+// of an application own its configuration loader while the application
+// assembles a single provider:
+//   db, err := NewYAML(File("db.yaml"))
+//   ...
+//   cfg, err := NewYAML(
+//     File("base.yaml"),
+//     Mount("database", db),
+//     Mount("cache", cache),
+//   )
+// Mounting at Root adds the sub-provider's contents unchanged.
+//
+// A mounted provider is an ordinary source: it's merged with the other
+// sources in the order the options are supplied, so later sources (including
+// later mounts) take precedence over earlier ones, and mount points that
+// overlap are deep-merged. The sub-provider's contents have already been
+// expanded, so they aren't subject to the mounting provider's variable
+// expansion. Mounting an empty provider contributes nothing.
+func Mount(path string, sub *YAML) YAMLOption {
+	if sub == nil {
+		return failed(fmt.Errorf("can't mount nil provider at %q", path))
+	}
+	var segments []string
+	if path != Root {
+		segments = strings.Split(path, _separator)
+	}
+	for _, s := range segments {
+		if s == "" {
+			return failed(fmt.Errorf("can't mount provider at %q: empty path segment", path))
+		}
+	}
+	if sub.empty {
+		return optionFunc(func(*config) {})
+	}
+	contents := sub.contents
+	for i := len(segments) - 1; i >= 0; i-- {
+		contents = map[interface{}]interface{}{segments[i]: contents}
+	}
+	bs, err := yaml.Marshal(contents)
+	if err != nil {
+		return failed(unreachable.Wrap(fmt.Errorf("couldn't marshal mounted provider: %v", err)))
+	}
+	name := fmt.Sprintf("provider %q mounted at %q", sub.name, path)
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{bytes: bs, raw: true, name: name})
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMount(t *testing.T) {
+	db, err := NewYAML(StaticNamed("db", map[string]interface{}{
+		"host": "db.local",
+		"port": 5432,
+	}))
+	require.NoError(t, err, "couldn't construct db provider")
+	cache, err := NewYAML(StaticNamed("cache", map[string]interface{}{
+		"ttl": "1m",
+	}))
+	require.NoError(t, err, "couldn't construct cache provider")
+
+	t.Run("distinct paths", func(t *testing.T) {
+		p, err := NewYAML(
+			Static(map[string]interface{}{"name": "app"}),
+			Mount("storage.database", db),
+			Mount("cache", cache),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "app", p.Get("name").String(), "unexpected base value")
+		assert.Equal(t, "db.local", p.Get("storage.database.host").String(), "unexpected mounted value")
+		assert.Equal(t, 5432, p.Get("storage.database.port").Value(), "unexpected mounted value")
+		assert.Equal(t, "1m", p.Get("cache.ttl").String(), "unexpected mounted value")
+	})
+
+	t.Run("overlapping paths", func(t *testing.T) {
+		replica, err := NewYAML(Static(map[string]interface{}{
+			"host":    "replica.local",
+			"replica": true,
+		}))
+		require.NoError(t, err, "couldn't construct replica provider")
+
+		p, err := NewYAML(
+			Static(map[string]interface{}{
+				"database": map[string]interface{}{"port": 1, "user": "admin"},
+			}),
+			Mount("database", db),
+			Mount("database", replica),
+			Static(map[string]interface{}{
+				"database": map[string]interface{}{"user": "root"},
+			}),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		var got map[string]interface{}
+		require.NoError(t, p.Get("database").Populate(&got), "couldn't populate")
+		assert.Equal(t, map[string]interface{}{
+			"host":    "replica.local",
+			"port":    5432,
+			"replica": true,
+			"user":    "root",
+		}, got, "expected mounts to merge in priority order")
+	})
+
+	t.Run("root", func(t *testing.T) {
+		p, err := NewYAML(Static(map[string]interface{}{"port": 1}), Mount(Root, db))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 5432, p.Get("port").Value(), "expected root mount to override base")
+	})
+
+	t.Run("no expansion", func(t *testing.T) {
+		sub, err := NewYAML(
+			Source(strings.NewReader("password: $$secret\nuser: ${USER}")),
+			Expand(func(string) (string, bool) { return "alice", true }),
+		)
+		require.NoError(t, err, "couldn't construct sub-provider")
+		p, err := NewYAML(
+			Mount("db", sub),
+			Expand(func(string) (string, bool) { return "bob", true }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "$secret", p.Get("db.password").String(), "expected mounted values to stay expanded")
+		assert.Equal(t, "alice", p.Get("db.user").String(), "expected sub-provider's expansion to win")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		empty, err := NewYAML(Source(strings.NewReader("# nothing here")))
+		require.NoError(t, err, "couldn't construct empty provider")
+		p, err := NewYAML(Static(map[string]interface{}{"db": map[string]interface{}{"port": 1}}), Mount("db", empty))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 1, p.Get("db.port").Value(), "expected empty mount to contribute nothing")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewYAML(Mount("db", nil))
+		assert.Error(t, err, "expected error mounting nil provider")
+		_, err = NewYAML(Mount("db..primary", db))
+		assert.Error(t, err, "expected error mounting at path with empty segment")
+	})
+}
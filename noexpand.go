@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// NoExpandPattern exempts string values matching a regular expression from
+// variable expansion, while the rest of the configuration is expanded
+// normally. It's a finer-grained alternative to RawSource for values that
+// only look like variable references, such as templates stored for later
+// rendering:
+//   greeting: "Hello, ${name}!"
+//   port: ${PORT:8080}
+// With NoExpandPattern(regexp.MustCompile(`^Hello, `)), the greeting is
+// preserved exactly, but the port is still expanded.
+//
+// Patterns are matched against values as written, before expansion, and
+// only against string values; mapping keys are always expanded. Supplying
+// this option more than once exempts values matching any of the patterns.
+func NoExpandPattern(re *regexp.Regexp) YAMLOption {
+	if re == nil {
+		return failed(errors.New("no-expand pattern must not be nil"))
+	}
+	return optionFunc(func(c *config) {
+		c.noExpand = append(c.noExpand, re)
+	})
+}
+
+// protectPatterns escapes the string values in the merged YAML that match
+// any of the patterns, so that expansion restores them exactly.
+func protectPatterns(merged *bytes.Buffer, patterns []*regexp.Regexp) (*bytes.Buffer, error) {
+	var contents interface{}
+	if err := yaml.NewDecoder(merged).Decode(&contents); err == io.EOF {
+		return merged, nil
+	} else if err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't decode merged YAML: %v", err))
+	}
+
+	contents = replaceMatching(contents, patterns, func(s string) string {
+		return string(escapeVariables([]byte(s)))
+	})
+
+	buf := &bytes.Buffer{}
+	if err := yaml.NewEncoder(buf).Encode(contents); err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't re-serialize merged YAML: %v", err))
+	}
+	return buf, nil
+}
+
+// replaceMatching replaces every string value in a YAML node that matches
+// any of the patterns with the result of f. Mapping keys are left alone.
+func replaceMatching(node interface{}, patterns []*regexp.Regexp, f func(string) string) interface{} {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		for k, v := range n {
+			n[k] = replaceMatching(v, patterns, f)
+		}
+	case []interface{}:
+		for i, v := range n {
+			n[i] = replaceMatching(v, patterns, f)
+		}
+	case string:
+		for _, re := range patterns {
+			if re.MatchString(n) {
+				return f(n)
+			}
+		}
+	}
+	return node
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoExpandPattern(t *testing.T) {
+	env := map[string]string{"name": "world", "PORT": "80"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	src := strings.Join([]string{
+		`greeting: "Hello, ${name}!"`,
+		`farewell: "Bye, ${name}!"`,
+		`port: ${PORT}`,
+		`templates: ["Hello, $name", "${name}"]`,
+		"",
+	}, "\n")
+	hello := regexp.MustCompile(`^Hello, `)
+
+	t.Run("matching values are preserved", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup), NoExpandPattern(hello))
+		require.NoError(t, err, "couldn't construct provider")
+
+		assert.Equal(t, "Hello, ${name}!", p.Get("greeting").String(), "matching value was expanded")
+		assert.Equal(t, "Bye, world!", p.Get("farewell").String(), "non-matching value wasn't expanded")
+		assert.Equal(t, 80, p.Get("port").Value(), "non-matching value wasn't expanded")
+		assert.Equal(t, []interface{}{"Hello, $name", "world"}, p.Get("templates").Value(), "sequence elements are matched individually")
+	})
+
+	t.Run("multiple patterns", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(src)),
+			Expand(lookup),
+			NoExpandPattern(hello),
+			NoExpandPattern(regexp.MustCompile(`^Bye`)),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "Hello, ${name}!", p.Get("greeting").String(), "value matching first pattern was expanded")
+		assert.Equal(t, "Bye, ${name}!", p.Get("farewell").String(), "value matching second pattern was expanded")
+	})
+
+	t.Run("missing variables in matching values", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(`template: "${UNSET}"`)),
+			Expand(lookup),
+			NoExpandPattern(regexp.MustCompile(`UNSET`)),
+		)
+		require.NoError(t, err, "exempt values shouldn't need their variables set")
+		assert.Equal(t, "${UNSET}", p.Get("template").String(), "unexpected template value")
+		assert.Empty(t, p.RequiredEnvVars(), "exempt values shouldn't require variables")
+	})
+
+	t.Run("strict expansion", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(`template: "Hello, ${unclosed"`)),
+			Expand(lookup),
+			StrictExpand(),
+			NoExpandPattern(hello),
+		)
+		require.NoError(t, err, "exempt values shouldn't be checked for malformed references")
+		assert.Equal(t, "Hello, ${unclosed", p.Get("template").String(), "unexpected template value")
+	})
+
+	t.Run("survives defaults", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Expand(lookup), NoExpandPattern(hello))
+		require.NoError(t, err, "couldn't construct provider")
+		v, err := p.Get(Root).WithDefault(map[string]string{"extra": "${name}"})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.Equal(t, "Hello, ${name}!", v.Get("greeting").String(), "matching value was expanded after WithDefault")
+		assert.Equal(t, "world", v.Get("extra").String(), "default wasn't expanded")
+	})
+
+	t.Run("dollar signs without expansion", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(`greeting: "Hello, $$name"`)), NoExpandPattern(hello))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "Hello, $$name", p.Get("greeting").String(), "values shouldn't be escaped when expansion is disabled")
+	})
+
+	t.Run("nil pattern", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader(src)), NoExpandPattern(nil))
+		require.Error(t, err, "expected an error for a nil pattern")
+		assert.Contains(t, err.Error(), "must not be nil", "unexpected error message")
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// _decimal matches numbers written in plain decimal notation, the only form
+// whose precision PreserveNumbers keeps.
+var _decimal = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+
+// PreserveNumbers keeps the exact text of numbers that don't fit in an int64,
+// a uint64, or a float64 without rounding, such as
+//   balance: 12345678901234567890.12345
+//   limit: 123456789012345678901234567890
+// Without it, gopkg.in/yaml.v2 rounds such numbers to the nearest float64 as
+// soon as sources are merged. With it, merging is unchanged, but the provider
+// remembers how each imprecise number was written, and Populate and
+// Value.Number see that text instead of the rounded value.
+//
+// This matters only for targets that can hold more precision than a float64.
+// Populating a string or json.Number field (or any type that unmarshals from
+// a string) yields the number exactly as written, so it can be parsed with
+// math/big or a decimal library. Populating an int64 field from an integer
+// that doesn't fit is an error rather than a silently rounded value, and
+// populating a float64 field rounds the original text once, rather than
+// rounding an already-rounded value. Values retrieved as interface{}, like
+// those returned by Value.Value and YAML.Flatten, are still float64s.
+//
+// Only numbers written in decimal notation, in sources or in the values of
+// expanded variables, are preserved, and numbers that a MergeFunc replaces
+// with a different value are rounded as usual. Snapshots keep the exact text
+// of preserved numbers (see YAML.Snapshot).
+func PreserveNumbers() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.exactNumbers = true
+	})
+}
+
+// preciseNumbers finds the numbers in the sources and the expanded, merged
+// YAML that a float64 can't represent exactly, and returns their original
+// text keyed by dotted path. Numbers that didn't survive merging unchanged
+// are omitted.
+func preciseNumbers(sources []source, merged []byte, contents interface{}, normalize func(string) string, mode KeyMatchMode) map[string]string {
+	scan := func(bs []byte, normalize func(string) string, found map[string]string) {
+		var doc yaml3.Node
+		if err := yaml3.Unmarshal(bs, &doc); err != nil {
+			// Merging has already validated the sources, so this is a
+			// document that gopkg.in/yaml.v3 can't read. Its numbers are
+			// rounded as usual.
+			return
+		}
+		eachScalar(&doc, nil, normalize, func(path []string, node *yaml3.Node) {
+			text := ""
+			if isImprecise(node) {
+				text = node.Value
+			}
+			found[strings.Join(path, _separator)] = text
+		})
+	}
+	// Merged values come from the highest-priority source that sets them, so
+	// later sources overwrite earlier ones. Values produced by expanding
+	// variables only appear in the merged YAML, which is used for any path
+	// where the winning source doesn't have a number.
+	found := make(map[string]string)
+	for _, s := range sources {
+		scan(s.bytes, normalize, found)
+	}
+	expanded := make(map[string]string)
+	scan(merged, nil, expanded)
+	for key, text := range expanded {
+		if found[key] == "" {
+			found[key] = text
+		}
+	}
+
+	numbers := make(map[string]string)
+	for key, text := range found {
+		if text == "" {
+			continue
+		}
+		val, ok := lookup(contents, strings.Split(key, _separator), mode)
+		if !ok {
+			continue
+		}
+		if f, ok := val.(float64); ok && f == roundedNumber(text) {
+			numbers[key] = text
+		}
+	}
+	if len(numbers) == 0 {
+		return nil
+	}
+	return numbers
+}
+
+// eachScalar calls f with the path of every scalar in a YAML node.
+func eachScalar(node *yaml3.Node, path []string, normalize func(string) string, f func([]string, *yaml3.Node)) {
+	switch node.Kind {
+	case yaml3.DocumentNode:
+		for _, n := range node.Content {
+			eachScalar(n, path, normalize, f)
+		}
+	case yaml3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if normalize != nil {
+				key = normalize(key)
+			}
+			eachScalar(node.Content[i+1], appendPath(path, key), normalize, f)
+		}
+	case yaml3.SequenceNode:
+		for i, n := range node.Content {
+			eachScalar(n, appendPath(path, strconv.Itoa(i)), normalize, f)
+		}
+	case yaml3.ScalarNode:
+		f(path, node)
+	}
+}
+
+// isImprecise reports whether a scalar node is a decimal number that
+// gopkg.in/yaml.v2 would round.
+func isImprecise(node *yaml3.Node) bool {
+	if node.Style != 0 || !_decimal.MatchString(node.Value) {
+		return false
+	}
+	if tag := node.ShortTag(); tag != "!!int" && tag != "!!float" {
+		return false
+	}
+	exact, ok := new(big.Rat).SetString(node.Value)
+	if !ok {
+		return false
+	}
+	f := roundedNumber(node.Value)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return true
+	}
+	if _, ok := decodeScalar(node.Value).(float64); !ok {
+		// Integers that fit in an int64 or a uint64 aren't rounded.
+		return false
+	}
+	return exact.Cmp(new(big.Rat).SetFloat64(f)) != 0
+}
+
+// roundedNumber returns the float64 that gopkg.in/yaml.v2 decodes a number
+// to.
+func roundedNumber(text string) float64 {
+	f, _ := decodeScalar(text).(float64)
+	return f
+}
+
+func decodeScalar(text string) interface{} {
+	var val interface{}
+	if err := yaml.Unmarshal([]byte(text), &val); err != nil {
+		return nil
+	}
+	return val
+}
+
+func appendPath(path []string, segment string) []string {
+	extended := make([]string, len(path), len(path)+1)
+	copy(extended, path)
+	return append(extended, segment)
+}
+
+// restoreNumbers replaces the rounded numbers in YAML serialized from the
+// value at path with their original text.
+func (y *YAML) restoreNumbers(buf *bytes.Buffer, path []string) (*bytes.Buffer, error) {
+	prefix := strings.Join(path, _separator)
+	relevant := false
+	for key := range y.numbers {
+		if prefix == "" || key == prefix || strings.HasPrefix(key, prefix+_separator) {
+			relevant = true
+			break
+		}
+	}
+	if !relevant {
+		return buf, nil
+	}
+
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(buf.Bytes(), &doc); err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't decode config at key %s: %v", prefix, err))
+	}
+	var restore func(*yaml3.Node, []string)
+	restore = func(node *yaml3.Node, path []string) {
+		switch node.Kind {
+		case yaml3.DocumentNode:
+			for _, n := range node.Content {
+				restore(n, path)
+			}
+		case yaml3.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				restore(node.Content[i+1], appendPath(path, node.Content[i].Value))
+			}
+		case yaml3.SequenceNode:
+			for i, n := range node.Content {
+				restore(n, appendPath(path, strconv.Itoa(i)))
+			}
+		case yaml3.ScalarNode:
+			text, ok := y.numbers[strings.Join(path, _separator)]
+			if !ok || node.ShortTag() != "!!float" {
+				return
+			}
+			// Reshaping may have moved values, so only restore numbers that
+			// still have the same value.
+			if f, ok := decodeScalar(node.Value).(float64); ok && f == roundedNumber(text) {
+				node.Tag, node.Value = "", text
+			}
+		}
+	}
+	restore(&doc, path)
+
+	restored := &bytes.Buffer{}
+	enc := yaml3.NewEncoder(restored)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't re-serialize config at key %s: %v", prefix, err))
+	}
+	if err := enc.Close(); err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't re-serialize config at key %s: %v", prefix, err))
+	}
+	return restored, nil
+}
+
+// Number returns the number at this key as a json.Number, which holds the
+// number's decimal text. If the provider was constructed with
+// PreserveNumbers, the text is exactly as written; otherwise, imprecise
+// numbers have already been rounded to float64s. It's an error if the key is
+// absent or isn't a number.
+func (v Value) Number() (json.Number, error) {
+	key := strings.Join(v.path, _separator)
+	val, ok := v.provider.at(v.path)
+	if !ok {
+		return "", &NotFoundError{Provider: v.provider.name, Key: key}
+	}
+	if text, ok := v.provider.numbers[key]; ok {
+		return json.Number(text), nil
+	}
+	switch n := val.(type) {
+	case int:
+		return json.Number(strconv.Itoa(n)), nil
+	case int64:
+		return json.Number(strconv.FormatInt(n, 10)), nil
+	case uint64:
+		return json.Number(strconv.FormatUint(n, 10)), nil
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return "", fmt.Errorf("value at key %q is %v, which isn't a finite number", key, n)
+		}
+		return json.Number(strconv.FormatFloat(n, 'g', -1, 64)), nil
+	}
+	return "", fmt.Errorf("value at key %q isn't a number", key)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreserveNumbers(t *testing.T) {
+	const (
+		decimal = "12345678901234567890.12345"
+		large   = "123456789012345678901234567890"
+		int64s  = "9223372036854775807"
+	)
+	src := strings.Join([]string{
+		"balance: " + decimal,
+		"limit: " + large,
+		"max: " + int64s,
+		"ratio: 0.5",
+		"rates: [" + decimal + ", 1.25]",
+		"",
+	}, "\n")
+
+	type account struct {
+		Balance json.Number   `yaml:"balance"`
+		Limit   string        `yaml:"limit"`
+		Max     int64         `yaml:"max"`
+		Ratio   float64       `yaml:"ratio"`
+		Rates   []json.Number `yaml:"rates"`
+	}
+
+	t.Run("precision is lost by default", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var a account
+		require.NoError(t, p.Get(Root).Populate(&a), "couldn't populate")
+		assert.NotEqual(t, decimal, a.Balance.String(), "expected decimal to be rounded without PreserveNumbers")
+		assert.NotEqual(t, large, a.Limit, "expected large integer to be rounded without PreserveNumbers")
+	})
+
+	t.Run("populate", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), PreserveNumbers())
+		require.NoError(t, err, "couldn't construct provider")
+		var a account
+		require.NoError(t, p.Get(Root).Populate(&a), "couldn't populate")
+		assert.Equal(t, account{
+			Balance: decimal,
+			Limit:   large,
+			Max:     9223372036854775807,
+			Ratio:   0.5,
+			Rates:   []json.Number{decimal, "1.25"},
+		}, a, "unexpected populated struct")
+
+		exact, ok := new(big.Rat).SetString(a.Balance.String())
+		require.True(t, ok, "couldn't parse balance")
+		assert.Equal(t, "12345678901234567890.12345", exact.FloatString(5), "unexpected exact balance")
+
+		var balance json.Number
+		require.NoError(t, p.Get("balance").Populate(&balance), "couldn't populate scalar")
+		assert.Equal(t, json.Number(decimal), balance, "unexpected number populating a scalar")
+		var rate string
+		require.NoError(t, p.Get("rates.0").Populate(&rate), "couldn't populate sequence element")
+		assert.Equal(t, decimal, rate, "unexpected number populating a sequence element")
+	})
+
+	t.Run("numeric fields", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), PreserveNumbers())
+		require.NoError(t, err, "couldn't construct provider")
+		var f float64
+		require.NoError(t, p.Get("balance").Populate(&f), "couldn't populate float64")
+		assert.Equal(t, 12345678901234567890.12345, f, "unexpected float64")
+
+		var i int64
+		err = p.Get("limit").Populate(&i)
+		require.Error(t, err, "expected an overflowing integer to fail")
+		assert.Contains(t, err.Error(), `couldn't populate key "limit"`, "unexpected error message")
+	})
+
+	t.Run("accessor", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), PreserveNumbers())
+		require.NoError(t, err, "couldn't construct provider")
+		for key, expect := range map[string]string{
+			"balance": decimal,
+			"limit":   large,
+			"max":     int64s,
+			"ratio":   "0.5",
+			"rates.0": decimal,
+		} {
+			n, err := p.Get(key).Number()
+			require.NoError(t, err, "couldn't get number at %q", key)
+			assert.Equal(t, json.Number(expect), n, "unexpected number at %q", key)
+		}
+		i, err := p.Get("max").Number()
+		require.NoError(t, err, "couldn't get number")
+		max, err := i.Int64()
+		require.NoError(t, err, "couldn't parse int64")
+		assert.Equal(t, int64(9223372036854775807), max, "unexpected int64")
+
+		_, err = p.Get("rates").Number()
+		assert.Error(t, err, "expected an error for a sequence")
+		_, err = p.Get("missing").Number()
+		var notFound *NotFoundError
+		assert.True(t, errors.As(err, &notFound), "expected a NotFoundError for a missing key")
+	})
+
+	t.Run("overrides and expansion", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(src)),
+			Source(strings.NewReader("limit: 1\nbalance: ${BALANCE}")),
+			Expand(func(string) (string, bool) { return "0.100000000000000000001", true }),
+			PreserveNumbers(),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		n, err := p.Get("limit").Number()
+		require.NoError(t, err, "couldn't get number")
+		assert.Equal(t, json.Number("1"), n, "expected overridden number not to be restored")
+		n, err = p.Get("balance").Number()
+		require.NoError(t, err, "couldn't get number")
+		assert.Equal(t, json.Number("0.100000000000000000001"), n, "expected expanded number to be preserved")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), PreserveNumbers())
+		require.NoError(t, err, "couldn't construct provider")
+		v, err := p.Get(Root).WithDefault(map[string]float64{"ratio": 1})
+		require.NoError(t, err, "couldn't apply defaults")
+		var a account
+		require.NoError(t, v.Populate(&a), "couldn't populate")
+		assert.Equal(t, json.Number(decimal), a.Balance, "expected precision to survive WithDefault")
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import "errors"
+
+// An Observer is notified when configuration is accessed, which lets
+// applications track hot keys, populate failures, and configuration that's
+// never read. See WithObserver.
+//
+// Paths are dotted, as passed to Get; the root of the configuration is
+// reported as Root. Observers are called synchronously while the provider is
+// being read, so implementations must be safe for concurrent use and should
+// return quickly.
+type Observer interface {
+	// OnGet is called each time a Value is retrieved with YAML.Get,
+	// YAML.GetOrError, or Value.Get, whether or not the path has any
+	// configuration.
+	OnGet(path string)
+	// OnPopulate is called after each Value.Populate with the value's path
+	// and the error Populate returns, if any.
+	OnPopulate(path string, err error)
+}
+
+// WithObserver registers an Observer that the provider notifies on each
+// access. Providers created with WithDefault keep the observer. Without an
+// observer, providers don't pay for instrumentation beyond a nil check.
+func WithObserver(o Observer) YAMLOption {
+	if o == nil {
+		return failed(errors.New("observer must not be nil"))
+	}
+	return optionFunc(func(c *config) {
+		c.observer = o
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type populateEvent struct {
+	path string
+	err  error
+}
+
+type recordingObserver struct {
+	mu        sync.Mutex
+	gets      []string
+	populates []populateEvent
+}
+
+func (o *recordingObserver) OnGet(path string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.gets = append(o.gets, path)
+}
+
+func (o *recordingObserver) OnPopulate(path string, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.populates = append(o.populates, populateEvent{path, err})
+}
+
+func TestWithObserver(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		_, err := NewYAML(WithObserver(nil))
+		assert.Error(t, err, "expected error for nil observer")
+	})
+
+	obs := &recordingObserver{}
+	p, err := NewYAML(
+		Source(strings.NewReader("server: {port: 80, host: example.com}")),
+		WithObserver(obs),
+	)
+	require.NoError(t, err, "couldn't create provider")
+
+	var port int
+	require.NoError(t, p.Get("server").Get("port").Populate(&port), "populate failed")
+	assert.Equal(t, 80, port, "unexpected port")
+
+	var host int
+	assert.Error(t, p.Get("server.host").Populate(&host), "expected populate of string into int to fail")
+
+	_, err = p.GetOrError("missing")
+	assert.Error(t, err, "expected missing key")
+
+	var all map[string]interface{}
+	require.NoError(t, p.Get(Root).Populate(&all), "populate of root failed")
+
+	assert.Equal(t, []string{"server", "server.port", "server.host", "missing", Root}, obs.gets, "unexpected Get paths")
+	require.Len(t, obs.populates, 3, "unexpected number of populates")
+	assert.Equal(t, "server.port", obs.populates[0].path, "unexpected path for first populate")
+	assert.NoError(t, obs.populates[0].err, "unexpected error for first populate")
+	assert.Equal(t, "server.host", obs.populates[1].path, "unexpected path for failed populate")
+	assert.Error(t, obs.populates[1].err, "expected failed populate to report its error")
+	assert.Equal(t, Root, obs.populates[2].path, "unexpected path for root populate")
+
+	t.Run("with default", func(t *testing.T) {
+		d, err := p.withDefault(map[string]int{"extra": 1})
+		require.NoError(t, err, "couldn't apply defaults")
+		d.Get("extra")
+		assert.Equal(t, "extra", obs.gets[len(obs.gets)-1], "observer not kept by withDefault")
+	})
+}
@@ -21,9 +21,20 @@
 package config
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 
 	"go.uber.org/multierr"
 	yaml "gopkg.in/yaml.v2"
@@ -61,10 +72,373 @@ func (f optionFunc) apply(c *config) { f(c) }
 // the shell naming rules above. If a variable isn't found, the default value
 // is used.
 //
-// $$ is expanded to a literal $.
+// Variables are expanded in mapping keys as well as values, so
+//   ${REGION}:
+//     replicas: 3
+// addresses the key us-east if REGION is us-east. Because merging happens
+// before expansion, a key like ${REGION} is never deep-merged with a literal
+// key in another source, even if they match after expansion. Instead, keys
+// that are identical after expansion are duplicates: in strict mode, NewYAML
+// returns an error, and in permissive mode it's unspecified which value wins.
+//
+// $$ is expanded to a literal $, which escapes references that should be
+// kept as written, as in many templating languages: $${VAR} becomes the
+// literal text ${VAR}, and $$VAR becomes $VAR, without looking VAR up. This
+// lets a single value opt out of expansion without moving it to a raw
+// source.
+//
+// A $ that doesn't begin a variable reference (for example, one followed by
+// a space or digit, or at the end of a value), a ${ with no closing brace,
+// and an unmatched } are all left as-is. Variables are never expanded in raw
+// sources, whose contents are preserved exactly: a $$ in a raw source remains
+// $$. To preview expansion of a single string, use ExpandString.
 func Expand(lookup LookupFunc) YAMLOption {
+	return ExpandE(withoutErrors(lookup))
+}
+
+// ExpandE is like Expand, but uses a lookup function that can fail. If a
+// lookup returns an error, provider construction fails with an error that
+// names the variable and wraps the lookup's error, so it's available via
+// errors.Is and errors.As. The syntax and semantics of variable references
+// are otherwise identical to Expand.
+func ExpandE(lookup LookupErrFunc) YAMLOption {
 	return optionFunc(func(c *config) {
 		c.lookup = lookup
+		c.lookupCtx = ignoreContext(lookup)
+	})
+}
+
+// ExpandContext is like ExpandE, but uses a lookup function that accepts a
+// context, which is useful for resolving variables from remote backends like
+// secret stores. During NewYAMLContext, lookups receive the context passed to
+// it, so they can respect cancellation and deadlines; NewYAML and WithDefault
+// use context.Background(). A lookup that returns the context's error makes
+// provider construction fail with an error that wraps it.
+func ExpandContext(lookup LookupContextFunc) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.lookupCtx = lookup
+		c.lookup = nil
+		if lookup != nil {
+			c.lookup = bindContext(context.Background(), lookup)
+		}
+	})
+}
+
+// CacheExpansion makes a provider remember the result of each variable
+// lookup made while expanding its configuration, and reuse it rather than
+// looking the variable up again. WithDefault constructs a new provider by
+// re-expanding the original sources; with CacheExpansion, that provider sees
+// the environment as it was when the original provider was constructed, even
+// if it has changed since. Variables that the original
+// configuration didn't reference are looked up when they're first needed and
+// then cached too. Failed lookups aren't cached.
+//
+// It has no effect unless the provider expands variables (see Expand).
+func CacheExpansion() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.cacheLookups = true
+	})
+}
+
+// StrictExpand makes provider construction fail if the configuration contains
+// a malformed variable reference, rather than leaving it as-is or treating it
+// as an unusual key. With StrictExpand, a reference beginning with ${ must
+// have a closing brace in the same value, and the key before any default must
+// be non-empty and adhere to the shell naming rules described in Expand. So
+//   ${UNCLOSED
+//   ${}
+//   ${BAD NAME}
+// are all errors, which identify the malformed reference and the key holding
+// it. References are checked only when Expand is also supplied, and never in
+// raw sources, Verbatim values, or values exempted by NoExpandPattern.
+func StrictExpand() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.strictExpand = true
+	})
+}
+
+// EnvAllowlist limits variable expansion to the named variables: provider
+// construction fails if the configuration references any other variable,
+// even one that's set or that has a default. This lets security-sensitive
+// applications guarantee that configuration can't read arbitrary values from
+// the environment. Multiple EnvAllowlist options are combined, and supplying
+// the option without any names rejects every reference.
+//
+// Allowed variables are still looked up as usual, so an allowed variable
+// that's unset and has no default remains an error. The allowlist is enforced
+// only when Expand (or one of its variants) is also supplied, and never
+// applies to raw sources, Verbatim values, or values exempted by
+// NoExpandPattern, which aren't expanded.
+func EnvAllowlist(names ...string) YAMLOption {
+	return optionFunc(func(c *config) {
+		if c.envAllowlist == nil {
+			c.envAllowlist = make(map[string]struct{}, len(names))
+		}
+		for _, n := range names {
+			c.envAllowlist[n] = struct{}{}
+		}
+	})
+}
+
+// StrictPaths limits Populate's unknown-key checks to the subtrees at the
+// supplied dotted paths. Populating a struct then fails if a configured key
+// within one of those subtrees doesn't correspond to a field, but tolerates
+// unknown keys everywhere else. This is useful when only some sections of
+// the configuration are under your control, for example
+//   NewYAML(File("base.yaml"), File("vendor.yaml"), Permissive(), StrictPaths("security"))
+// StrictPaths affects only populating: whether sources with duplicate keys
+// are rejected is still controlled by Permissive. Supplying no paths has no
+// effect.
+func StrictPaths(paths ...string) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.strictPaths = append(c.strictPaths, paths...)
+	})
+}
+
+// ReplaceMaps changes how the mappings at the supplied dotted paths are
+// merged: instead of deep-merging, a higher-priority source's mapping
+// replaces a lower-priority one entirely. For example, with
+// ReplaceMaps("labels"), merging
+//   # base.yaml
+//   labels: {team: core, tier: 1}
+//
+//   # override.yaml
+//   labels: {team: edge}
+// produces {labels: {team: edge}} rather than {labels: {team: edge, tier: 1}}.
+// Mappings elsewhere, including those nested inside a replaced mapping in a
+// single source, are unaffected. Note that non-string keys are matched using
+// their string representation.
+func ReplaceMaps(paths ...string) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.replaceMaps = append(c.replaceMaps, paths...)
+	})
+}
+
+// NormalizeKeys canonicalizes the string keys of every mapping in each
+// source before merging, so that keys differing only in, say, case combine
+// into a single branch. With NormalizeKeys(strings.ToLower),
+//   # base.yaml
+//   Server: {Port: 80}
+//
+//   # override.yaml
+//   server: {host: example.com}
+// merges to {server: {port: 80, host: example.com}}. Keys that aren't strings
+// are left alone. If normalizing makes two keys in the same mapping of one
+// source equal, strict mode reports an error, and permissive mode keeps the
+// later key's value, just as for duplicate keys. Equal keys in different
+// sources merge normally. Since the merged configuration holds only
+// normalized keys, Get, ReplaceMaps, and the other options that accept
+// paths must use normalized keys too.
+func NormalizeKeys(f func(string) string) YAMLOption {
+	if f == nil {
+		return failed(errors.New("key normalization function must not be nil"))
+	}
+	return optionFunc(func(c *config) {
+		c.normalizeKeys = f
+	})
+}
+
+// A MergeResolver decides how to merge conflicting values. See MergeFunc.
+type MergeResolver = func(path []string, lower, higher interface{}) (interface{}, bool)
+
+// MergeFunc customizes how conflicting values are merged, for example to
+// keep the larger of two numbers. A conflict occurs wherever two sources both
+// set a non-null value at the same path, unless both values are mappings
+// that would be deep-merged; pairs of scalars, pairs of sequences, mappings
+// replaced because of ReplaceMaps, and values of mismatched types are all
+// conflicts. At each conflict, f receives the path (with mapping keys
+// formatted using fmt.Sprint) and the lower- and higher-priority values, as
+// gopkg.in/yaml.v2 unmarshals them into interface{}. If f reports true, its
+// result becomes the merged value, and must be representable as YAML;
+// otherwise, the higher-priority value wins as usual (or, in strict mode,
+// values of mismatched types are an error). f must not retain or modify the
+// path.
+//
+// Conflicts are resolved before variables are expanded, so f sees
+// unexpanded references such as ${PORT}. Providers created with WithDefault
+// use f to merge the defaults too.
+func MergeFunc(f MergeResolver) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.mergeFunc = f
+	})
+}
+
+// MaxDepth limits how deeply mappings and sequences may nest in each
+// source: NewYAML returns an error naming the offending key if any value is
+// more than max keys and indices below the root. For example, a: {b: [c]}
+// has a depth of 3. Since merging and populating walk configuration
+// recursively, the limit protects services that load untrusted or
+// machine-generated configuration from exhausting the stack. By default,
+// providers allow a depth of 1000, which is far beyond what hand-written
+// configuration needs.
+//
+// The limit is enforced after each source is parsed, so it's best combined
+// with MaxSourceBytes.
+func MaxDepth(max int) YAMLOption {
+	if max <= 0 {
+		return failed(fmt.Errorf("maximum depth must be positive, got %d", max))
+	}
+	return optionFunc(func(c *config) {
+		c.maxDepth = max
+	})
+}
+
+// MaxSequenceLen limits the number of elements in each sequence: NewYAML
+// returns an error naming the offending key if any sequence has more than
+// max elements. Like MaxDepth, it protects services that load untrusted
+// configuration, in this case from an overlay whose huge sequences would be
+// expensive to merge and populate. By default, sequences may be any length.
+//
+// The limit is enforced after each source is parsed, including defaults
+// applied with WithDefault, so it's best combined with MaxSourceBytes.
+func MaxSequenceLen(max int) YAMLOption {
+	if max <= 0 {
+		return failed(fmt.Errorf("maximum sequence length must be positive, got %d", max))
+	}
+	return optionFunc(func(c *config) {
+		c.maxSeqLen = max
+	})
+}
+
+// MaxSourceBytes limits the size of each source read from an io.Reader or a
+// file, including sources added by Source, RawSource, SectionedSource, Stdin,
+// File, GzipFile, FS, and SopsFile, as well as the decoded contents of
+// Base64EnvSource. NewYAML reads no more than max+1 bytes from each source,
+// so an oversized or unbounded stream fails quickly instead of being buffered
+// in memory. Sources are read when the provider is constructed, so
+// MaxSourceBytes applies no matter where it appears among the options.
+//
+// By default, the size of sources isn't limited. In-memory sources, like
+// those added by Static, are never limited.
+func MaxSourceBytes(max int64) YAMLOption {
+	if max <= 0 {
+		return failed(fmt.Errorf("maximum source size must be positive, got %d", max))
+	}
+	return optionFunc(func(c *config) {
+		c.maxSourceBytes = max
+	})
+}
+
+// StructTag makes Populate map configuration keys to struct fields using the
+// named struct tag instead of the yaml tag, which is convenient for structs
+// that are already tagged for another encoding. For example, with
+// StructTag("json"),
+//   type Server struct {
+//     ListenPort int `json:"listen_port"`
+//   }
+// populates from {listen_port: 8080}. As with the yaml tag, untagged fields
+// are addressed by their lowercased names, fields tagged "-" are skipped, and
+// the fields of untagged embedded structs are promoted.
+//
+// Only the key portion of the tag is used: flags like omitempty and string
+// are ignored, and structs can't be inlined using the alternate tag (though
+// `yaml:",inline"` still works). Fields tagged `yaml:"-"` remain unavailable.
+// Types that implement their own unmarshaling are unaffected.
+func StructTag(name string) YAMLOption {
+	if name == "" {
+		return failed(errors.New("struct tag name must not be empty"))
+	}
+	return optionFunc(func(c *config) {
+		c.tag = name
+	})
+}
+
+// UseDefaultTags makes Populate fill in struct fields using their default
+// tags, so a single struct declares both its keys and their defaults:
+//   type Server struct {
+//     Port    int           `yaml:"port" default:"8080"`
+//     Hosts   []string      `yaml:"hosts" default:"[localhost]"`
+//     Timeout time.Duration `yaml:"timeout" default:"5s"`
+//   }
+// Each default is parsed as YAML into the field's type. After decoding, a
+// field is set to its default if it's still zero-valued and its key is
+// absent from the configuration, so explicitly configured zero values (like
+// port: 0) are preserved. Defaults also apply to the fields of nested
+// structs, non-nil pointers to structs, and the elements of sequences, and
+// they apply even if there's no configuration at the populated key at all.
+// Normalizers see the defaulted values. Defaults that can't be parsed into
+// their field's type make Populate return an error.
+func UseDefaultTags() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.defaultTags = true
+	})
+}
+
+// UseEnvTags makes Populate override struct fields with the environment
+// variables named by their env tags, keeping 12-factor overrides next to the
+// fields they override:
+//   type Server struct {
+//     Port int    `yaml:"port" env:"PORT"`
+//     Host string `yaml:"host" env:"HOST"`
+//   }
+// After decoding and applying any default tags, each field whose variable is
+// set, even to an empty string, is replaced with the variable's value, so
+// environment variables take precedence over both configuration and default
+// tags. Values for string fields are used as-is; other values are parsed as
+// YAML into the field's type, so PORT=9090 populates an int and
+// HOSTS=[a, b] populates a []string. Values that can't be parsed make
+// Populate return an error.
+//
+// Env tags apply to the fields of nested structs and non-nil pointers to
+// structs, even if there's no configuration at the populated key, but not to
+// the elements of sequences or maps. Variables are read with os.LookupEnv
+// when Populate is called, so they aren't subject to EnvAllowlist.
+func UseEnvTags() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.envTags = true
+	})
+}
+
+// RejectNullStructs makes Populate return an error, naming the key, when an
+// explicit null would populate a struct or map that isn't behind a pointer.
+// By default, gopkg.in/yaml.v2 silently leaves such values unchanged, so a
+// source that accidentally sets
+//   server: null
+// is indistinguishable from one that doesn't mention the server at all.
+// Nulls remain allowed wherever the target is a pointer, interface, slice,
+// or scalar, and for types that implement their own unmarshaling.
+func RejectNullStructs() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.rejectNulls = true
+	})
+}
+
+// StrictScalars makes Populate return an error, naming the key, when a
+// Boolean or number populates a string field. Unquoted YAML scalars are
+// typed by their spelling, so configuration that looks like a string often
+// isn't one:
+//   country: no   # the Norway problem: populates "false"
+//   version: 1.0  # populates "1", since merging re-serializes 1.0 as 1
+//   enabled: on   # populates "true"
+// By default, gopkg.in/yaml.v2 silently converts such values to strings.
+// With StrictScalars, the value must be quoted to populate a string field.
+// Fields of types that implement their own unmarshaling, and json.Number
+// fields, which are meant to hold numbers, are exempt.
+func StrictScalars() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.strictScalars = true
+	})
+}
+
+// TrimStrings makes Populate remove leading and trailing whitespace from
+// string values, which configuration copied from spreadsheets or environment
+// variables often carries, before setting them on the target. It's
+// equivalent to TrimStringsFunc(strings.TrimSpace).
+func TrimStrings() YAMLOption {
+	return TrimStringsFunc(strings.TrimSpace)
+}
+
+// TrimStringsFunc makes Populate pass every string value through f before
+// setting it on the target. Only string scalars are transformed: keys,
+// Booleans, numbers, and nulls are left alone. Value.Value, which is
+// implemented with Populate, also returns transformed strings, but the
+// provider's contents, as returned by YAML.CanonicalBytes, aren't changed.
+func TrimStringsFunc(f func(string) string) YAMLOption {
+	if f == nil {
+		return failed(errors.New("string transform must not be nil"))
+	}
+	return optionFunc(func(c *config) {
+		c.trimStrings = f
 	})
 }
 
@@ -97,12 +471,12 @@ func Name(name string) YAMLOption {
 // Sources are subject to variable expansion (via the Expand option). To
 // provide a source that remains unexpanded, use the RawSource option.
 func Source(r io.Reader) YAMLOption {
-	all, err := ioutil.ReadAll(r)
-	if err != nil {
-		return failed(err)
-	}
+	load := readOnce(r)
 	return optionFunc(func(c *config) {
-		c.sources = append(c.sources, source{bytes: all})
+		c.sources = append(c.sources, source{load: func(limit int64) ([]source, error) {
+			all, err := load(limit)
+			return []source{{bytes: all}}, err
+		}})
 	})
 }
 
@@ -113,36 +487,343 @@ func Source(r io.Reader) YAMLOption {
 // Raw sources are not subject to variable expansion. To provide a source with
 // variable expansion enabled, use the Source option.
 func RawSource(r io.Reader) YAMLOption {
-	all, err := ioutil.ReadAll(r)
-	if err != nil {
-		return failed(err)
-	}
+	load := readOnce(r)
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{load: func(limit int64) ([]source, error) {
+			all, err := load(limit)
+			return []source{{bytes: all, raw: true}}, err
+		}})
+	})
+}
+
+// _stdin is the reader Stdin consumes. Tests replace it.
+var _stdin io.Reader = os.Stdin
+
+// Stdin adds the process's standard input as a source of YAML configuration
+// named "stdin", so that tools can layer piped configuration over files:
+//   cat override.yaml | tool
+// Like Source, it's subject to variable expansion. Standard input is read to
+// EOF when the provider is constructed; if it's empty, Stdin contributes
+// nothing.
+func Stdin() YAMLOption {
+	load := readOnce(_stdin)
 	return optionFunc(func(c *config) {
-		c.sources = append(c.sources, source{bytes: all, raw: true})
+		c.sources = append(c.sources, source{name: "stdin", load: func(limit int64) ([]source, error) {
+			all, err := load(limit)
+			if err != nil && !errors.Is(err, errSourceTooLarge) {
+				err = fmt.Errorf("couldn't read stdin: %v", err)
+			}
+			return []source{{bytes: all, name: "stdin"}}, err
+		}})
 	})
 }
 
+// SectionedSource splits a stream into named sections and adds each as a
+// source of YAML configuration, in order, so later sections override earlier
+// ones. Sections are separated by lines containing only the YAML document
+// separator, ---, and the stream may begin with a separator. A section is
+// named by a comment of the form
+//   # section: NAME
+// on its first non-blank line; unnamed sections are named by their
+// zero-based position in the stream (for example, "section 1"). Section names
+// identify the sources in errors. Priority, merge, and expansion logic are
+// identical to Source.
+//
+// For example, this stream contributes two sources named defaults and
+// overrides:
+//   # section: defaults
+//   port: 80
+//   ---
+//   # section: overrides
+//   port: 8080
+func SectionedSource(r io.Reader) YAMLOption {
+	load := readOnce(r)
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{load: func(limit int64) ([]source, error) {
+			all, err := load(limit)
+			if err != nil {
+				return nil, err
+			}
+			return splitSections(all), nil
+		}})
+	})
+}
+
+func splitSections(all []byte) []source {
+	var (
+		sections []source
+		current  [][]byte
+	)
+	flush := func() {
+		bs := bytes.Join(current, nil)
+		current = nil
+		name := sectionName(bs)
+		if name == "" {
+			name = fmt.Sprintf("section %d", len(sections))
+		}
+		sections = append(sections, source{bytes: bs, name: name})
+	}
+	lines := bytes.SplitAfter(all, []byte("\n"))
+	for i, line := range lines {
+		if string(bytes.TrimRight(line, " \t\r\n")) != "---" {
+			current = append(current, line)
+			continue
+		}
+		if i == 0 {
+			// A leading separator doesn't end a section.
+			continue
+		}
+		flush()
+	}
+	flush()
+	return sections
+}
+
+func sectionName(section []byte) string {
+	for _, line := range bytes.Split(section, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !bytes.HasPrefix(line, []byte("#")) {
+			return ""
+		}
+		comment := bytes.TrimSpace(bytes.TrimPrefix(line, []byte("#")))
+		if !bytes.HasPrefix(comment, []byte(_sectionPrefix)) {
+			return ""
+		}
+		return string(bytes.TrimSpace(bytes.TrimPrefix(comment, []byte(_sectionPrefix))))
+	}
+	return ""
+}
+
+const _sectionPrefix = "section:"
+
 // File opens a file, uses it as a source of YAML configuration, and closes it
-// once provider construction is complete. Priority, merge, and expansion
-// logic are identical to Source.
+// once provider construction is complete. Relative names are resolved
+// against the directory set by BaseDir, if any. Priority, merge, and
+// expansion logic are identical to Source.
 func File(name string) YAMLOption {
-	f, err := os.Open(name)
+	return optionFunc(func(c *config) {
+		name := c.resolvePath(name)
+		c.sources = append(c.sources, source{name: name, load: func(limit int64) ([]source, error) {
+			all, err := readFile(name, limit)
+			if err != nil {
+				return nil, err
+			}
+			return []source{{bytes: all, name: name, file: name}}, nil
+		}})
+	})
+}
+
+// Environment adds the conventional pair of files for environment-specific
+// configuration: a base file, config.yaml, and an overlay for the current
+// environment, config.<env>.yaml, both in dir. The environment is the value
+// of the envVar environment variable, read when the provider is constructed.
+// For example, with APP_ENV=prod,
+//   Environment("APP_ENV", "config")
+// is equivalent to File("config/config.yaml") followed by
+// File("config/config.prod.yaml"), so the overlay takes priority.
+//
+// The base file is required. The overlay is optional: if envVar is unset or
+// empty, or no file exists for the environment, only the base file is used.
+// Environment names may not contain path separators. A relative dir is
+// resolved like File's names, respecting BaseDir.
+func Environment(envVar, dir string) YAMLOption {
+	if envVar == "" {
+		return failed(errors.New("environment variable name must not be empty"))
+	}
+	return optionFunc(func(c *config) {
+		dir := c.resolvePath(dir)
+		File(filepath.Join(dir, "config.yaml")).apply(c)
+
+		env := os.Getenv(envVar)
+		if env == "" {
+			return
+		}
+		if strings.ContainsAny(env, `/\`) {
+			c.err = multierr.Append(c.err, fmt.Errorf("invalid environment %q in %s: environment names may not contain path separators", env, envVar))
+			return
+		}
+		name := filepath.Join(dir, "config."+env+".yaml")
+		c.sources = append(c.sources, source{name: name, load: func(limit int64) ([]source, error) {
+			all, err := readFile(name, limit)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return []source{{bytes: all, name: name, file: name}}, nil
+		}})
+	})
+}
+
+// BaseDir resolves the relative names of subsequent File, GzipFile,
+// SopsFile, Dir, and Environment sources against the supplied directory
+// rather than the process's working directory, which makes construction
+// independent of where the process was started. Absolute names are
+// unaffected. BaseDir applies only to sources that follow it in the list of
+// options, and a later BaseDir replaces an earlier one; BaseDir("") restores
+// the default.
+func BaseDir(dir string) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.baseDir = dir
+	})
+}
+
+// RelativeFiles adds the named files as sources, like File, but resolves the
+// relative names of all but the first against the directory containing the
+// first. This makes it easy to load a primary configuration file along with
+// its siblings:
+//   config.RelativeFiles("/etc/app/base.yaml", "production.yaml", "secrets/db.yaml")
+// The first name is resolved like any other File source.
+func RelativeFiles(first string, rest ...string) YAMLOption {
+	return optionFunc(func(c *config) {
+		File(first).apply(c)
+		prev := c.baseDir
+		c.baseDir = filepath.Dir(c.resolvePath(first))
+		for _, name := range rest {
+			File(name).apply(c)
+		}
+		c.baseDir = prev
+	})
+}
+
+// resolvePath resolves a relative file name against the configured base
+// directory.
+func (c *config) resolvePath(name string) string {
+	if c.baseDir == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(c.baseDir, name)
+}
+
+// GzipFile is like File, but decompresses the file's contents before using
+// them as a source of YAML configuration. Priority, merge, and expansion
+// logic are identical to Source.
+//
+// MaxSourceBytes limits both the compressed and the decompressed size of the
+// file.
+func GzipFile(name string) YAMLOption {
+	return optionFunc(func(c *config) {
+		name := c.resolvePath(name)
+		c.sources = append(c.sources, source{name: name, load: func(limit int64) ([]source, error) {
+			f, err := os.Open(name)
+			if err != nil {
+				return nil, err
+			}
+			all, err := gunzip(f, limit)
+			if errors.Is(err, errSourceTooLarge) {
+				return nil, multierr.Append(err, f.Close())
+			}
+			if err != nil {
+				return nil, multierr.Append(fmt.Errorf("can't decompress %s: %v", name, err), f.Close())
+			}
+			if err := f.Close(); err != nil {
+				return nil, err
+			}
+			return []source{{bytes: all, name: name, file: name}}, nil
+		}})
+	})
+}
+
+// FS opens the named file in the supplied filesystem and uses its contents as
+// a source of YAML configuration. It's typically used with an embed.FS to
+// ship default configuration inside the binary, with overrides read from
+// disk using File. Names must follow the conventions of io/fs: they're
+// slash-separated and unrooted. Priority, merge, and expansion logic are
+// identical to Source.
+func FS(fsys fs.FS, name string) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{name: name, load: func(limit int64) ([]source, error) {
+			all, err := readFS(fsys, name, limit)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("no file %q in filesystem", name)
+			}
+			if err != nil && !errors.Is(err, errSourceTooLarge) {
+				return nil, fmt.Errorf("can't read %q from filesystem: %v", name, err)
+			}
+			return []source{{bytes: all, name: name}}, err
+		}})
+	})
+}
+
+func readFS(fsys fs.FS, name string, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return fs.ReadFile(fsys, name)
+	}
+	f, err := fsys.Open(name)
 	if err != nil {
-		return failed(err)
+		return nil, err
 	}
-	all, err := ioutil.ReadAll(f)
+	all, err := readAll(f, limit)
 	if err != nil {
-		err = multierr.Append(err, f.Close())
-		return failed(err)
+		return nil, multierr.Append(err, f.Close())
 	}
-	if err := f.Close(); err != nil {
-		return failed(err)
+	return all, f.Close()
+}
+
+// A DecryptFunc decrypts the contents of an encrypted configuration file. The
+// name is provided for context; implementations must not include the
+// plaintext in any errors they return.
+type DecryptFunc func(name string, ciphertext []byte) ([]byte, error)
+
+// SopsFile reads a file encrypted with sops (https://github.com/mozilla/sops),
+// decrypts it with the supplied function, and uses the plaintext as a source
+// of YAML configuration. To avoid forcing a dependency on the sops library,
+// callers must supply the decryption function; with sops, that's typically
+//   func(name string, ciphertext []byte) ([]byte, error) {
+//     return decrypt.Data(ciphertext, "yaml")
+//   }
+// If decryption fails, provider construction returns an error naming the file.
+// Neither the ciphertext nor the plaintext is ever included in errors.
+// Priority, merge, and expansion logic are identical to Source.
+func SopsFile(name string, decrypt DecryptFunc) YAMLOption {
+	if decrypt == nil {
+		return failed(fmt.Errorf("can't decrypt %s: no decryption function supplied", name))
 	}
 	return optionFunc(func(c *config) {
-		c.sources = append(c.sources, source{bytes: all})
+		name := c.resolvePath(name)
+		c.sources = append(c.sources, source{name: name, load: func(limit int64) ([]source, error) {
+			ciphertext, err := readFile(name, limit)
+			if err != nil {
+				return nil, err
+			}
+			plaintext, err := decrypt(name, ciphertext)
+			if err != nil {
+				return nil, fmt.Errorf("can't decrypt %s: %v", name, err)
+			}
+			return []source{{bytes: plaintext, name: name, file: name}}, nil
+		}})
 	})
 }
 
+// _gzipMagic is the header that begins every gzip stream.
+var _gzipMagic = []byte{0x1f, 0x8b}
+
+func gunzip(r io.Reader, limit int64) ([]byte, error) {
+	compressed, err := readAll(r, limit)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(compressed, _gzipMagic) {
+		return nil, errors.New("not gzip-compressed")
+	}
+	z, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("corrupt gzip data: %v", err)
+	}
+	all, err := readAll(z, limit)
+	if errors.Is(err, errSourceTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("corrupt gzip data: %v", err)
+	}
+	return all, nil
+}
+
 // Static serializes a Go data structure to YAML and uses the result as a
 // source. If serialization fails, provider construction will return an error.
 // Priority, merge, and expansion logic are identical to Source.
@@ -156,16 +837,90 @@ func Static(val interface{}) YAMLOption {
 	})
 }
 
-// appendSources appends the given list of YAML sources as-is. Variable
+// StaticNamed is like Static, but also names the source and the provider.
+// It's particularly convenient in tests, where a descriptive name makes
+// error messages easier to read. If multiple StaticNamed options are
+// supplied, the last sets the provider's name.
+//
+// Unlike Static, StaticNamed never panics: values that can't be represented
+// as YAML (for example, functions and channels) make provider construction
+// return an error instead.
+func StaticNamed(name string, val interface{}) YAMLOption {
+	bs, err := marshal(val)
+	if err != nil {
+		return failed(fmt.Errorf("can't marshal %s to YAML: %v", name, err))
+	}
+	return optionFunc(func(c *config) {
+		c.name = name
+		c.sources = append(c.sources, source{bytes: bs, name: name})
+	})
+}
+
+// marshal serializes a value to YAML. Unlike yaml.Marshal, it returns an
+// error rather than panicking when it encounters types YAML can't represent,
+// like functions and channels.
+func marshal(val interface{}) (bs []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("can't marshal %T to YAML: %v", val, r)
+		}
+	}()
+	return yaml.Marshal(val)
+}
+
+// CoerceScalars makes Populate convert string scalars to the numeric or
+// Boolean type of the field being populated, using the parsing rules of the
+// strconv package. This is most useful with variable expansion, since
+// expanded variables are always strings: with CoerceScalars, both
+//   port: ${PORT}
+//   port: "8080"
+// populate an int field. Strings that can't be converted make Populate
+// return an error naming the offending key. (Numbers and Booleans already
+// populate string fields without coercion.)
+func CoerceScalars() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.coerce = true
+	})
+}
+
+// NonEmptySources makes provider construction fail if any source is empty.
+// Sources containing only whitespace and comments are considered empty, but
+// sources with an explicit top-level null are not. This catches truncated or
+// mis-mounted configuration files, which would otherwise silently contribute
+// nothing.
+func NonEmptySources() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.nonEmpty = true
+	})
+}
+
+// appendSources appends the given list of YAML sources as-is, keeping the
+// origins and default markers of the provider they came from. Variable
 // expansion will be performed on all passed sources.
-func appendSources(srcs [][]byte) YAMLOption {
+func appendSources(srcs [][]byte, origins []string, defaults []bool) YAMLOption {
 	return optionFunc(func(c *config) {
-		for _, src := range srcs {
-			c.sources = append(c.sources, source{bytes: src})
+		for i, src := range srcs {
+			c.sources = append(c.sources, source{bytes: src, origin: origins[i], isDefault: defaults[i]})
 		}
 	})
 }
 
+// defaultSource adds the lowest-priority source that holds the defaults
+// supplied to WithDefault.
+func defaultSource(bs []byte) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{bytes: bs, origin: _defaultOrigin, isDefault: true})
+	})
+}
+
+// onClose registers a cleanup function for sources that hold resources (for
+// example, watchers or network connections) beyond provider construction.
+func onClose(f func() error) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.closers = append(c.closers, f)
+	})
+}
+
 func failed(err error) YAMLOption {
 	return optionFunc(func(c *config) {
 		c.err = multierr.Append(c.err, err)
@@ -175,12 +930,150 @@ func failed(err error) YAMLOption {
 type source struct {
 	bytes []byte
 	raw   bool
+	name  string // optional, used only in error messages
+	file  string // the file the source was read from, if any; see EnableIncludes
+
+	// origin, if set, describes the source in YAML.Explain in place of its
+	// name; see appendSources. isDefault marks sources added by WithDefault.
+	origin    string
+	isDefault bool
+
+	// load, if set, reads the source's contents once all options have been
+	// applied, so that reads can respect MaxSourceBytes. It may split the
+	// contents into several sources.
+	load func(limit int64) ([]source, error)
+}
+
+// loadSources replaces sources that haven't been read yet with their
+// contents.
+func loadSources(srcs []source, limit int64) ([]source, error) {
+	loaded := make([]source, 0, len(srcs))
+	var errs error
+	for _, s := range srcs {
+		if s.load == nil {
+			loaded = append(loaded, s)
+			continue
+		}
+		ls, err := s.load(limit)
+		if errors.Is(err, errSourceTooLarge) {
+			err = fmt.Errorf("%s is larger than the maximum of %d bytes", s.describe(len(loaded)), limit)
+		}
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		loaded = append(loaded, ls...)
+	}
+	return loaded, errs
+}
+
+var errSourceTooLarge = errors.New("source too large")
+
+// readAll reads all of r, but fails with errSourceTooLarge instead of
+// reading more than limit bytes. A non-positive limit disables the check.
+func readAll(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	// Read one extra byte to distinguish sources of exactly the limit from
+	// larger ones.
+	all, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(all)) > limit {
+		return nil, errSourceTooLarge
+	}
+	return all, nil
+}
+
+// readFile reads a named file, respecting MaxSourceBytes.
+func readFile(name string, limit int64) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	all, err := readAll(f, limit)
+	if err != nil {
+		return nil, multierr.Append(err, f.Close())
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// readOnce reads r the first time the returned function is called and
+// returns the same contents on subsequent calls, so options wrapping
+// readers can be reused.
+func readOnce(r io.Reader) func(limit int64) ([]byte, error) {
+	var (
+		mu   sync.Mutex
+		done bool
+		all  []byte
+		err  error
+	)
+	return func(limit int64) ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			all, err = readAll(r, limit)
+			done = true
+		}
+		if err == nil && limit > 0 && int64(len(all)) > limit {
+			return nil, errSourceTooLarge
+		}
+		return all, err
+	}
+}
+
+// describe returns a human-readable description of the source for use in
+// error messages. Unnamed sources are identified by their position.
+func (s source) describe(idx int) string {
+	if s.name == "" {
+		return fmt.Sprintf("source at index %d", idx)
+	}
+	return fmt.Sprintf("source %q", s.name)
 }
 
 type config struct {
-	name    string
-	strict  bool
-	sources []source
-	lookup  LookupFunc
-	err     error
+	name           string
+	strict         bool
+	nonEmpty       bool
+	coerce         bool
+	keyMatch       KeyMatchMode
+	strictExpand   bool
+	strictPaths    []string
+	replaceMaps    []string
+	tag            string
+	maxDepth       int
+	maxSeqLen      int
+	maxSourceBytes int64
+	baseDir        string
+	includes       bool
+	defaultTags    bool
+	envTags        bool
+	rejectNulls    bool
+	strictScalars  bool
+	trimStrings    func(string) string
+	registry       *Registry
+	observer       Observer
+	deprecated     map[string]string
+	mergeFunc      MergeResolver
+	normalizeKeys  func(string) string
+	owned          map[string]reflect.Type
+	tags           map[string]TagResolver
+	sources        []source
+	lookup         LookupErrFunc
+	lookupCtx      LookupContextFunc // lookup, for use with NewYAMLContext
+	cacheLookups   bool
+	collectStats   bool
+	envAllowlist   map[string]struct{}
+	noExpand       []*regexp.Regexp
+	exactNumbers   bool
+	friendly       bool
+	verbatim       []string
+	verbatimValues []verbatimValue
+	closers        []func() error // run by YAML.Close
+	err            error
 }
@@ -21,11 +21,18 @@
 package config
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -111,3 +118,744 @@ func TestSourceErrors(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestNonEmptySources(t *testing.T) {
+	tests := []struct {
+		desc      string
+		source    string
+		expectErr bool
+	}{
+		{"empty", "", true},
+		{"whitespace only", "  \n\n   ", true},
+		{"comment only", "# nothing to see here\n", true},
+		{"explicit null", "~", false},
+		{"valid", "foo: bar", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := NewYAML(
+				Source(strings.NewReader("foo: baz")),
+				Source(strings.NewReader(tt.source)),
+				NonEmptySources(),
+			)
+			if tt.expectErr {
+				require.Error(t, err, "expected provider construction to fail")
+				assert.Contains(t, err.Error(), "source at index 1 is empty", "unexpected error message")
+				return
+			}
+			require.NoError(t, err, "couldn't construct provider")
+		})
+	}
+
+	t.Run("named file", func(t *testing.T) {
+		f, err := ioutil.TempFile("" /* dir */, "test-non-empty-sources" /* prefix */)
+		require.NoError(t, err, "couldn't create temporary file")
+		defer os.Remove(f.Name())
+		require.NoError(t, f.Close(), "couldn't close temporary file")
+
+		_, err = NewYAML(File(f.Name()), NonEmptySources())
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), f.Name(), "expected error to name the empty file")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader("")))
+		require.NoError(t, err, "empty sources should be allowed by default")
+	})
+}
+
+func TestGzipFile(t *testing.T) {
+	dir, err := ioutil.TempDir("" /* dir */, "test-gzip-file" /* prefix */)
+	require.NoError(t, err, "couldn't create temporary directory")
+	defer os.RemoveAll(dir)
+
+	compressed := &bytes.Buffer{}
+	z := gzip.NewWriter(compressed)
+	_, err = z.Write([]byte("foo: bar"))
+	require.NoError(t, err, "couldn't compress YAML")
+	require.NoError(t, z.Close(), "couldn't flush compressed YAML")
+
+	write := func(name string, contents []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, contents, 0644), "couldn't write %s", name)
+		return path
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		p, err := NewYAML(GzipFile(write("valid.yaml.gz", compressed.Bytes())))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "bar", p.Get("foo").Value(), "unexpected value")
+	})
+
+	t.Run("not gzip", func(t *testing.T) {
+		_, err := NewYAML(GzipFile(write("plain.yaml", []byte("foo: bar"))))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), "not gzip-compressed", "unexpected error message")
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		truncated := compressed.Bytes()[:compressed.Len()-4]
+		_, err := NewYAML(GzipFile(write("truncated.yaml.gz", truncated)))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), "corrupt gzip data", "unexpected error message")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := NewYAML(GzipFile(filepath.Join(dir, "not_there.yaml.gz")))
+		require.Error(t, err, "expected provider construction to fail")
+	})
+}
+
+func TestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/base.yaml": &fstest.MapFile{Data: []byte("foo: bar\nbaz: quux")},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		p, err := NewYAML(FS(fsys, "config/base.yaml"), Source(strings.NewReader("baz: override")))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "bar", p.Get("foo").Value(), "unexpected value from embedded source")
+		assert.Equal(t, "override", p.Get("baz").Value(), "later source should take priority")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := NewYAML(FS(fsys, "config/not_there.yaml"))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), `no file "config/not_there.yaml" in filesystem`, "unexpected error message")
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		_, err := NewYAML(FS(fsys, "config"))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), "can't read", "unexpected error message")
+	})
+
+}
+
+func TestSopsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("" /* dir */, "test-sops-file" /* prefix */)
+	require.NoError(t, err, "couldn't create temporary directory")
+	defer os.RemoveAll(dir)
+
+	const secret = "password: hunter2"
+	encrypted := filepath.Join(dir, "secrets.enc.yaml")
+	require.NoError(t, ioutil.WriteFile(encrypted, []byte("ENC["+secret+"]"), 0644), "couldn't write encrypted file")
+
+	// The fake decryptor unwraps ENC[...].
+	decrypt := func(name string, ciphertext []byte) ([]byte, error) {
+		s := string(ciphertext)
+		if !strings.HasPrefix(s, "ENC[") || !strings.HasSuffix(s, "]") {
+			return nil, errors.New("no matching key")
+		}
+		return []byte(strings.TrimSuffix(strings.TrimPrefix(s, "ENC["), "]")), nil
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("password: default\nuser: admin")),
+			SopsFile(encrypted, decrypt),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "hunter2", p.Get("password").Value(), "unexpected decrypted value")
+		assert.Equal(t, "admin", p.Get("user").Value(), "expected decrypted source to merge")
+	})
+
+	t.Run("decryption failure", func(t *testing.T) {
+		plain := filepath.Join(dir, "plain.yaml")
+		require.NoError(t, ioutil.WriteFile(plain, []byte(secret), 0644), "couldn't write plaintext file")
+		_, err := NewYAML(SopsFile(plain, decrypt))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), "can't decrypt "+plain+": no matching key", "unexpected error message")
+		assert.NotContains(t, err.Error(), "hunter2", "error shouldn't contain plaintext")
+	})
+
+	t.Run("nil decryptor", func(t *testing.T) {
+		_, err := NewYAML(SopsFile(encrypted, nil))
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), "no decryption function", "unexpected error message")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := NewYAML(SopsFile(filepath.Join(dir, "not_there.yaml"), decrypt))
+		require.Error(t, err, "expected provider construction to fail")
+	})
+}
+
+func TestSectionedSource(t *testing.T) {
+	const stream = `---
+# section: defaults
+port: 80
+host: localhost
+---
+# section: overrides
+port: 8080
+---
+debug: true
+`
+
+	t.Run("merge", func(t *testing.T) {
+		p, err := NewYAML(SectionedSource(strings.NewReader(stream)))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("port").Value(), "later sections should take priority")
+		assert.Equal(t, "localhost", p.Get("host").Value(), "expected sections to be merged")
+		assert.Equal(t, true, p.Get("debug").Value(), "expected unnamed section to be merged")
+	})
+
+	t.Run("names", func(t *testing.T) {
+		cfg := &config{}
+		SectionedSource(strings.NewReader(stream)).apply(cfg)
+		require.NoError(t, cfg.err, "unexpected error applying option")
+		sources, err := loadSources(cfg.sources, 0 /* limit */)
+		require.NoError(t, err, "unexpected error reading sections")
+		names := make([]string, len(sources))
+		for i, s := range sources {
+			names[i] = s.name
+		}
+		assert.Equal(t, []string{"defaults", "overrides", "section 2"}, names, "unexpected section names")
+	})
+
+	t.Run("names in errors", func(t *testing.T) {
+		_, err := NewYAML(
+			SectionedSource(strings.NewReader("# section: base\nfoo: bar\n---\n# section: empty\n")),
+			NonEmptySources(),
+		)
+		require.Error(t, err, "expected provider construction to fail")
+		assert.Contains(t, err.Error(), `source "empty" is empty`, "expected error to name the section")
+	})
+
+	t.Run("single section", func(t *testing.T) {
+		cfg := &config{}
+		SectionedSource(strings.NewReader("foo: bar")).apply(cfg)
+		sources, err := loadSources(cfg.sources, 0 /* limit */)
+		require.NoError(t, err, "unexpected error reading sections")
+		require.Len(t, sources, 1, "unexpected number of sections")
+		assert.Equal(t, "section 0", sources[0].name, "unexpected section name")
+		assert.Equal(t, "foo: bar", string(sources[0].bytes), "unexpected section contents")
+	})
+}
+
+func TestStdin(t *testing.T) {
+	withStdin := func(t testing.TB, contents string) {
+		orig := _stdin
+		_stdin = bytes.NewReader([]byte(contents))
+		t.Cleanup(func() { _stdin = orig })
+	}
+
+	t.Run("layered over file", func(t *testing.T) {
+		dir := writeDir(t, map[string]string{"base.yaml": "port: 80\nhost: localhost\n"})
+		withStdin(t, "port: 8080\n")
+		p, err := NewYAML(File(filepath.Join(dir, "base.yaml")), Stdin())
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("port").Value(), "expected stdin to override file")
+		assert.Equal(t, "localhost", p.Get("host").Value(), "expected file values to remain")
+
+		res, err := p.Explain("port")
+		require.NoError(t, err, "couldn't explain key")
+		assert.Equal(t, "stdin", res.Source, "expected stdin to be named in origin tracking")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		withStdin(t, "")
+		p, err := NewYAML(Static(map[string]int{"port": 80}), Stdin())
+		require.NoError(t, err, "expected empty stdin to be allowed")
+		assert.Equal(t, 80, p.Get("port").Value(), "expected empty stdin to contribute nothing")
+
+		withStdin(t, "")
+		_, err = NewYAML(Stdin(), NonEmptySources())
+		require.Error(t, err, "expected NonEmptySources to reject empty stdin")
+		assert.Contains(t, err.Error(), `source "stdin" is empty`, "expected error to name stdin")
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		orig := _stdin
+		_stdin = iotest.ErrReader(errors.New("broken pipe"))
+		defer func() { _stdin = orig }()
+		_, err := NewYAML(Stdin())
+		require.Error(t, err, "expected read error")
+		assert.Contains(t, err.Error(), "couldn't read stdin: broken pipe", "unexpected error message")
+	})
+}
+
+func TestEnvironment(t *testing.T) {
+	const envVar = "CONFIG_TEST_ENVIRONMENT"
+	dir := writeDir(t, map[string]string{
+		"config.yaml":      "name: api\nport: 80\ndebug: false\n",
+		"config.dev.yaml":  "debug: true\n",
+		"config.prod.yaml": "port: 443\n",
+	})
+	withEnv := func(t testing.TB, env string) {
+		require.NoError(t, os.Setenv(envVar, env), "couldn't set %s", envVar)
+		t.Cleanup(func() { os.Unsetenv(envVar) })
+	}
+	type cfg struct {
+		Name  string
+		Port  int
+		Debug bool
+	}
+	populate := func(t testing.TB, opts ...YAMLOption) cfg {
+		p, err := NewYAML(opts...)
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		require.NoError(t, p.Get(Root).Populate(&c), "couldn't populate")
+		return c
+	}
+
+	t.Run("dev", func(t *testing.T) {
+		withEnv(t, "dev")
+		assert.Equal(t, cfg{"api", 80, true}, populate(t, Environment(envVar, dir)), "expected dev overlay")
+	})
+
+	t.Run("prod", func(t *testing.T) {
+		withEnv(t, "prod")
+		assert.Equal(t, cfg{"api", 443, false}, populate(t, Environment(envVar, dir)), "expected prod overlay")
+	})
+
+	t.Run("missing overlay", func(t *testing.T) {
+		withEnv(t, "staging")
+		assert.Equal(t, cfg{"api", 80, false}, populate(t, Environment(envVar, dir)), "expected base file alone")
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		os.Unsetenv(envVar)
+		assert.Equal(t, cfg{"api", 80, false}, populate(t, Environment(envVar, dir)), "expected base file alone")
+	})
+
+	t.Run("base dir and priority", func(t *testing.T) {
+		withEnv(t, "prod")
+		c := populate(t,
+			BaseDir(filepath.Dir(dir)),
+			Environment(envVar, filepath.Base(dir)),
+			Static(map[string]int{"port": 8443}),
+		)
+		assert.Equal(t, 8443, c.Port, "expected later sources to override the overlay")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewYAML(Environment(envVar, filepath.Join(dir, "missing")))
+		assert.Error(t, err, "expected missing base file to fail")
+
+		withEnv(t, "../prod")
+		_, err = NewYAML(Environment(envVar, dir))
+		require.Error(t, err, "expected environment with path separators to fail")
+		assert.Contains(t, err.Error(), "may not contain path separators", "unexpected error message")
+
+		_, err = NewYAML(Environment("", dir))
+		assert.Error(t, err, "expected empty variable name to fail")
+	})
+}
+
+func TestMaxDepth(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("{a: ", depth) + "1" + strings.Repeat("}", depth)
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader(nested(_defaultMaxDepth))))
+		require.NoError(t, err, "expected configuration at the limit to load")
+
+		_, err = NewYAML(Source(strings.NewReader(nested(_defaultMaxDepth + 1))))
+		require.Error(t, err, "expected deeply nested configuration to fail")
+		assert.Contains(t, err.Error(), "exceeds the maximum nesting depth of 1000", "unexpected error message")
+	})
+
+	t.Run("custom limit", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader("ok: 1")), Source(strings.NewReader(nested(3))), MaxDepth(2))
+		require.Error(t, err, "expected nested configuration to fail")
+		assert.Contains(t, err.Error(), `value at key "a.a.a" exceeds the maximum nesting depth of 2`, "unexpected error message")
+
+		p, err := NewYAML(Source(strings.NewReader(nested(3))), MaxDepth(3))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 1, p.Get("a.a.a").Value(), "unexpected value")
+	})
+
+	t.Run("preserved by WithDefault", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(nested(3))), MaxDepth(3))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.withDefault(map[string]interface{}{"b": map[string]interface{}{"c": 1}})
+		require.NoError(t, err, "couldn't apply defaults")
+
+		p, err = NewYAML(Source(strings.NewReader("a: 1")), MaxDepth(2))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.withDefault(map[string]interface{}{"b": map[string]interface{}{"c": []int{1}}})
+		require.Error(t, err, "expected deeply nested defaults to fail")
+		assert.Contains(t, err.Error(), `value at key "b.c.0" exceeds the maximum nesting depth of 2`, "unexpected error message")
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := NewYAML(MaxDepth(0))
+		require.Error(t, err, "expected non-positive limit to fail")
+		assert.Contains(t, err.Error(), "maximum depth must be positive", "unexpected error message")
+	})
+}
+
+func TestMaxSequenceLen(t *testing.T) {
+	list := func(n int) string {
+		elems := make([]string, n)
+		for i := range elems {
+			elems[i] = strconv.Itoa(i)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	}
+
+	t.Run("unbounded by default", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("hosts: " + list(10000))))
+		require.NoError(t, err, "couldn't construct provider")
+		var hosts []int
+		require.NoError(t, p.Get("hosts").Populate(&hosts), "couldn't populate")
+		assert.Len(t, hosts, 10000, "unexpected number of elements")
+	})
+
+	t.Run("under the limit", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("hosts: "+list(100))), MaxSequenceLen(100))
+		require.NoError(t, err, "expected a sequence at the limit to load")
+		var hosts []int
+		require.NoError(t, p.Get("hosts").Populate(&hosts), "couldn't populate")
+		assert.Len(t, hosts, 100, "unexpected number of elements")
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		_, err := NewYAML(
+			Source(strings.NewReader("hosts: [a]")),
+			Source(strings.NewReader("servers:\n  - ports: "+list(101))),
+			MaxSequenceLen(100),
+		)
+		require.Error(t, err, "expected an overlong sequence to fail")
+		assert.Contains(t, err.Error(), `sequence at key "servers.0.ports" has 101 elements, exceeding the maximum of 100`, "unexpected error message")
+	})
+
+	t.Run("preserved by WithDefault", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("hosts: [a]")), MaxSequenceLen(2))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.withDefault(map[string][]int{"ports": {1, 2, 3}})
+		require.Error(t, err, "expected overlong defaults to fail")
+		assert.Contains(t, err.Error(), `sequence at key "ports" has 3 elements`, "unexpected error message")
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := NewYAML(MaxSequenceLen(0))
+		require.Error(t, err, "expected non-positive limit to fail")
+		assert.Contains(t, err.Error(), "maximum sequence length must be positive", "unexpected error message")
+	})
+}
+
+// endlessReader produces an unbounded stream of YAML comment lines, counting
+// the bytes it's asked for.
+type endlessReader struct{ read int }
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		if i%10 == 0 {
+			p[i] = '#'
+		} else if i%10 == 9 {
+			p[i] = '\n'
+		} else {
+			p[i] = ' '
+		}
+	}
+	r.read += len(p)
+	return len(p), nil
+}
+
+func TestMaxSourceBytes(t *testing.T) {
+	const limit = 16
+	src := "foo: 1234567890" // 15 bytes
+
+	t.Run("under and at limit", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(src)),
+			RawSource(strings.NewReader(src+"1")),
+			MaxSourceBytes(limit),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 12345678901, p.Get("foo").Value(), "unexpected value")
+	})
+
+	t.Run("over limit", func(t *testing.T) {
+		_, err := NewYAML(
+			Source(strings.NewReader(src)),
+			Source(strings.NewReader(src+"12")),
+			MaxSourceBytes(limit),
+		)
+		require.Error(t, err, "expected oversized source to fail")
+		assert.Contains(t, err.Error(), "source at index 1 is larger than the maximum of 16 bytes", "unexpected error message")
+	})
+
+	t.Run("streaming reader", func(t *testing.T) {
+		r := &endlessReader{}
+		_, err := NewYAML(Source(r), MaxSourceBytes(1024))
+		require.Error(t, err, "expected unbounded source to fail")
+		assert.Contains(t, err.Error(), "larger than the maximum of 1024 bytes", "unexpected error message")
+		assert.True(t, r.read <= 4096, "expected reads to stop near the limit, read %d bytes", r.read)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		dir, err := ioutil.TempDir("" /* dir */, "test-max-source-bytes" /* prefix */)
+		require.NoError(t, err, "couldn't create temporary directory")
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "big.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte(src+"12"), 0644), "couldn't write file")
+		_, err = NewYAML(File(path), MaxSourceBytes(limit))
+		require.Error(t, err, "expected oversized file to fail")
+		assert.Contains(t, err.Error(), fmt.Sprintf("source %q is larger than the maximum of 16 bytes", path), "unexpected error message")
+
+		_, err = NewYAML(FS(fstest.MapFS{"big.yaml": {Data: []byte(src + "12")}}, "big.yaml"), MaxSourceBytes(limit))
+		require.Error(t, err, "expected oversized file to fail")
+		assert.Contains(t, err.Error(), `source "big.yaml" is larger than the maximum of 16 bytes`, "unexpected error message")
+
+		// Highly compressible contents stay small on disk but not in memory.
+		var buf bytes.Buffer
+		z := gzip.NewWriter(&buf)
+		_, err = z.Write([]byte("foo: bar\n" + strings.Repeat("# padding\n", 1000)))
+		require.NoError(t, err, "couldn't compress contents")
+		require.NoError(t, z.Close(), "couldn't compress contents")
+		gz := filepath.Join(dir, "big.yaml.gz")
+		require.NoError(t, ioutil.WriteFile(gz, buf.Bytes(), 0644), "couldn't write file")
+		require.True(t, buf.Len() < 1024, "expected compressed file to be under the limit")
+		_, err = NewYAML(GzipFile(gz), MaxSourceBytes(1024))
+		require.Error(t, err, "expected oversized decompressed file to fail")
+		assert.Contains(t, err.Error(), "is larger than the maximum of 1024 bytes", "unexpected error message")
+	})
+
+	t.Run("reused option", func(t *testing.T) {
+		opt := Source(strings.NewReader(src))
+		_, err := NewYAML(opt)
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = NewYAML(opt, MaxSourceBytes(10))
+		require.Error(t, err, "expected limit to apply to previously read source")
+		p, err := NewYAML(opt)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 1234567890, p.Get("foo").Value(), "expected reused option to keep contents")
+	})
+
+	t.Run("in-memory sources", func(t *testing.T) {
+		_, err := NewYAML(Static(map[string]string{"foo": src}), MaxSourceBytes(1))
+		assert.NoError(t, err, "expected in-memory sources to be unlimited")
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := NewYAML(MaxSourceBytes(0))
+		require.Error(t, err, "expected non-positive limit to fail")
+		assert.Contains(t, err.Error(), "maximum source size must be positive", "unexpected error message")
+	})
+}
+
+func TestBaseDir(t *testing.T) {
+	dir := writeDir(t, map[string]string{
+		"base.yaml":        "name: base\nport: 80",
+		"override.yaml":    "port: 8080",
+		"conf.d/tls.yaml":  "tls: true",
+		"nested/more.yaml": "more: true",
+	})
+	other := writeDir(t, map[string]string{"other.yaml": "other: true"})
+
+	t.Run("relative and absolute names", func(t *testing.T) {
+		p, err := NewYAML(
+			BaseDir(dir),
+			File("base.yaml"),
+			File(filepath.Join(other, "other.yaml")),
+			Dir("conf.d"),
+			File("override.yaml"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, map[interface{}]interface{}{
+			"name":  "base",
+			"port":  8080,
+			"tls":   true,
+			"other": true,
+		}, p.Get(Root).Value(), "unexpected contents")
+	})
+
+	t.Run("only subsequent sources", func(t *testing.T) {
+		_, err := NewYAML(File("override.yaml"), BaseDir(dir))
+		require.Error(t, err, "expected preceding file to resolve against the working directory")
+
+		_, err = NewYAML(BaseDir(dir), BaseDir(""), File("override.yaml"))
+		assert.Error(t, err, "expected empty base directory to restore the default")
+	})
+
+	t.Run("errors name resolved paths", func(t *testing.T) {
+		_, err := NewYAML(BaseDir(dir), File("base.yaml"), MaxSourceBytes(4))
+		require.Error(t, err, "expected oversized file to fail")
+		assert.Contains(t, err.Error(), filepath.Join(dir, "base.yaml"), "expected error to name the resolved path")
+	})
+
+	t.Run("relative to first file", func(t *testing.T) {
+		p, err := NewYAML(RelativeFiles(filepath.Join(dir, "base.yaml"), "override.yaml", "nested/more.yaml"))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("port").Value(), "expected sibling to override")
+		assert.Equal(t, true, p.Get("more").Value(), "expected nested sibling")
+
+		p, err = NewYAML(BaseDir(filepath.Dir(dir)), RelativeFiles(filepath.Join(filepath.Base(dir), "base.yaml"), "override.yaml"), File(filepath.Join(filepath.Base(dir), "nested/more.yaml")))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("port").Value(), "expected first file to resolve against BaseDir")
+		assert.Equal(t, true, p.Get("more").Value(), "expected BaseDir to be restored after RelativeFiles")
+	})
+}
+
+func TestRejectNullStructs(t *testing.T) {
+	type server struct {
+		Port int `yaml:"port"`
+	}
+	type Common struct {
+		TLS server `yaml:"tls"`
+	}
+
+	tests := []struct {
+		desc   string
+		src    string
+		target func() interface{}
+		want   string // error substring, or empty for success
+	}{
+		{
+			desc:   "null struct",
+			src:    "server: null",
+			target: func() interface{} { return &struct{ Server server }{} },
+			want:   `from null at key "server"`,
+		},
+		{
+			desc:   "null pointer to struct",
+			src:    "server: null",
+			target: func() interface{} { return &struct{ Server *server }{} },
+		},
+		{
+			desc:   "null map",
+			src:    "labels: ~",
+			target: func() interface{} { return &struct{ Labels map[string]string }{} },
+			want:   `from null at key "labels"`,
+		},
+		{
+			desc:   "null map value",
+			src:    "servers: {web: null}",
+			target: func() interface{} { return &struct{ Servers map[string]server }{} },
+			want:   `from null at key "servers.web"`,
+		},
+		{
+			desc:   "null sequence element",
+			src:    "servers: [{port: 80}, null]",
+			target: func() interface{} { return &struct{ Servers []server }{} },
+			want:   `from null at key "servers.1"`,
+		},
+		{
+			desc: "null in embedded struct",
+			src:  "tls: null",
+			target: func() interface{} {
+				return &struct {
+					Common
+					Port int
+				}{}
+			},
+			want: `from null at key "tls"`,
+		},
+		{
+			desc: "null scalar and slice",
+			src:  "port: null\nhosts: null",
+			target: func() interface{} {
+				return &struct {
+					Port  int
+					Hosts []string
+				}{}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			lenient, err := NewYAML(Source(strings.NewReader(tt.src)))
+			require.NoError(t, err, "couldn't construct provider")
+			assert.NoError(t, lenient.Get(Root).Populate(tt.target()), "expected nulls to be allowed by default")
+
+			p, err := NewYAML(Source(strings.NewReader(tt.src)), RejectNullStructs())
+			require.NoError(t, err, "couldn't construct provider")
+			err = p.Get(Root).Populate(tt.target())
+			if tt.want == "" {
+				assert.NoError(t, err, "unexpected error")
+				return
+			}
+			require.Error(t, err, "expected null to be rejected")
+			assert.Contains(t, err.Error(), tt.want, "unexpected error message")
+		})
+	}
+
+	t.Run("pointer stays nil", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("server: null")), RejectNullStructs())
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg struct{ Server *server }
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Nil(t, cfg.Server, "expected null to leave pointer nil")
+	})
+}
+
+func TestMergeFunc(t *testing.T) {
+	var conflicts []string
+	maxWins := func(path []string, lower, higher interface{}) (interface{}, bool) {
+		conflicts = append(conflicts, strings.Join(path, _separator))
+		l, lok := lower.(int)
+		h, hok := higher.(int)
+		if !lok || !hok || l < h {
+			return nil, false
+		}
+		return l, true
+	}
+
+	p, err := NewYAML(
+		Source(strings.NewReader("replicas: 5\nname: base")),
+		Source(strings.NewReader("replicas: 3\nname: prod\nregion: us-east")),
+		MergeFunc(maxWins),
+	)
+	require.NoError(t, err, "couldn't construct provider")
+	assert.Equal(t, 5, p.Get("replicas").Value(), "expected larger replica count to win")
+	assert.Equal(t, "prod", p.Get("name").Value(), "expected declined conflict to use the higher-priority value")
+	assert.ElementsMatch(t, []string{"replicas", "name"}, conflicts, "resolver should only see conflicting paths")
+
+	conflicts = nil
+	d, err := p.withDefault(map[string]int{"replicas": 10})
+	require.NoError(t, err, "couldn't apply defaults")
+	assert.Equal(t, 10, d.Get("replicas").Value(), "expected WithDefault to use the resolver")
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	base := strings.NewReader("Server:\n  Port: 80\n  Hosts: [a]\n")
+	override := strings.NewReader("server:\n  port: 8080\n  tls: {enabled: true}\n")
+	p, err := NewYAML(Source(base), Source(override), NormalizeKeys(strings.ToLower))
+	require.NoError(t, err, "couldn't construct provider")
+
+	var cfg struct {
+		Server struct {
+			Port  int
+			Hosts []string
+			TLS   struct {
+				Enabled bool
+			}
+		}
+	}
+	require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+	assert.Equal(t, 8080, cfg.Server.Port, "expected differently-cased keys to merge")
+	assert.Equal(t, []string{"a"}, cfg.Server.Hosts, "expected lower-priority keys to remain")
+	assert.True(t, cfg.Server.TLS.Enabled, "expected higher-priority keys to be added")
+	assert.False(t, p.Get("Server").HasValue(), "expected only normalized keys to remain")
+
+	t.Run("collisions", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader("Port: 80\nport: 81")), NormalizeKeys(strings.ToLower))
+		require.Error(t, err, "expected collision to fail in strict mode")
+		assert.Contains(t, err.Error(), `duplicate key "port" after normalizing keys`, "unexpected error message")
+
+		p, err := NewYAML(Source(strings.NewReader("Port: 80\nport: 81")), NormalizeKeys(strings.ToLower), Permissive())
+		require.NoError(t, err, "expected collision to be allowed in permissive mode")
+		assert.Equal(t, 81, p.Get("port").Value(), "expected later key to win")
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		withDefault, err := p.Get(Root).WithDefault(map[string]interface{}{
+			"SERVER": map[string]int{"Timeout": 5},
+		})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.Equal(t, 5, withDefault.Get("server.timeout").Value(), "expected defaults to be normalized")
+		assert.Equal(t, 8080, withDefault.Get("server.port").Value(), "expected configuration to be preserved")
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		_, err := NewYAML(NormalizeKeys(nil))
+		assert.Error(t, err, "expected nil normalizer to fail")
+	})
+}
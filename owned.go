@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.uber.org/multierr"
+)
+
+// OwnedSections declares the sections of a shared configuration that the
+// caller owns, so that they can be validated strictly without rejecting
+// sections owned by others. Each key is the name of a top-level section (or
+// a dotted path), and each value is a struct, or pointer to a struct, whose
+// type describes the section. See YAML.ValidateOwned. The values are only
+// used for their types, and are never modified. Multiple OwnedSections
+// options are combined.
+func OwnedSections(sections map[string]interface{}) YAMLOption {
+	for name, target := range sections {
+		if target == nil {
+			return failed(fmt.Errorf("owned section %q has no target", name))
+		}
+		if t := derefType(reflect.TypeOf(target)); t.Kind() != reflect.Struct {
+			return failed(fmt.Errorf("owned section %q must have a struct target, got %T", name, target))
+		}
+	}
+	return optionFunc(func(c *config) {
+		if c.owned == nil {
+			c.owned = make(map[string]reflect.Type, len(sections))
+		}
+		for name, target := range sections {
+			c.owned[name] = derefType(reflect.TypeOf(target))
+		}
+	})
+}
+
+// ownedTypes carries owned sections over to providers created by
+// WithDefault.
+func ownedTypes(types map[string]reflect.Type) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.owned = types
+	})
+}
+
+// ValidateOwned strictly populates a fresh value of each owned section's
+// type (see OwnedSections), rejecting unknown keys within the section even if
+// the provider is permissive, and returns all the resulting errors combined.
+// Top-level sections that aren't owned are ignored entirely, and owned
+// sections with no configuration are valid. Providers without owned sections
+// are always valid.
+func (y *YAML) ValidateOwned() error {
+	if len(y.owned) == 0 {
+		return nil
+	}
+	strict := *y
+	strict.strict = true
+	strict.strictPaths = nil
+
+	names := make([]string, 0, len(y.owned))
+	for name := range y.owned {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs error
+	for _, name := range names {
+		target := reflect.New(y.owned[name]).Interface()
+		if err := strict.populate(strings.Split(name, _separator), target); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid section %q: %v", name, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOwned(t *testing.T) {
+	type payments struct {
+		Currency string `yaml:"currency"`
+		Retries  int    `yaml:"retries"`
+	}
+	type search struct {
+		Index string `yaml:"index"`
+	}
+	owned := OwnedSections(map[string]interface{}{
+		"payments": payments{},
+		"search":   &search{},
+	})
+	provider := func(t testing.TB, src string, opts ...YAMLOption) *YAML {
+		p, err := NewYAML(append([]YAMLOption{Source(strings.NewReader(src)), Permissive(), owned}, opts...)...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		p := provider(t, "payments: {currency: USD, retries: 3}\nsearch: {index: products}\nbilling: {anything: goes}")
+		assert.NoError(t, p.ValidateOwned(), "expected unrelated sections to be ignored")
+	})
+
+	t.Run("missing section", func(t *testing.T) {
+		p := provider(t, "payments: {currency: USD}")
+		assert.NoError(t, p.ValidateOwned(), "expected absent owned sections to be valid")
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		p := provider(t, "payments: {currency: USD, retires: 3}\nsearch: {index: products, shards: 2}\nbilling: {anything: goes}")
+		var cfg struct {
+			Payments payments `yaml:"payments"`
+		}
+		require.NoError(t, p.Get(Root).Populate(&cfg), "expected permissive populate to succeed")
+
+		err := p.ValidateOwned()
+		require.Error(t, err, "expected unknown keys in owned sections to fail")
+		msg := err.Error()
+		assert.Contains(t, msg, `invalid section "payments"`, "expected payments error")
+		assert.Contains(t, msg, "retires", "expected payments error to name the unknown key")
+		assert.Contains(t, msg, `invalid section "search"`, "expected search error")
+		assert.Contains(t, msg, "shards", "expected search error to name the unknown key")
+		assert.NotContains(t, msg, "billing", "expected unowned section to be ignored")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		p := provider(t, "payments: {retries: many}")
+		err := p.ValidateOwned()
+		require.Error(t, err, "expected invalid value to fail")
+		assert.Contains(t, err.Error(), `couldn't populate key "payments.retries"`, "unexpected error message")
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		p := provider(t, "payments: {currency: USD}")
+		d, err := p.withDefault(map[string]interface{}{"search": map[string]int{"replicas": 2}})
+		require.NoError(t, err, "couldn't apply defaults")
+		assert.Error(t, d.ValidateOwned(), "expected WithDefault to keep owned sections")
+	})
+
+	t.Run("invalid targets", func(t *testing.T) {
+		_, err := NewYAML(OwnedSections(map[string]interface{}{"a": nil}))
+		assert.Error(t, err, "expected error for nil target")
+		_, err = NewYAML(OwnedSections(map[string]interface{}{"a": 42}))
+		assert.Error(t, err, "expected error for non-struct target")
+	})
+
+	t.Run("no owned sections", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("a: 1")))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.NoError(t, p.ValidateOwned(), "expected providers without owned sections to be valid")
+	})
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetPointer returns the value at an RFC 6901 JSON Pointer, like
+// "/server/port", for interoperating with tools that describe configuration
+// diffs and patches that way. The empty pointer refers to the whole
+// configuration. Within each reference token, "~1" stands for a slash and
+// "~0" for a tilde, so "/paths/~1v1~1users" refers to the "/v1/users" key of
+// paths. Tokens are matched like Get's path segments: within sequences, they
+// are zero-based indices without leading zeros or signs, and the KeyMatch
+// option controls how they match non-string keys. Unlike Get's keys, tokens
+// may contain periods.
+//
+// It's an error if the pointer is malformed or doesn't refer to any
+// configuration; in the latter case, the error is a *NotFoundError whose Key
+// is the pointer.
+func (y *YAML) GetPointer(pointer string) (Value, error) {
+	path, err := parsePointer(pointer)
+	if err != nil {
+		return Value{}, err
+	}
+	if err := checkIndices(pointer, y.contents, path, y.keyMatch); err != nil {
+		return Value{}, err
+	}
+	// Unlike get, treat "/" as the empty key rather than the root.
+	if y.observer != nil {
+		y.observer.OnGet(strings.Join(path, _separator))
+	}
+	if _, ok := y.at(path); !ok {
+		return Value{}, &NotFoundError{Provider: y.name, Key: pointer}
+	}
+	return Value{path: path, provider: y}, nil
+}
+
+// parsePointer splits a JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON Pointer %q: must be empty or start with a slash", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("invalid JSON Pointer %q: ~ must be followed by 0 or 1", pointer)
+			}
+		}
+		// Unescaping ~1 first keeps "~01" from becoming a slash.
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// checkIndices rejects tokens that address sequence elements but aren't
+// canonical array indices: RFC 6901 doesn't allow leading zeros or signs, so
+// neither "01" nor "+1" refers to the second element.
+func checkIndices(pointer string, root interface{}, path []string, mode KeyMatchMode) error {
+	cur := root
+	for _, token := range path {
+		switch n := cur.(type) {
+		case []interface{}:
+			// "-" refers to the element after the last, which never exists.
+			if token != "-" && !isArrayIndex(token) {
+				return fmt.Errorf("invalid JSON Pointer %q: %q isn't an array index", pointer, token)
+			}
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx >= len(n) {
+				return nil
+			}
+			cur = n[idx]
+		case map[interface{}]interface{}:
+			key, ok := mode.resolve(n, token)
+			if !ok {
+				return nil
+			}
+			cur = n[key]
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// isArrayIndex reports whether a token is "0" or a decimal number without
+// leading zeros.
+func isArrayIndex(token string) bool {
+	if token == "" || (token[0] == '0' && len(token) > 1) {
+		return false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPointer(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader(strings.Join([]string{
+		`server: {port: 8080, tls: {enabled: true}}`,
+		`peers: [{host: alpha}, {host: beta}]`,
+		`paths: {/v1/users: users, a~b: tilde, ~1: literal}`,
+		`"": empty`,
+		`version.major: 2`,
+		`version: {major: 3}`,
+	}, "\n"))))
+	require.NoError(t, err, "couldn't construct provider")
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+	}{
+		{"/server/port", 8080},
+		{"/server/tls/enabled", true},
+		{"/peers/1/host", "beta"},
+		{"/paths/~1v1~1users", "users"},
+		{"/paths/a~0b", "tilde"},
+		{"/paths/~01", "literal"},
+		{"/", "empty"},
+		{"/version.major", 2},
+		{"/version/major", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			v, err := p.GetPointer(tt.pointer)
+			require.NoError(t, err, "couldn't resolve pointer")
+			assert.Equal(t, tt.want, v.Value(), "unexpected value")
+		})
+	}
+
+	t.Run("whole document", func(t *testing.T) {
+		v, err := p.GetPointer("")
+		require.NoError(t, err, "couldn't resolve empty pointer")
+		assert.Equal(t, p.Get(Root).Value(), v.Value(), "expected empty pointer to refer to the root")
+	})
+
+	t.Run("populate", func(t *testing.T) {
+		v, err := p.GetPointer("/peers/0")
+		require.NoError(t, err, "couldn't resolve pointer")
+		var peer struct{ Host string }
+		require.NoError(t, v.Populate(&peer), "couldn't populate")
+		assert.Equal(t, "alpha", peer.Host, "unexpected peer")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		for _, pointer := range []string{"/server/host", "/peers/2", "/peers/-", "/server/port/0"} {
+			_, err := p.GetPointer(pointer)
+			var nf *NotFoundError
+			require.True(t, errors.As(err, &nf), "expected a NotFoundError for %q, got %v", pointer, err)
+			assert.Equal(t, pointer, nf.Key, "expected error to name the pointer")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, pointer := range []string{"server/port", "/paths/a~2b", "/paths/a~", "/peers/01", "/peers/+1", "/peers/-1", "/peers/00/host"} {
+			_, err := p.GetPointer(pointer)
+			require.Error(t, err, "expected %q to be invalid", pointer)
+			assert.Contains(t, err.Error(), "invalid JSON Pointer", "unexpected error message")
+		}
+	})
+}
@@ -0,0 +1,1375 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	"go.uber.org/multierr"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A Normalizer cleans up configuration after it's populated: for example,
+// by lowercasing hostnames or trimming whitespace. If a target passed to
+// Value.Populate implements Normalizer, Populate calls Normalize once the
+// configuration has been successfully decoded into it, before the validation
+// tags are checked (see the package documentation). Normalize isn't called
+// if decoding fails or if there's no configuration at the key being
+// populated, and it's only called on the target itself, not on nested
+// fields; to normalize nested structs, call their Normalize methods from the
+// parent's.
+type Normalizer interface {
+	Normalize()
+}
+
+// A field describes how gopkg.in/yaml.v2 maps a YAML key onto a struct
+// field.
+type field struct {
+	key   string
+	index []int // as used by reflect.Value.FieldByIndex
+	typ   reflect.Type
+
+	// Keys in the configuration usually match the keys gopkg.in/yaml.v2
+	// uses, but the StructTag option can make them differ; see taggedFields.
+	yamlKey string
+	name    string
+	tag     reflect.StructTag
+
+	// Embedded fields are anonymous, untagged structs (or pointers to
+	// structs). gopkg.in/yaml.v2 addresses them by their lowercased type
+	// name, but Go promotes their fields; see reshape.
+	embedded bool
+	// gopkg.in/yaml.v2 can't set unexported embedded fields, and panics if
+	// it tries.
+	unexported bool
+}
+
+// structFields lists the YAML-addressable fields of a struct type, following
+// the same rules as gopkg.in/yaml.v2: unexported fields and fields tagged "-"
+// are skipped, keys default to the lowercased field name, and the fields of
+// ",inline" structs are promoted.
+func structFields(t reflect.Type) ([]field, error) {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("yaml")
+		if tag == "" && !strings.Contains(string(f.Tag), ":") {
+			tag = string(f.Tag)
+		}
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		inline := false
+		for _, flag := range parts[1:] {
+			if flag == "inline" {
+				inline = true
+			}
+		}
+		if inline {
+			switch f.Type.Kind() {
+			case reflect.Struct:
+				inner, err := structFields(f.Type)
+				if err != nil {
+					return nil, err
+				}
+				for _, in := range inner {
+					in.index = append([]int{i}, in.index...)
+					fields = append(fields, in)
+				}
+			case reflect.Map:
+				// Inlined maps collect otherwise-unknown keys. There's no
+				// single key that addresses them.
+			default:
+				// gopkg.in/yaml.v2 panics when it encounters these fields.
+				return nil, fmt.Errorf(
+					"can't inline field %s of type %v in %v: only structs and maps may be inlined",
+					f.Name, f.Type, t,
+				)
+			}
+			continue
+		}
+		key := parts[0]
+		if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+		fields = append(fields, field{
+			key:        key,
+			index:      []int{i},
+			typ:        f.Type,
+			yamlKey:    key,
+			name:       f.Name,
+			tag:        f.Tag,
+			embedded:   f.Anonymous && parts[0] == "" && isStruct(f.Type),
+			unexported: f.PkgPath != "",
+		})
+	}
+	return fields, nil
+}
+
+// taggedFields is like structFields, but keys fields by the supplied struct
+// tag rather than the yaml tag. Fields tagged "-" are skipped, and untagged
+// fields keep their default key, the lowercased field name. Each field's
+// yamlKey is still the key gopkg.in/yaml.v2 expects.
+func taggedFields(t reflect.Type, tag string) ([]field, error) {
+	fields, err := structFields(t)
+	if err != nil || tag == "" || tag == _defaultTag {
+		return fields, err
+	}
+	tagged := fields[:0]
+	for _, f := range fields {
+		name := strings.Split(f.tag.Get(tag), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			f.key = strings.ToLower(f.name)
+		default:
+			f.key = name
+		}
+		tagged = append(tagged, f)
+	}
+	return tagged, nil
+}
+
+const _defaultTag = "yaml"
+
+// isStruct reports whether a type is a struct or a pointer to one.
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// isOpaque reports whether a type handles its own unmarshaling. The contents
+// of such types are opaque to this package.
+func isOpaque(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return t.Implements(_unmarshalerType) || pt.Implements(_unmarshalerType) ||
+		t.Implements(_textUnmarshalerType) || pt.Implements(_textUnmarshalerType)
+}
+
+var (
+	_unmarshalerType     = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	_textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// reshape rewrites unmarshaled YAML into the shape gopkg.in/yaml.v2 expects
+// when populating a value of the given type. It never modifies its input:
+// any mappings or sequences that need changes are copied.
+//
+// Most importantly, reshape moves the promoted fields of untagged embedded
+// structs into a nested mapping. YAML keys behave like Go field access, so
+//   type Common struct { Host string }
+//   type Server struct {
+//     Common
+//     Port int
+//   }
+// populates from both {host: foo, port: 80} and {common: {host: foo}, port: 80}.
+func (y *YAML) reshape(node interface{}, t reflect.Type, path []string) (interface{}, error) {
+	t = derefType(t)
+	if isOpaque(t) {
+		return node, nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return node, nil
+		}
+		return y.reshapeStruct(m, t, path)
+	case reflect.Map:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return node, nil
+		}
+		var out map[interface{}]interface{}
+		for k, v := range m {
+			reshaped, err := y.reshape(v, t.Elem(), extend(path, fmt.Sprint(k)))
+			if err != nil {
+				return nil, err
+			}
+			out = set(out, m, k, reshaped)
+		}
+		if out == nil {
+			return m, nil
+		}
+		return out, nil
+	case reflect.Slice, reflect.Array:
+		seq, ok := node.([]interface{})
+		if !ok {
+			return node, nil
+		}
+		var out []interface{}
+		for i, v := range seq {
+			reshaped, err := y.reshape(v, t.Elem(), extend(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			if out == nil && !sameNode(reshaped, v) {
+				out = make([]interface{}, len(seq))
+				copy(out, seq)
+			}
+			if out != nil {
+				out[i] = reshaped
+			}
+		}
+		if out == nil {
+			return seq, nil
+		}
+		return out, nil
+	}
+	if y.coerce {
+		return coerce(node, t, path)
+	}
+	return node, nil
+}
+
+func (y *YAML) reshapeStruct(m map[interface{}]interface{}, t reflect.Type, path []string) (interface{}, error) {
+	fields, err := taggedFields(t, y.tag)
+	if err != nil {
+		return nil, err
+	}
+	var out map[interface{}]interface{}
+	own := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		own[f.key] = struct{}{}
+	}
+	for _, f := range fields {
+		if !f.embedded {
+			continue
+		}
+		keys, err := promotedKeys(derefType(f.typ), y.tag)
+		if err != nil {
+			return nil, err
+		}
+		var promoted map[interface{}]interface{}
+		if nested, ok := m[f.key].(map[interface{}]interface{}); ok {
+			promoted = nested
+		}
+		var moved map[interface{}]interface{}
+		for _, key := range keys {
+			if _, shadowed := own[key]; shadowed {
+				continue
+			}
+			v, ok := m[key]
+			if !ok {
+				continue
+			}
+			if _, dup := promoted[key]; dup {
+				continue
+			}
+			if moved == nil {
+				moved = make(map[interface{}]interface{}, len(promoted)+1)
+				for k, v := range promoted {
+					moved[k] = v
+				}
+			}
+			moved[key] = v
+			if out == nil {
+				out = copyMapping(m)
+			}
+			delete(out, key)
+		}
+		if moved != nil {
+			out[f.key] = moved
+		}
+	}
+	if out == nil {
+		out = m
+	}
+
+	var reshaped map[interface{}]interface{}
+	for _, f := range fields {
+		v, ok := out[f.key]
+		if !ok {
+			continue
+		}
+		if f.unexported {
+			return nil, fmt.Errorf(
+				"can't populate unexported embedded field %v in %v: export the type or tag the field with `yaml:\",inline\"`",
+				f.typ, t,
+			)
+		}
+		switch f.tag.Get(_formatTagName) {
+		case "json":
+			decoded, err := decodeJSONString(v, extend(path, f.key))
+			if err != nil {
+				return nil, err
+			}
+			v = decoded
+		case "tuple":
+			converted, err := tupleToMapping(v, f.typ, y.tag, extend(path, f.key))
+			if err != nil {
+				return nil, err
+			}
+			v = converted
+		}
+		r, err := y.reshape(v, f.typ, extend(path, f.key))
+		if err != nil {
+			return nil, err
+		}
+		reshaped = set(reshaped, out, f.key, r)
+	}
+	if reshaped == nil {
+		reshaped = out
+	}
+	if y.tag == "" || y.tag == _defaultTag {
+		return reshaped, nil
+	}
+	return y.renameKeys(reshaped, fields, t, path)
+}
+
+// renameKeys translates the keys of a mapping from those of the StructTag
+// option to those gopkg.in/yaml.v2 expects. Keys that would address a field
+// only under the yaml tag are unknown: they're rejected in strict mode and
+// dropped otherwise, so they can't populate fields by accident.
+func (y *YAML) renameKeys(m map[interface{}]interface{}, fields []field, t reflect.Type, path []string) (interface{}, error) {
+	yamlFields, err := structFields(t)
+	if err != nil {
+		return nil, err
+	}
+	renamed := make(map[string]string, len(fields))
+	for _, f := range fields {
+		renamed[f.key] = f.yamlKey
+	}
+	unknown := make(map[string]struct{}, len(yamlFields))
+	for _, f := range yamlFields {
+		if _, ok := renamed[f.yamlKey]; !ok {
+			unknown[f.yamlKey] = struct{}{}
+		}
+	}
+
+	out := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		key, ok := k.(string)
+		if !ok {
+			out[k] = v
+			continue
+		}
+		if yamlKey, ok := renamed[key]; ok {
+			out[yamlKey] = v
+			continue
+		}
+		if _, ok := unknown[key]; ok {
+			if y.strictAt(path) {
+				return nil, fmt.Errorf("key %q at %q doesn't match a %s tag in %v", key, strings.Join(path, _separator), y.tag, t)
+			}
+			continue
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// promotedKeys lists the keys that address a struct's fields, including the
+// fields promoted from any embedded structs.
+func promotedKeys(t reflect.Type, tag string) ([]string, error) {
+	return promotedKeysOf(t, tag, make(map[reflect.Type]struct{}))
+}
+
+func promotedKeysOf(t reflect.Type, tag string, seen map[reflect.Type]struct{}) ([]string, error) {
+	if _, ok := seen[t]; ok {
+		// Self-referential embedding, possible through pointers.
+		return nil, nil
+	}
+	seen[t] = struct{}{}
+	fields, err := taggedFields(t, tag)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, f.key)
+		if !f.embedded {
+			continue
+		}
+		inner, err := promotedKeysOf(derefType(f.typ), tag, seen)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, inner...)
+	}
+	return keys, nil
+}
+
+// set assigns a key in a copy-on-write mapping. If the value is unchanged
+// from the original, set avoids copying.
+func set(out, orig map[interface{}]interface{}, k, v interface{}) map[interface{}]interface{} {
+	if out == nil {
+		if sameNode(orig[k], v) {
+			return nil
+		}
+		out = copyMapping(orig)
+	}
+	out[k] = v
+	return out
+}
+
+func copyMapping(m map[interface{}]interface{}) map[interface{}]interface{} {
+	out := make(map[interface{}]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// sameNode reports whether two nodes are identical (not merely equal):
+// mappings and sequences must share the same underlying storage.
+func sameNode(a, b interface{}) bool {
+	switch av := a.(type) {
+	case map[interface{}]interface{}:
+		bv, ok := b.(map[interface{}]interface{})
+		return ok && reflect.ValueOf(av).Pointer() == reflect.ValueOf(bv).Pointer()
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		return ok && len(av) == len(bv) && (len(av) == 0 || &av[0] == &bv[0])
+	}
+	if _, ok := b.(map[interface{}]interface{}); ok {
+		return false
+	}
+	if _, ok := b.([]interface{}); ok {
+		return false
+	}
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && a == b
+}
+
+// extend returns a copy of a path with an additional segment.
+func extend(path []string, segment string) []string {
+	extended := make([]string, len(path), len(path)+1)
+	copy(extended, path)
+	return append(extended, segment)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// visit walks a populated Go value alongside the YAML node it was populated
+// from, calling f for every pair. It descends through pointers, struct
+// fields, and sequence elements. Map values aren't addressable, so visit
+// doesn't descend into them.
+func visit(node interface{}, v reflect.Value, f func(interface{}, reflect.Value) error) error {
+	if err := f(node, v); err != nil {
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return visit(node, v.Elem(), f)
+	case reflect.Struct:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			child, ok := m[field.key]
+			if !ok {
+				continue
+			}
+			if err := visit(child, v.FieldByIndex(field.index), f); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		seq, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i := 0; i < len(seq) && i < v.Len(); i++ {
+			if err := visit(seq[i], v.Index(i), f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rejectNulls implements RejectNullStructs, checking unmarshaled YAML
+// against the type it will populate.
+func rejectNulls(node interface{}, t reflect.Type, path []string) error {
+	if isOpaque(t) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if node == nil {
+			return nil
+		}
+		return rejectNulls(node, t.Elem(), path)
+	case reflect.Struct, reflect.Map:
+		if node == nil {
+			return fmt.Errorf("can't populate %v from null at key %q", t, strings.Join(path, _separator))
+		}
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		if t.Kind() == reflect.Map {
+			for k, v := range m {
+				if err := rejectNulls(v, t.Elem(), extend(path, fmt.Sprint(k))); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		fields, err := structFields(t)
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			child, ok := m[field.key]
+			if !ok {
+				continue
+			}
+			childPath := path
+			if !field.embedded {
+				// reshape nests promoted fields under their embedded struct,
+				// but the configuration doesn't.
+				childPath = extend(path, field.key)
+			}
+			if err := rejectNulls(child, field.typ, childPath); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		seq, _ := node.([]interface{})
+		for i, elem := range seq {
+			if err := rejectNulls(elem, t.Elem(), extend(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var _jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// rejectNonStrings implements StrictScalars, checking unmarshaled YAML
+// against the type it will populate.
+func rejectNonStrings(node interface{}, t reflect.Type, path []string) error {
+	if isOpaque(t) || t == _jsonNumberType {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return rejectNonStrings(node, t.Elem(), path)
+	case reflect.String:
+		switch node.(type) {
+		case bool:
+			return fmt.Errorf("couldn't populate key %q: expected a string, got boolean %v; "+
+				"unquoted words like yes, no, on, and off are Booleans, so quote the value to keep a string",
+				strings.Join(path, _separator), node)
+		case int, int64, uint64, float64:
+			return fmt.Errorf("couldn't populate key %q: expected a string, got number %v; "+
+				"quote the value to keep it exactly as written",
+				strings.Join(path, _separator), node)
+		}
+	case reflect.Struct, reflect.Map:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		if t.Kind() == reflect.Map {
+			for k, v := range m {
+				if err := rejectNonStrings(v, t.Elem(), extend(path, fmt.Sprint(k))); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		fields, err := structFields(t)
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			child, ok := m[field.key]
+			if !ok {
+				continue
+			}
+			childPath := path
+			if !field.embedded {
+				childPath = extend(path, field.key)
+			}
+			if err := rejectNonStrings(child, field.typ, childPath); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		seq, _ := node.([]interface{})
+		for i, elem := range seq {
+			if err := rejectNonStrings(elem, t.Elem(), extend(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// transformStrings returns a copy of an unmarshaled YAML node with f applied
+// to every string scalar. Keys are left alone.
+func transformStrings(node interface{}, f func(string) string) interface{} {
+	switch n := node.(type) {
+	case string:
+		return f(n)
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(n))
+		for k, v := range n {
+			m[k] = transformStrings(v, f)
+		}
+		return m
+	case []interface{}:
+		seq := make([]interface{}, len(n))
+		for i, v := range n {
+			seq[i] = transformStrings(v, f)
+		}
+		return seq
+	}
+	return node
+}
+
+// dropConfiguredKeys deletes the keys of a non-empty map that the
+// configuration is about to set. gopkg.in/yaml.v2 replaces map values
+// wholesale anyway, but in strict mode it refuses to overwrite them.
+func dropConfiguredKeys(node interface{}, v reflect.Value) error {
+	m, ok := node.(map[interface{}]interface{})
+	if !ok || v.Kind() != reflect.Map || v.Len() == 0 {
+		return nil
+	}
+	configured := make(map[string]struct{}, len(m))
+	for k := range m {
+		configured[fmt.Sprint(k)] = struct{}{}
+	}
+	for _, k := range v.MapKeys() {
+		if _, ok := configured[fmt.Sprint(k.Interface())]; ok {
+			v.SetMapIndex(k, reflect.Value{})
+		}
+	}
+	return nil
+}
+
+// markNulls distinguishes explicit nulls from absent keys for
+// pointer-to-pointer targets: gopkg.in/yaml.v2 leaves both nil, but an
+// explicit null should produce a non-nil pointer to a nil pointer.
+func markNulls(node interface{}, v reflect.Value) error {
+	if node != nil || v.Kind() != reflect.Ptr || !v.IsNil() || !v.CanSet() {
+		return nil
+	}
+	if v.Type().Elem().Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return nil
+}
+
+// strictAt reports whether populating from path should reject unknown keys.
+// Without StrictPaths, that's the provider's strictness. With StrictPaths,
+// only populating within one of the strict subtrees is strict; subtrees
+// nested further inside the populated value are checked by checkStrictPaths.
+func (y *YAML) strictAt(path []string) bool {
+	if len(y.strictPaths) == 0 {
+		return y.strict
+	}
+	for _, sp := range y.strictPaths {
+		if hasPrefix(path, sp) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStrictPaths rejects unknown keys in the strict subtrees nested within
+// a value of type t at path. Each subtree is decoded strictly into a
+// throwaway value of the corresponding field's type.
+func (y *YAML) checkStrictPaths(path []string, t reflect.Type) error {
+	for _, sp := range y.strictPaths {
+		if len(sp) <= len(path) || !hasPrefix(sp, path) {
+			continue
+		}
+		sub, ok := fieldPathType(t, sp[len(path):], y.tag)
+		if !ok {
+			// The target has nowhere to put this subtree.
+			continue
+		}
+		val, ok := y.at(sp)
+		if !ok {
+			continue
+		}
+		val, err := y.reshape(val, sub, sp)
+		if err != nil {
+			return err
+		}
+		buf := &bytes.Buffer{}
+		if err := yaml.NewEncoder(buf).Encode(val); err != nil {
+			err := fmt.Errorf("couldn't marshal config at key %s to YAML: %v", strings.Join(sp, _separator), err)
+			return unreachable.Wrap(err)
+		}
+		dec := yaml.NewDecoder(buf)
+		dec.SetStrict(true)
+		if err := dec.Decode(reflect.New(sub).Interface()); err != nil {
+			return fmt.Errorf("invalid config at strict key %q: %v", strings.Join(sp, _separator), err)
+		}
+	}
+	return nil
+}
+
+// fieldPathType finds the type of the value that a relative path addresses
+// within a value of type t. It reports false if the path leads somewhere t
+// can't represent, or into a type with no fixed structure.
+func fieldPathType(t reflect.Type, path []string, tag string) (reflect.Type, bool) {
+	for _, segment := range path {
+		t = derefType(t)
+		if isOpaque(t) {
+			return nil, false
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := findField(t, segment, tag, make(map[reflect.Type]struct{}))
+			if !ok {
+				return nil, false
+			}
+			t = f.typ
+		case reflect.Map, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return nil, false
+		}
+	}
+	return t, true
+}
+
+// findField finds the field of a struct addressed by a key, including fields
+// promoted from embedded structs. The struct's own fields take priority.
+func findField(t reflect.Type, key, tag string, seen map[reflect.Type]struct{}) (field, bool) {
+	if _, ok := seen[t]; ok {
+		return field{}, false
+	}
+	seen[t] = struct{}{}
+	fields, err := taggedFields(t, tag)
+	if err != nil {
+		return field{}, false
+	}
+	for _, f := range fields {
+		if f.key == key && !f.unexported {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if !f.embedded || f.unexported {
+			continue
+		}
+		if inner, ok := findField(derefType(f.typ), key, tag, seen); ok {
+			return inner, true
+		}
+	}
+	return field{}, false
+}
+
+// fieldByKey returns the field of a struct value addressed by a key, using
+// the same rules as findField. Nil pointers to embedded structs are allocated
+// if alloc is set; otherwise fieldByKey reports false when it meets one.
+func fieldByKey(v reflect.Value, key, tag string, alloc bool) (reflect.Value, bool) {
+	fields, err := taggedFields(v.Type(), tag)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	for _, f := range fields {
+		if f.key == key && !f.unexported {
+			return v.FieldByIndex(f.index), true
+		}
+	}
+	for _, f := range fields {
+		if !f.embedded || f.unexported {
+			continue
+		}
+		if _, ok := findField(derefType(f.typ), key, tag, make(map[reflect.Type]struct{})); !ok {
+			continue
+		}
+		inner, ok := derefValue(v.FieldByIndex(f.index), alloc)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		return fieldByKey(inner, key, tag, alloc)
+	}
+	return reflect.Value{}, false
+}
+
+// derefValue follows pointers, allocating nil ones if alloc is set. It
+// reports false if it meets a nil pointer it may not allocate.
+func derefValue(v reflect.Value, alloc bool) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if !alloc {
+				return reflect.Value{}, false
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+// fieldAt returns the struct field that a relative path of keys addresses
+// within v, which must be a struct. See fieldByKey for the meaning of alloc.
+func fieldAt(v reflect.Value, path []string, tag string, alloc bool) (reflect.Value, bool) {
+	for _, segment := range path {
+		var ok bool
+		if v, ok = derefValue(v, alloc); !ok {
+			return reflect.Value{}, false
+		}
+		if v, ok = fieldByKey(v, segment, tag, alloc); !ok {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// structFieldType finds the type of the struct field that a relative path of
+// keys addresses within struct type t. Unlike fieldPathType, every segment
+// must name a struct field.
+func structFieldType(t reflect.Type, path []string, tag string) (reflect.Type, error) {
+	for i, segment := range path {
+		t = derefType(t)
+		if t.Kind() != reflect.Struct || isOpaque(t) {
+			return nil, fmt.Errorf("%v at %q isn't a struct", t, strings.Join(path[:i], _separator))
+		}
+		f, ok := findField(t, segment, tag, make(map[reflect.Type]struct{}))
+		if !ok {
+			return nil, fmt.Errorf("%v has no field for key %q", t, segment)
+		}
+		t = f.typ
+	}
+	return t, nil
+}
+
+func hasPrefix(path, prefix []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if path[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// plan collects the paths that populating target from path would read.
+func (y *YAML) plan(path []string, target interface{}) ([][]string, error) {
+	t := reflect.TypeOf(target)
+	if t == nil {
+		return nil, errors.New("can't plan populating a nil target")
+	}
+	p := &planner{y: y, seen: make(map[string]struct{})}
+	if err := p.walk(path, t); err != nil {
+		return nil, err
+	}
+	return p.paths, nil
+}
+
+type planner struct {
+	y     *YAML
+	paths [][]string
+	seen  map[string]struct{}
+}
+
+func (p *planner) add(path []string) {
+	key := strings.Join(path, _separator)
+	if _, ok := p.seen[key]; ok {
+		return
+	}
+	p.seen[key] = struct{}{}
+	p.paths = append(p.paths, path)
+}
+
+// walk plans a value of type t at path.
+func (p *planner) walk(path []string, t reflect.Type) error {
+	t = derefType(t)
+	val, ok := p.y.at(path)
+	if !ok {
+		return nil
+	}
+	if _, isMapping := val.(map[interface{}]interface{}); !isMapping || t.Kind() != reflect.Struct || isOpaque(t) {
+		p.add(path)
+		return nil
+	}
+	return p.walkFields(path, t, []reflect.Type{t}, nil)
+}
+
+// walkFields plans the fields of a struct whose mapping is at path. The
+// fields of untagged embedded structs may be nested under their own key or
+// promoted into the parent's mapping, where the parent's own fields shadow
+// them. Promoting tracks the structs sharing this path to stop recursive
+// embedding.
+func (p *planner) walkFields(path []string, t reflect.Type, promoting []reflect.Type, shadowed map[string]struct{}) error {
+	fields, err := taggedFields(t, p.y.tag)
+	if err != nil {
+		return err
+	}
+	own := make(map[string]struct{}, len(shadowed)+len(fields))
+	for k := range shadowed {
+		own[k] = struct{}{}
+	}
+	for _, f := range fields {
+		own[f.key] = struct{}{}
+	}
+	for _, f := range fields {
+		if f.unexported {
+			continue
+		}
+		if _, ok := shadowed[f.key]; ok {
+			continue
+		}
+		if err := p.walk(extend(path, f.key), f.typ); err != nil {
+			return err
+		}
+		if !f.embedded {
+			continue
+		}
+		inner := derefType(f.typ)
+		if containsType(promoting, inner) {
+			continue
+		}
+		if err := p.walkFields(path, inner, append(promoting, inner), own); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsType(ts []reflect.Type, t reflect.Type) bool {
+	for _, candidate := range ts {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+// locateErrors explains a failed decode of node into a value of type t by
+// decoding the node's children one at a time, so that each error names the
+// most deeply nested key (or sequence index) that can't be decoded. If no
+// more precise location can be found, it returns the original error.
+func locateErrors(node interface{}, t reflect.Type, path []string, strict bool, err error) error {
+	located := locate(node, t, path, strict)
+	if len(located) == 0 {
+		return err
+	}
+	return multierr.Combine(located...)
+}
+
+func locate(node interface{}, t reflect.Type, path []string, strict bool) []error {
+	t = derefType(t)
+	err := decodeAs(node, t, strict)
+	if err == nil {
+		return nil
+	}
+	var located []error
+	if !isOpaque(t) {
+		located = locateChildren(node, t, path, strict)
+	}
+	if len(located) > 0 {
+		return located
+	}
+	if len(path) == 0 {
+		return []error{err}
+	}
+	return []error{fmt.Errorf("couldn't populate key %q: %s", strings.Join(path, _separator), mismatchMessage(node, t, err))}
+}
+
+// mismatchMessage explains an error decoding node into a value of type t. If
+// the error is a type mismatch, the explanation leads with the expected Go
+// type and the actual YAML kind, as in "expected int, got string".
+func mismatchMessage(node interface{}, t reflect.Type, err error) string {
+	msg := unmarshalMessage(err)
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return msg
+	}
+	for _, e := range te.Errors {
+		if !strings.Contains(e, "cannot unmarshal") {
+			return msg
+		}
+	}
+	return fmt.Sprintf("expected %v, got %s: %s", t, yamlKind(node), msg)
+}
+
+// yamlKind describes the kind of an unmarshaled YAML node.
+func yamlKind(node interface{}) string {
+	switch node.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case []interface{}:
+		return "sequence"
+	case map[interface{}]interface{}:
+		return "mapping"
+	}
+	return fmt.Sprintf("%T", node)
+}
+
+func locateChildren(node interface{}, t reflect.Type, path []string, strict bool) []error {
+	var located []error
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		fields, err := structFields(t)
+		if err != nil {
+			return nil
+		}
+		for _, f := range fields {
+			if f.unexported {
+				continue
+			}
+			if v, ok := m[f.key]; ok {
+				located = append(located, locate(v, f.typ, extend(path, f.key), strict)...)
+			}
+		}
+	case reflect.Map:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		keys := make([]string, 0, len(m))
+		byKey := make(map[string]interface{}, len(m))
+		for k := range m {
+			key := fmt.Sprint(k)
+			keys = append(keys, key)
+			byKey[key] = k
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := extend(path, k)
+			if err := decodeAs(byKey[k], t.Key(), strict); err != nil {
+				located = append(located, fmt.Errorf(
+					"couldn't populate key %q: invalid mapping key: %s",
+					strings.Join(child, _separator),
+					unmarshalMessage(err),
+				))
+				continue
+			}
+			located = append(located, locate(m[byKey[k]], t.Elem(), child, strict)...)
+		}
+	case reflect.Slice, reflect.Array:
+		seq, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, v := range seq {
+			located = append(located, locate(v, t.Elem(), extend(path, strconv.Itoa(i)), strict)...)
+		}
+	}
+	return located
+}
+
+// decodeAs reports whether node can be decoded into a fresh value of type t.
+func decodeAs(node interface{}, t reflect.Type, strict bool) error {
+	buf := &bytes.Buffer{}
+	if err := yaml.NewEncoder(buf).Encode(node); err != nil {
+		return unreachable.Wrap(fmt.Errorf("couldn't marshal config to YAML: %v", err))
+	}
+	dec := yaml.NewDecoder(buf)
+	dec.SetStrict(strict)
+	return dec.Decode(reflect.New(t).Interface())
+}
+
+// unmarshalMessage strips gopkg.in/yaml.v2's line numbers from an error.
+// Configuration is re-serialized before it's decoded, so the line numbers
+// don't refer to any source.
+func unmarshalMessage(err error) string {
+	var te *yaml.TypeError
+	if !errors.As(err, &te) {
+		return err.Error()
+	}
+	msgs := make([]string, len(te.Errors))
+	for i, msg := range te.Errors {
+		if strings.HasPrefix(msg, "line ") {
+			if colon := strings.Index(msg, ": "); colon != -1 {
+				msg = msg[colon+2:]
+			}
+		}
+		msgs[i] = msg
+	}
+	return strings.Join(msgs, "; ")
+}
+
+const (
+	_defaultTagName = "default"
+	_envTagName     = "env"
+)
+
+// applyDefaults implements UseDefaultTags, walking a populated Go value
+// alongside the YAML node it was populated from. Unlike visit, it also
+// descends into fields whose keys are absent, since their nested fields may
+// have defaults too. If applied isn't nil, applyDefaults appends the dotted
+// path of each field it sets; path is the key v was populated from.
+func applyDefaults(node interface{}, v reflect.Value, path []string, applied *[]string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return applyDefaults(node, v.Elem(), path, applied)
+	case reflect.Struct:
+		if !v.CanSet() {
+			return nil
+		}
+		m, _ := node.(map[interface{}]interface{})
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if field.unexported {
+				continue
+			}
+			fv := v.FieldByIndex(field.index)
+			child, present := m[field.key]
+			childPath := path
+			if !field.embedded {
+				// reshape nests promoted fields under their embedded struct,
+				// but the configuration doesn't.
+				childPath = extend(path, field.key)
+			}
+			if def, ok := field.tag.Lookup(_defaultTagName); ok && !present && fv.IsZero() {
+				if err := yaml.Unmarshal([]byte(def), fv.Addr().Interface()); err != nil {
+					return fmt.Errorf("invalid default %q for field %s of %v: %v", def, field.name, v.Type(), err)
+				}
+				if applied != nil {
+					*applied = append(*applied, strings.Join(childPath, _separator))
+				}
+			}
+			if err := applyDefaults(child, fv, childPath, applied); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		seq, _ := node.([]interface{})
+		for i := 0; i < v.Len(); i++ {
+			var elem interface{}
+			if i < len(seq) {
+				elem = seq[i]
+			}
+			if err := applyDefaults(elem, v.Index(i), extend(path, strconv.Itoa(i)), applied); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyEnvTags implements UseEnvTags, overriding the fields of a populated Go
+// value with the environment variables named by their env tags.
+func applyEnvTags(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return applyEnvTags(v.Elem())
+	case reflect.Struct:
+		if !v.CanSet() {
+			return nil
+		}
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if field.unexported {
+				continue
+			}
+			fv := v.FieldByIndex(field.index)
+			if name, ok := field.tag.Lookup(_envTagName); ok && name != "" {
+				if val, ok := os.LookupEnv(name); ok {
+					if err := setFromEnv(fv, val); err != nil {
+						return fmt.Errorf("invalid value %q in environment variable %s for field %s of %v: %v", val, name, field.name, v.Type(), err)
+					}
+				}
+			}
+			if err := applyEnvTags(fv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setFromEnv replaces a field with an environment variable's value.
+func setFromEnv(fv reflect.Value, val string) error {
+	t := fv.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	bs := []byte(val)
+	if t.Kind() == reflect.String && !isOpaque(t) {
+		// Quote strings, so values like 007 and yes aren't re-typed.
+		var err error
+		if bs, err = yaml.Marshal(val); err != nil {
+			return unreachable.Wrap(err)
+		}
+	}
+	replacement := reflect.New(fv.Type())
+	if err := yaml.Unmarshal(bs, replacement.Interface()); err != nil {
+		return err
+	}
+	fv.Set(replacement.Elem())
+	return nil
+}
+
+// populateEach implements PopulateAll, populating each field of a struct
+// from its own key (see populateField) and collecting every field's errors.
+// Keys that don't
+// belong to a field, including the promoted keys of embedded structs, are
+// decoded together into a scratch copy of the struct, from which embedded
+// structs and inlined maps are copied. It returns nil if the
+// target isn't a pointer to a struct populated from a mapping.
+func (y *YAML) populateEach(path []string, i interface{}) []error {
+	target := reflect.ValueOf(i)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return nil
+	}
+	v := target.Elem()
+	if v.Kind() != reflect.Struct || isOpaque(v.Type()) {
+		return nil
+	}
+	val, _ := y.at(path)
+	m, ok := val.(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	fields, err := taggedFields(v.Type(), y.tag)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	claimed := make(map[interface{}]bool)
+	var fromRest []int
+	for _, field := range fields {
+		if field.unexported {
+			continue
+		}
+		if field.embedded {
+			fromRest = append(fromRest, field.index[0])
+			continue
+		}
+		claimed[field.key] = true
+		errs = append(errs, multierr.Errors(y.populateField(m, v, field, path))...)
+	}
+
+	rest := make(map[interface{}]interface{})
+	for k, child := range m {
+		if !claimed[k] {
+			rest[k] = child
+		}
+	}
+	if len(rest) == 0 {
+		return errs
+	}
+	scratch := reflect.New(v.Type())
+	errs = append(errs, multierr.Errors(y.decode(rest, path, scratch.Interface(), nil))...)
+	for idx := 0; idx < v.NumField(); idx++ {
+		f := v.Type().Field(idx)
+		if f.PkgPath == "" && f.Type.Kind() == reflect.Map && strings.Contains(f.Tag.Get("yaml"), ",inline") {
+			fromRest = append(fromRest, idx)
+		}
+	}
+	for _, idx := range fromRest {
+		v.Field(idx).Set(scratch.Elem().Field(idx))
+	}
+	return errs
+}
+
+// populateField populates one field of the struct v from the struct's
+// mapping m, which is at path. The field's key is reshaped as part of the
+// struct, so the field's format tag applies, and its default and env tags
+// are honored as Populate would.
+func (y *YAML) populateField(m map[interface{}]interface{}, v reflect.Value, f field, path []string) error {
+	fv := v.FieldByIndex(f.index)
+	fieldPath := extend(path, f.key)
+	child, present := m[f.key]
+	if present {
+		reshaped, err := y.reshapeStruct(map[interface{}]interface{}{f.key: child}, v.Type(), path)
+		if err != nil {
+			return err
+		}
+		child = reshaped.(map[interface{}]interface{})[f.yamlKey]
+		if err := y.decode(child, fieldPath, fv.Addr().Interface(), nil); err != nil {
+			return err
+		}
+	}
+	if y.defaultTags && !present {
+		if def, ok := f.tag.Lookup(_defaultTagName); ok && fv.IsZero() {
+			if err := yaml.Unmarshal([]byte(def), fv.Addr().Interface()); err != nil {
+				return fmt.Errorf("invalid default %q for field %s of %v: %v", def, f.name, v.Type(), err)
+			}
+		}
+		if err := applyDefaults(nil, fv, fieldPath, nil); err != nil {
+			return err
+		}
+	}
+	if y.envTags {
+		if name, ok := f.tag.Lookup(_envTagName); ok && name != "" {
+			if val, ok := os.LookupEnv(name); ok {
+				if err := setFromEnv(fv, val); err != nil {
+					return fmt.Errorf("invalid value %q in environment variable %s for field %s of %v: %v", val, name, f.name, v.Type(), err)
+				}
+			}
+		}
+		if err := applyEnvTags(fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
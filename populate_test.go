@@ -0,0 +1,1512 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestPopulatePointerToPointer(t *testing.T) {
+	type optional struct {
+		Timeout **int
+	}
+
+	t.Run("absent", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("{}")))
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg optional
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Nil(t, cfg.Timeout, "absent key should leave field nil")
+	})
+
+	t.Run("null", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("timeout: ~")))
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg optional
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		require.NotNil(t, cfg.Timeout, "explicit null should set outer pointer")
+		assert.Nil(t, *cfg.Timeout, "explicit null should leave inner pointer nil")
+	})
+
+	t.Run("present", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("timeout: 5")))
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg optional
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		require.NotNil(t, cfg.Timeout, "value should set outer pointer")
+		require.NotNil(t, *cfg.Timeout, "value should set inner pointer")
+		assert.Equal(t, 5, **cfg.Timeout, "unexpected value")
+	})
+
+	t.Run("nested in sequence", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("- timeout: ~\n- {}")))
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg []optional
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate slice")
+		require.Len(t, cfg, 2, "unexpected length")
+		assert.NotNil(t, cfg[0].Timeout, "explicit null should set outer pointer")
+		assert.Nil(t, cfg[1].Timeout, "absent key should leave field nil")
+	})
+
+	t.Run("top level", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("timeout: ~")))
+		require.NoError(t, err, "couldn't construct provider")
+
+		var absent **int
+		require.NoError(t, p.Get("not_there").Populate(&absent), "couldn't populate absent key")
+		assert.Nil(t, absent, "absent key should leave pointer nil")
+
+		var null **int
+		require.NoError(t, p.Get("timeout").Populate(&null), "couldn't populate null key")
+		require.NotNil(t, null, "explicit null should set outer pointer")
+		assert.Nil(t, *null, "explicit null should leave inner pointer nil")
+	})
+}
+
+func TestPopulateSequenceOfMappings(t *testing.T) {
+	type route struct {
+		Path    string
+		Backend string
+		Retries int
+	}
+	p, err := NewYAML(Source(strings.NewReader(`
+routes:
+  - {path: /users, backend: users}
+  - {path: /orders, backend: orders, retries: 3}
+`)))
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("index into sequence", func(t *testing.T) {
+		var r route
+		require.NoError(t, p.Get("routes.1").Populate(&r), "couldn't populate single route")
+		assert.Equal(t, route{Path: "/orders", Backend: "orders", Retries: 3}, r, "unexpected route")
+		assert.Equal(t, "users", p.Get("routes.0.backend").Value(), "unexpected nested value")
+		assert.Equal(t, "users", p.Get("routes").Get("0.backend").Value(), "unexpected value from Value.Get")
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		for _, key := range []string{"routes.2", "routes.-1", "routes.first", "routes.0.path.0"} {
+			assert.False(t, p.Get(key).HasValue(), "expected no value at %q", key)
+		}
+	})
+
+	t.Run("non-empty target is replaced", func(t *testing.T) {
+		routes := []route{
+			{Path: "/default", Backend: "default", Retries: 1},
+			{Path: "/default", Backend: "default", Retries: 1},
+			{Path: "/extra", Backend: "extra", Retries: 1},
+		}
+		require.NoError(t, p.Get("routes").Populate(&routes), "couldn't populate routes")
+		assert.Equal(t, []route{
+			{Path: "/users", Backend: "users"},
+			{Path: "/orders", Backend: "orders", Retries: 3},
+		}, routes, "expected configured sequence to replace existing elements")
+	})
+}
+
+type CommonConfig struct {
+	Host    string
+	Timeout int
+}
+
+type TLSConfig struct {
+	CommonConfig
+	Cert string
+}
+
+type Recursive struct {
+	*Recursive
+	Name string
+}
+
+func TestPopulateEmbeddedStructs(t *testing.T) {
+	provider := func(t testing.TB, src string) *YAML {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("value", func(t *testing.T) {
+		var cfg struct {
+			CommonConfig
+			Port int
+		}
+		p := provider(t, "host: localhost\ntimeout: 5\nport: 80")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, "localhost", cfg.Host, "unexpected promoted field")
+		assert.Equal(t, 5, cfg.Timeout, "unexpected promoted field")
+		assert.Equal(t, 80, cfg.Port, "unexpected field")
+		assert.Equal(t, map[interface{}]interface{}{
+			"host":    "localhost",
+			"timeout": 5,
+			"port":    80,
+		}, p.Get(Root).Value(), "populating shouldn't modify provider contents")
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		var cfg struct {
+			*CommonConfig
+			Port int
+		}
+		p := provider(t, "host: localhost\nport: 80")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		require.NotNil(t, cfg.CommonConfig, "expected embedded pointer to be allocated")
+		assert.Equal(t, "localhost", cfg.Host, "unexpected promoted field")
+	})
+
+	t.Run("nested key", func(t *testing.T) {
+		var cfg struct {
+			CommonConfig
+			Port int
+		}
+		p := provider(t, "commonconfig: {host: localhost}\ntimeout: 5")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, "localhost", cfg.Host, "unexpected field from nested key")
+		assert.Equal(t, 5, cfg.Timeout, "unexpected promoted field")
+	})
+
+	t.Run("multiple levels", func(t *testing.T) {
+		var cfg struct {
+			TLSConfig
+			Port int
+		}
+		p := provider(t, "host: localhost\ncert: /etc/cert.pem\nport: 443")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, "localhost", cfg.Host, "unexpected doubly-promoted field")
+		assert.Equal(t, "/etc/cert.pem", cfg.Cert, "unexpected promoted field")
+	})
+
+	t.Run("shadowed", func(t *testing.T) {
+		var cfg struct {
+			CommonConfig
+			Host string
+		}
+		p := provider(t, "host: outer\ntimeout: 5")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, "outer", cfg.Host, "outer field should shadow promoted field")
+		assert.Equal(t, "", cfg.CommonConfig.Host, "shadowed field shouldn't be set")
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		var cfg struct {
+			Common CommonConfig `yaml:",inline"`
+			Port   int
+		}
+		p := provider(t, "host: localhost\nport: 80")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, "localhost", cfg.Common.Host, "unexpected inlined field")
+	})
+
+	t.Run("inline pointer", func(t *testing.T) {
+		var cfg struct {
+			Common *CommonConfig `yaml:",inline"`
+		}
+		p := provider(t, "host: localhost")
+		err := p.Get(Root).Populate(&cfg)
+		require.Error(t, err, "expected inlining a pointer to fail")
+		assert.Contains(t, err.Error(), "only structs and maps may be inlined", "unexpected error message")
+	})
+
+	t.Run("self-referential", func(t *testing.T) {
+		var cfg Recursive
+		p := provider(t, "name: outer\nrecursive: {name: inner}")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		require.NotNil(t, cfg.Recursive, "expected embedded pointer to be allocated")
+		assert.Equal(t, "outer", cfg.Name, "unexpected outer field")
+		assert.Equal(t, "inner", cfg.Recursive.Name, "unexpected inner field")
+	})
+
+	t.Run("unexported", func(t *testing.T) {
+		type common struct{ Host string }
+		var cfg struct {
+			common
+			Port int
+		}
+		p := provider(t, "host: localhost")
+		err := p.Get(Root).Populate(&cfg)
+		require.Error(t, err, "expected unexported embedded field to fail")
+		assert.Contains(t, err.Error(), "unexported embedded field", "unexpected error message")
+
+		p = provider(t, "port: 80")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "unused unexported embedded fields should be ignored")
+		assert.Equal(t, 80, cfg.Port, "unexpected field")
+
+		var inlined struct {
+			common `yaml:",inline"`
+		}
+		p = provider(t, "host: localhost")
+		require.NoError(t, p.Get(Root).Populate(&inlined), "couldn't populate inlined unexported struct")
+		assert.Equal(t, "localhost", inlined.Host, "unexpected inlined field")
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		var cfg struct {
+			CommonConfig
+		}
+		p := provider(t, "host: localhost\nunknown: true")
+		err := p.Get(Root).Populate(&cfg)
+		require.Error(t, err, "expected unknown key to fail in strict mode")
+		assert.Contains(t, err.Error(), "unknown", "unexpected error message")
+	})
+
+	t.Run("in sequence", func(t *testing.T) {
+		var cfg []struct {
+			CommonConfig
+		}
+		p := provider(t, "- host: a\n- host: b")
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate slice")
+		require.Len(t, cfg, 2, "unexpected length")
+		assert.Equal(t, "b", cfg[1].Host, "unexpected promoted field in sequence element")
+	})
+}
+
+func TestPopulatePlan(t *testing.T) {
+	type server struct {
+		CommonConfig
+		Port    int
+		Addr    string `yaml:"address"`
+		TLS     *TLSConfig
+		Tags    []string
+		Ignored string `yaml:"-"`
+		Limits  map[string]int
+		Timeout **int
+	}
+
+	const src = `
+server:
+  host: example.com
+  port: 80
+  tls:
+    cert: server.pem
+  tags: [a, b]
+  limits: {rps: 10}
+  timeout: null
+  ignored: true
+  unused: 42
+`
+	p, err := NewYAML(Source(strings.NewReader(src)), Permissive())
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("struct", func(t *testing.T) {
+		var cfg server
+		paths, err := p.Get("server").PopulatePlan(&cfg)
+		require.NoError(t, err, "couldn't plan populate")
+		assert.Equal(t, []string{
+			"server.host",
+			"server.port",
+			"server.tls.cert",
+			"server.tags",
+			"server.limits",
+			"server.timeout",
+		}, paths, "unexpected paths")
+		assert.Equal(t, server{}, cfg, "planning shouldn't modify the target")
+	})
+
+	t.Run("nested embedded key", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("commonconfig: {host: example.com}\nport: 80")))
+		require.NoError(t, err, "couldn't construct provider")
+		paths, err := p.Get(Root).PopulatePlan(&server{})
+		require.NoError(t, err, "couldn't plan populate")
+		assert.Equal(t, []string{"commonconfig.host", "port"}, paths, "unexpected paths")
+	})
+
+	t.Run("recursive embedding", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("name: outer\nrecursive: {name: inner}")))
+		require.NoError(t, err, "couldn't construct provider")
+		paths, err := p.Get(Root).PopulatePlan(&Recursive{})
+		require.NoError(t, err, "couldn't plan populate")
+		assert.Equal(t, []string{"recursive.name", "name"}, paths, "unexpected paths")
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		var port int
+		paths, err := p.Get("server.port").PopulatePlan(&port)
+		require.NoError(t, err, "couldn't plan populate")
+		assert.Equal(t, []string{"server.port"}, paths, "unexpected paths")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		paths, err := p.Get("not_there").PopulatePlan(&server{})
+		require.NoError(t, err, "couldn't plan populate")
+		assert.Empty(t, paths, "expected no paths")
+	})
+
+	t.Run("nil target", func(t *testing.T) {
+		_, err := p.Get("server").PopulatePlan(nil)
+		require.Error(t, err, "expected planning to fail")
+	})
+}
+
+func TestPopulateFields(t *testing.T) {
+	type server struct {
+		*CommonConfig
+		Port   int
+		Addr   string `yaml:"address"`
+		TLS    *TLSConfig
+		Limits map[string]int
+	}
+	live := func() server {
+		return server{
+			CommonConfig: &CommonConfig{Host: "old.example.com", Timeout: 5},
+			Port:         80,
+			Addr:         "10.0.0.1",
+			Limits:       map[string]int{"rps": 10},
+		}
+	}
+	p, err := NewYAML(Source(strings.NewReader(`
+server:
+  host: new.example.com
+  timeout: 30
+  port: 8080
+  address: 10.0.0.2
+  tls:
+    cert: server.pem
+    host: tls.example.com
+`)))
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("single field", func(t *testing.T) {
+		cfg := live()
+		require.NoError(t, p.Get("server").PopulateFields(&cfg, "port"), "couldn't populate fields")
+		want := live()
+		want.Port = 8080
+		assert.Equal(t, want, cfg, "expected only port to change")
+	})
+
+	t.Run("tagged, promoted, and nested fields", func(t *testing.T) {
+		cfg := live()
+		err := p.Get("server").PopulateFields(&cfg, "address", "timeout", "tls.cert")
+		require.NoError(t, err, "couldn't populate fields")
+		assert.Equal(t, "10.0.0.2", cfg.Addr, "expected tagged field to change")
+		assert.Equal(t, 30, cfg.Timeout, "expected promoted field to change")
+		assert.Equal(t, "old.example.com", cfg.Host, "expected other promoted field to remain")
+		require.NotNil(t, cfg.TLS, "expected nested pointer to be allocated")
+		assert.Equal(t, TLSConfig{Cert: "server.pem"}, *cfg.TLS, "expected only the named nested field to be set")
+	})
+
+	t.Run("absent key", func(t *testing.T) {
+		cfg := live()
+		require.NoError(t, p.Get("server").PopulateFields(&cfg, "limits", "tls.timeout"), "couldn't populate fields")
+		assert.Equal(t, live(), cfg, "expected fields without configuration to remain untouched")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		cfg := live()
+		err := p.Get("server").PopulateFields(&cfg, "port", "unknown")
+		require.Error(t, err, "expected unknown field to fail")
+		assert.Contains(t, err.Error(), `can't populate field "unknown"`, "unexpected error message")
+
+		err = p.Get("server").PopulateFields(&cfg, "port", "limits.rps")
+		require.Error(t, err, "expected path through a map to fail")
+		assert.Contains(t, err.Error(), "isn't a struct", "unexpected error message")
+
+		bad, err := NewYAML(Source(strings.NewReader("port: 9090\naddress: [not, a, string]")))
+		require.NoError(t, err, "couldn't construct provider")
+		err = bad.Get(Root).PopulateFields(&cfg, "port", "address")
+		require.Error(t, err, "expected mismatched value to fail")
+		assert.Equal(t, live(), cfg, "expected no fields to change after an error")
+
+		assert.Error(t, p.Get("server").PopulateFields(cfg, "port"), "expected non-pointer target to fail")
+		assert.Error(t, p.Get("server").PopulateFields(&cfg, ""), "expected empty field path to fail")
+	})
+}
+
+func TestStrictPaths(t *testing.T) {
+	type security struct {
+		TLS struct {
+			CommonConfig
+			Cert string
+		}
+		Admins []struct{ Name string }
+	}
+	type vendor struct {
+		URL string
+	}
+	type app struct {
+		Security security
+		Vendor   vendor
+	}
+
+	provider := func(t testing.TB, src string, opts ...YAMLOption) *YAML {
+		opts = append([]YAMLOption{Source(strings.NewReader(src))}, opts...)
+		p, err := NewYAML(opts...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("unknown vendor key tolerated", func(t *testing.T) {
+		p := provider(t, `
+security:
+  tls: {cert: server.pem, host: example.com}
+vendor:
+  url: https://example.com
+  extra: true
+`, StrictPaths("security"))
+		var cfg app
+		require.NoError(t, p.Get(Root).Populate(&cfg), "unknown keys outside strict paths should be ignored")
+		assert.Equal(t, "server.pem", cfg.Security.TLS.Cert, "unexpected cert")
+		assert.Equal(t, "example.com", cfg.Security.TLS.Host, "promoted fields should be known")
+		assert.Equal(t, "https://example.com", cfg.Vendor.URL, "unexpected vendor URL")
+	})
+
+	t.Run("unknown security key rejected", func(t *testing.T) {
+		for _, src := range []string{
+			"security: {tls: {cert: server.pem, cipher: rc4}}",
+			"security: {admins: [{name: alice, role: root}]}",
+			"security: {debug: true}",
+		} {
+			p := provider(t, src, StrictPaths("security"))
+			var cfg app
+			err := p.Get(Root).Populate(&cfg)
+			require.Error(t, err, "expected populate of %q to fail", src)
+			assert.Contains(t, err.Error(), `strict key "security"`, "unexpected error message")
+		}
+	})
+
+	t.Run("populating inside strict path", func(t *testing.T) {
+		p := provider(t, "security: {tls: {cert: server.pem, cipher: rc4}}", StrictPaths("security"))
+		var tls struct{ Cert string }
+		assert.Error(t, p.Get("security.tls").Populate(&tls), "expected populate within strict path to fail")
+	})
+
+	t.Run("nested strict path", func(t *testing.T) {
+		p := provider(t, "security: {tls: {cert: server.pem, cipher: rc4}, debug: true}", StrictPaths("security.tls"))
+		var cfg app
+		err := p.Get(Root).Populate(&cfg)
+		require.Error(t, err, "expected populate to fail")
+		assert.Contains(t, err.Error(), `strict key "security.tls"`, "unexpected error message")
+	})
+
+	t.Run("permissive provider", func(t *testing.T) {
+		p := provider(t, "security: {debug: true}\nvendor: {extra: true}", Permissive(), StrictPaths("security"))
+		var cfg app
+		assert.Error(t, p.Get(Root).Populate(&cfg), "expected strict path to apply to permissive providers")
+		var v vendor
+		assert.NoError(t, p.Get("vendor").Populate(&v), "unexpected error outside strict path")
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		p := provider(t, "security: {debug: true}", StrictPaths("security"))
+		withDefault, err := p.Get(Root).WithDefault(map[string]interface{}{"vendor": map[string]interface{}{"extra": true}})
+		require.NoError(t, err, "couldn't add default")
+		var cfg app
+		assert.Error(t, withDefault.Populate(&cfg), "expected strict paths to survive WithDefault")
+	})
+}
+
+func TestPopulateRaw(t *testing.T) {
+	type pluginConfig struct {
+		Endpoint string
+		Retries  int
+	}
+	type host struct {
+		Name    string
+		Plugins map[string]Raw
+	}
+
+	p, err := NewYAML(Source(strings.NewReader(`
+name: host
+plugins:
+  auth:
+    endpoint: https://auth.example.com
+    retries: 3
+  metrics:
+    unknown_to_host: [a, b]
+    interval: 10s
+  disabled: ~
+`)))
+	require.NoError(t, err, "couldn't construct provider")
+
+	var cfg host
+	require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct in strict mode")
+	assert.Equal(t, "host", cfg.Name, "unexpected name")
+	require.Len(t, cfg.Plugins, 3, "unexpected number of plugins")
+	assert.Equal(t, "interval: 10s\nunknown_to_host:\n- a\n- b\n", string(cfg.Plugins["metrics"]), "unexpected raw metrics config")
+	disabled, ok := cfg.Plugins["disabled"]
+	assert.True(t, ok, "expected key for explicit null")
+	assert.Nil(t, disabled, "expected explicit null to leave Raw nil")
+
+	var auth pluginConfig
+	require.NoError(t, yaml.UnmarshalStrict(cfg.Plugins["auth"], &auth), "plugin couldn't decode its raw config")
+	assert.Equal(t, pluginConfig{Endpoint: "https://auth.example.com", Retries: 3}, auth, "unexpected plugin config")
+
+	t.Run("round trip", func(t *testing.T) {
+		roundTrip, err := NewYAML(Static(cfg))
+		require.NoError(t, err, "couldn't construct provider from populated struct")
+		assert.Equal(t, 3, roundTrip.Get("plugins.auth.retries").Value(), "expected Raw to marshal as structured YAML")
+		assert.Nil(t, roundTrip.Get("plugins.disabled").Value(), "expected null to round-trip")
+
+		var nilRaw Raw
+		bs, err := yaml.Marshal(map[string]Raw{"plugin": nilRaw})
+		require.NoError(t, err, "couldn't marshal nil Raw")
+		assert.Equal(t, "plugin: null\n", string(bs), "unexpected YAML for nil Raw")
+	})
+}
+
+func TestPopulateCount(t *testing.T) {
+	type server struct {
+		CommonConfig
+		Port   int
+		TLS    *TLSConfig
+		Tags   []string
+		Limits map[string]int
+	}
+
+	p, err := NewYAML(Source(strings.NewReader(`
+server:
+  host: example.com
+  port: 0
+  tls: {cert: server.pem}
+  tags: [a, b, c]
+  limits: {}
+  extra: ignored
+`)), Permissive())
+	require.NoError(t, err, "couldn't construct provider")
+
+	var cfg server
+	n, err := p.Get("server").PopulateCount(&cfg)
+	require.NoError(t, err, "couldn't populate struct")
+	// host, port, tls.cert, and three tags; limits is empty and extra has no
+	// corresponding field.
+	assert.Equal(t, 6, n, "unexpected leaf count")
+	assert.Equal(t, "example.com", cfg.Host, "expected struct to be populated")
+	assert.Equal(t, []string{"a", "b", "c"}, cfg.Tags, "expected struct to be populated")
+
+	t.Run("missing", func(t *testing.T) {
+		n, err := p.Get("not_there").PopulateCount(&cfg)
+		require.NoError(t, err, "couldn't populate struct")
+		assert.Zero(t, n, "expected no leaves")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var port struct{ Port []int }
+		n, err := p.Get("server").PopulateCount(&port)
+		assert.Error(t, err, "expected populate to fail")
+		assert.Zero(t, n, "expected no count on failure")
+	})
+}
+
+func TestStructTag(t *testing.T) {
+	type Backend struct {
+		Host string `json:"host_name"`
+	}
+	type server struct {
+		Backend
+		ListenPort int               `json:"listen_port,omitempty"`
+		Timeout    string            // untagged: lowercased field name
+		Secret     string            `json:"-"`
+		Limits     map[string]int    `json:"rate_limits"`
+		Upstreams  []Backend         `json:"upstreams"`
+		Labels     map[string]string `yaml:"labels_yaml" json:"labels"`
+	}
+
+	provider := func(t testing.TB, src string, opts ...YAMLOption) *YAML {
+		opts = append([]YAMLOption{Source(strings.NewReader(src)), StructTag("json")}, opts...)
+		p, err := NewYAML(opts...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("json tags", func(t *testing.T) {
+		p := provider(t, `
+host_name: example.com
+listen_port: 8080
+timeout: 1s
+rate_limits: {rps: 10}
+upstreams:
+  - host_name: a.example.com
+  - host_name: b.example.com
+labels: {team: core}
+`)
+		var cfg server
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate struct")
+		assert.Equal(t, server{
+			Backend:    Backend{Host: "example.com"},
+			ListenPort: 8080,
+			Timeout:    "1s",
+			Limits:     map[string]int{"rps": 10},
+			Upstreams:  []Backend{{Host: "a.example.com"}, {Host: "b.example.com"}},
+			Labels:     map[string]string{"team": "core"},
+		}, cfg, "unexpected populated struct")
+	})
+
+	t.Run("yaml keys are unknown", func(t *testing.T) {
+		for _, src := range []string{"listenport: 8080", "secret: hunter2", "labels_yaml: {team: core}"} {
+			var cfg server
+			err := provider(t, src).Get(Root).Populate(&cfg)
+			require.Error(t, err, "expected populate of %q to fail in strict mode", src)
+			assert.Contains(t, err.Error(), "doesn't match a json tag", "unexpected error message")
+
+			cfg = server{}
+			require.NoError(t, provider(t, src, Permissive()).Get(Root).Populate(&cfg), "unexpected error in permissive mode")
+			assert.Equal(t, server{}, cfg, "yaml keys shouldn't populate fields in permissive mode")
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		var cfg server
+		assert.Error(t, provider(t, "not_a_field: true").Get(Root).Populate(&cfg), "expected strict mode to reject unknown keys")
+	})
+
+	t.Run("plan", func(t *testing.T) {
+		paths, err := provider(t, "listen_port: 80\nhost_name: example.com").Get(Root).PopulatePlan(&server{})
+		require.NoError(t, err, "couldn't plan populate")
+		assert.Equal(t, []string{"host_name", "listen_port"}, paths, "unexpected paths")
+	})
+
+	t.Run("empty tag", func(t *testing.T) {
+		_, err := NewYAML(Static("foo"), StructTag(""))
+		assert.Error(t, err, "expected empty tag name to fail")
+	})
+}
+
+type normalizedServer struct {
+	Host  string
+	Ports []int
+
+	normalized int
+}
+
+func (s *normalizedServer) Normalize() {
+	s.normalized++
+	s.Host = strings.ToLower(strings.TrimSpace(s.Host))
+	if len(s.Ports) == 0 {
+		s.Ports = []int{80}
+	}
+}
+
+type normalizedLevel struct {
+	Level string `oneof:"debug info"`
+}
+
+func (l *normalizedLevel) Normalize() {
+	l.Level = strings.ToLower(l.Level)
+}
+
+func TestPopulateNormalize(t *testing.T) {
+	p, err := NewYAML(Source(strings.NewReader("server: {host: ' Example.COM '}\nbad: {ports: oops}")))
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("normalizes after decoding", func(t *testing.T) {
+		var s normalizedServer
+		require.NoError(t, p.Get("server").Populate(&s), "couldn't populate struct")
+		assert.Equal(t, "example.com", s.Host, "expected decoded host to be normalized")
+		assert.Equal(t, []int{80}, s.Ports, "expected normalization to fill in ports")
+		assert.Equal(t, 1, s.normalized, "expected Normalize to run once")
+	})
+
+	t.Run("not on decode failure", func(t *testing.T) {
+		var s normalizedServer
+		require.Error(t, p.Get("bad").Populate(&s), "expected populate to fail")
+		assert.Equal(t, 0, s.normalized, "expected Normalize not to run")
+	})
+
+	t.Run("not when missing", func(t *testing.T) {
+		var s normalizedServer
+		require.NoError(t, p.Get("missing").Populate(&s), "couldn't populate struct")
+		assert.Equal(t, 0, s.normalized, "expected Normalize not to run")
+	})
+
+	t.Run("before validation", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("level: INFO")))
+		require.NoError(t, err, "couldn't construct provider")
+		var l normalizedLevel
+		require.NoError(t, p.Get(Root).Populate(&l), "expected normalized value to be validated")
+		assert.Equal(t, "info", l.Level, "expected level to be normalized")
+	})
+
+	t.Run("holder", func(t *testing.T) {
+		h := NewHolder("server", func() interface{} { return &normalizedServer{} })
+		require.NoError(t, h.Store(p), "store failed")
+		assert.Equal(t, "example.com", h.Load().(*normalizedServer).Host, "expected held configuration to be normalized")
+	})
+}
+
+func TestPopulateErrorPaths(t *testing.T) {
+	type server struct {
+		Host string
+		Port int
+		Max  Bytes
+	}
+	type cluster struct {
+		Servers []server
+		Zones   map[string]server
+		Primary *server
+	}
+	const src = `
+servers:
+  - {host: a, port: 1}
+  - {host: b, port: 2}
+  - {host: c, port: 3}
+  - {host: d, port: not-a-port}
+zones:
+  east: {host: e, port: 5}
+primary: {host: p, port: 6}
+`
+	populate := func(t testing.TB, yaml string, opts ...YAMLOption) error {
+		p, err := NewYAML(append([]YAMLOption{Source(strings.NewReader(yaml))}, opts...)...)
+		require.NoError(t, err, "couldn't construct provider")
+		var c cluster
+		return p.Get(Root).Populate(&c)
+	}
+
+	t.Run("sequence element", func(t *testing.T) {
+		err := populate(t, src)
+		require.Error(t, err, "expected populate to fail")
+		assert.Equal(t, "couldn't populate key \"servers.3.port\": expected int, got string: cannot unmarshal !!str `not-a-port` into int", err.Error(), "unexpected error message")
+	})
+
+	t.Run("nested in value", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var servers []server
+		err = p.Get("servers").Populate(&servers)
+		require.Error(t, err, "expected populate to fail")
+		assert.Contains(t, err.Error(), `couldn't populate key "servers.3.port"`, "expected path to include the value's key")
+	})
+
+	t.Run("multiple errors", func(t *testing.T) {
+		err := populate(t, src, Source(strings.NewReader("zones: {west: {port: [1]}}\nprimary: {max: lots}")))
+		require.Error(t, err, "expected populate to fail")
+		msg := err.Error()
+		assert.Contains(t, msg, `couldn't populate key "servers.3.port"`, "expected sequence error")
+		assert.Contains(t, msg, `couldn't populate key "zones.west.port"`, "expected map error")
+		assert.Contains(t, msg, `couldn't populate key "primary.max": invalid byte size "lots"`, "expected custom unmarshaler error")
+	})
+
+	t.Run("unknown key in element", func(t *testing.T) {
+		err := populate(t, "servers: [{host: a}, {hots: b}]")
+		require.Error(t, err, "expected populate to fail")
+		assert.Contains(t, err.Error(), `couldn't populate key "servers.1": field hots not found in type`, "unexpected error message")
+	})
+
+	t.Run("root", func(t *testing.T) {
+		err := populate(t, "[1, 2]")
+		require.Error(t, err, "expected populate to fail")
+		assert.Contains(t, err.Error(), "cannot unmarshal !!seq into", "unexpected error message")
+	})
+}
+
+func TestPopulateTypeMismatchErrors(t *testing.T) {
+	type server struct {
+		Port    int            `yaml:"port"`
+		Hosts   []string       `yaml:"hosts"`
+		Limits  map[string]int `yaml:"limits"`
+		Debug   bool           `yaml:"debug"`
+		Timeout time.Duration  `yaml:"timeout"`
+	}
+	tests := []struct {
+		desc string
+		src  string
+		err  string
+	}{
+		{"string into int", "port: http", `couldn't populate key "server.port": expected int, got string`},
+		{"sequence into int", "port: [80]", `couldn't populate key "server.port": expected int, got sequence`},
+		{"mapping into slice", "hosts: {a: b}", `couldn't populate key "server.hosts": expected []string, got mapping`},
+		{"sequence into map", "limits: [1, 2]", `couldn't populate key "server.limits": expected map[string]int, got sequence`},
+		{"integer into bool", "debug: 1", `couldn't populate key "server.debug": expected bool, got integer`},
+		{"string into duration", "timeout: soon", `couldn't populate key "server.timeout": expected time.Duration, got string`},
+		{"nested element", "limits: {cpu: lots}", `couldn't populate key "server.limits.cpu": expected int, got string`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := NewYAML(Source(strings.NewReader("server:\n  " + tt.src)))
+			require.NoError(t, err, "couldn't construct provider")
+			var s server
+			err = p.Get("server").Populate(&s)
+			require.Error(t, err, "expected populate to fail")
+			assert.Contains(t, err.Error(), tt.err, "expected error to name the Go type and YAML kind")
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("server: {prot: 80}")))
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		err = p.Get("server").Populate(&s)
+		require.Error(t, err, "expected strict populate to fail")
+		assert.NotContains(t, err.Error(), "expected", "unknown keys aren't type mismatches")
+	})
+}
+
+func TestPopulateNonStringMapKeys(t *testing.T) {
+	type cfg struct {
+		Weights map[int]float64  `yaml:"weights"`
+		Flags   map[bool]string  `yaml:"flags"`
+		Ports   map[uint8]string `yaml:"ports"`
+	}
+	populate := func(t testing.TB, src string) (cfg, error) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		err = p.Get(Root).Populate(&c)
+		return c, err
+	}
+
+	t.Run("success", func(t *testing.T) {
+		c, err := populate(t, "weights: {1: 0.5, 2: 0.3}\nflags: {true: on-call, false: disabled}\nports: {80: http}")
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, map[int]float64{1: 0.5, 2: 0.3}, c.Weights, "unexpected integer-keyed map")
+		assert.Equal(t, map[bool]string{true: "on-call", false: "disabled"}, c.Flags, "unexpected Boolean-keyed map")
+		assert.Equal(t, map[uint8]string{80: "http"}, c.Ports, "unexpected uint8-keyed map")
+	})
+
+	t.Run("merged", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("weights: {1: 0.5, 2: 0.3}")),
+			Source(strings.NewReader("weights: {2: 0.4, 3: 0.1}")),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		var weights map[int]float64
+		require.NoError(t, p.Get("weights").Populate(&weights), "couldn't populate")
+		assert.Equal(t, map[int]float64{1: 0.5, 2: 0.4, 3: 0.1}, weights, "expected merged integer keys")
+	})
+
+	t.Run("key conversion errors", func(t *testing.T) {
+		_, err := populate(t, "weights: {1: 0.5, heavy: 0.9}\nports: {80: http, 8080: alt}")
+		require.Error(t, err, "expected populate to fail")
+		msg := err.Error()
+		assert.Contains(t, msg, "couldn't populate key \"weights.heavy\": invalid mapping key: cannot unmarshal !!str `heavy` into int", "expected string key error")
+		assert.Contains(t, msg, "couldn't populate key \"ports.8080\": invalid mapping key: cannot unmarshal !!int `8080` into uint8", "expected overflowing key error")
+		assert.NotContains(t, msg, `"weights.1"`, "valid keys shouldn't be reported")
+	})
+}
+
+func TestUseDefaultTags(t *testing.T) {
+	type tls struct {
+		Enabled bool   `default:"true"`
+		Cipher  string `default:"aes"`
+	}
+	type Common struct {
+		Region string `default:"us-east"`
+	}
+	type server struct {
+		Common
+		Host    string        `yaml:"host" default:"localhost"`
+		Port    int           `yaml:"port" default:"8080"`
+		Hosts   []string      `yaml:"hosts" default:"[a, b]"`
+		Timeout time.Duration `yaml:"timeout" default:"5s"`
+		Retries *int          `yaml:"retries" default:"3"`
+		TLS     tls           `yaml:"tls"`
+		Backup  *tls          `yaml:"backup"`
+		Plain   string        `yaml:"plain"`
+	}
+	type cluster struct {
+		Servers []server `yaml:"servers"`
+	}
+	provider := func(t testing.TB, src string, opts ...YAMLOption) *YAML {
+		p, err := NewYAML(append([]YAMLOption{Source(strings.NewReader(src)), UseDefaultTags()}, opts...)...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+	three := 3
+
+	t.Run("scalar and slice defaults", func(t *testing.T) {
+		var s server
+		require.NoError(t, provider(t, "host: example.com\ntls: {cipher: des}").Get(Root).Populate(&s), "couldn't populate")
+		assert.Equal(t, server{
+			Common:  Common{Region: "us-east"},
+			Host:    "example.com",
+			Port:    8080,
+			Hosts:   []string{"a", "b"},
+			Timeout: 5 * time.Second,
+			Retries: &three,
+			TLS:     tls{Enabled: true, Cipher: "des"},
+		}, s, "unexpected defaults")
+	})
+
+	t.Run("explicit zero values are kept", func(t *testing.T) {
+		var s server
+		require.NoError(t, provider(t, "port: 0\nhosts: []\ntls: {enabled: false}").Get(Root).Populate(&s), "couldn't populate")
+		assert.Equal(t, 0, s.Port, "expected explicit zero to be kept")
+		assert.Equal(t, []string{}, s.Hosts, "expected explicit empty sequence to be kept")
+		assert.False(t, s.TLS.Enabled, "expected explicit false to be kept")
+		assert.Equal(t, "aes", s.TLS.Cipher, "expected nested default")
+	})
+
+	t.Run("pre-set fields are kept", func(t *testing.T) {
+		s := server{Port: 9090}
+		require.NoError(t, provider(t, "{}").Get(Root).Populate(&s), "couldn't populate")
+		assert.Equal(t, 9090, s.Port, "expected pre-set field to be kept")
+	})
+
+	t.Run("sequence elements", func(t *testing.T) {
+		var c cluster
+		require.NoError(t, provider(t, "servers: [{host: a}, {port: 1, backup: {}}]").Get(Root).Populate(&c), "couldn't populate")
+		require.Len(t, c.Servers, 2, "unexpected number of servers")
+		assert.Equal(t, 8080, c.Servers[0].Port, "expected default in first element")
+		assert.Equal(t, "localhost", c.Servers[1].Host, "expected default in second element")
+		assert.Nil(t, c.Servers[0].Backup, "expected absent pointer to stay nil")
+		assert.Equal(t, &tls{Enabled: true, Cipher: "aes"}, c.Servers[1].Backup, "expected defaults in non-nil pointer")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		var s server
+		require.NoError(t, provider(t, "other: true", Permissive()).Get("server").Populate(&s), "couldn't populate")
+		assert.Equal(t, 8080, s.Port, "expected defaults without any configuration")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("{}")))
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		require.NoError(t, p.Get(Root).Populate(&s), "couldn't populate")
+		assert.Equal(t, server{}, s, "expected default tags to be ignored")
+	})
+
+	t.Run("survives WithDefault", func(t *testing.T) {
+		v, err := provider(t, "{}").Get(Root).WithDefault(map[string]string{"host": "fallback"})
+		require.NoError(t, err, "couldn't apply defaults")
+		var s server
+		require.NoError(t, v.Populate(&s), "couldn't populate")
+		assert.Equal(t, "fallback", s.Host, "expected WithDefault to apply")
+		assert.Equal(t, 8080, s.Port, "expected default tags to apply")
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		var bad struct {
+			Port int `default:"eighty"`
+		}
+		err := provider(t, "{}").Get(Root).Populate(&bad)
+		require.Error(t, err, "expected invalid default to fail")
+		assert.Contains(t, err.Error(), `invalid default "eighty" for field Port`, "unexpected error message")
+	})
+}
+
+func TestMergePopulate(t *testing.T) {
+	type tls struct {
+		Enabled bool   `yaml:"enabled"`
+		Cert    string `yaml:"cert"`
+	}
+	type server struct {
+		Host   string            `yaml:"host"`
+		Port   int               `yaml:"port" range:"1,65535"`
+		TLS    tls               `yaml:"tls"`
+		Labels map[string]string `yaml:"labels"`
+		Peers  []string          `yaml:"peers"`
+	}
+	p, err := NewYAML(Source(strings.NewReader(strings.Join([]string{
+		"base:",
+		"  host: localhost",
+		"  tls: {enabled: true, cert: base.pem}",
+		"  labels: {team: infra, tier: web}",
+		"  peers: [a, b]",
+		"override:",
+		"  port: 8080",
+		"  tls: {cert: override.pem}",
+		"  labels: {tier: api}",
+		"  peers: [c]",
+		"nulls:",
+		"  labels: ~",
+	}, "\n"))))
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("field-level merge", func(t *testing.T) {
+		var s server
+		require.NoError(t, MergePopulate(&s, p.Get("base"), p.Get("override")), "couldn't merge-populate")
+		assert.Equal(t, server{
+			Host:   "localhost",
+			Port:   8080,
+			TLS:    tls{Enabled: true, Cert: "override.pem"},
+			Labels: map[string]string{"team": "infra", "tier": "api"},
+			Peers:  []string{"c"},
+		}, s, "unexpected merged struct")
+	})
+
+	t.Run("order matters", func(t *testing.T) {
+		var s server
+		require.NoError(t, MergePopulate(&s, p.Get("override"), p.Get("base")), "couldn't merge-populate")
+		assert.Equal(t, "base.pem", s.TLS.Cert, "expected later values to win")
+		assert.Equal(t, 8080, s.Port, "expected earlier values to be kept")
+		assert.Equal(t, []string{"a", "b"}, s.Peers, "expected sequences to be replaced")
+	})
+
+	t.Run("absent values and nulls", func(t *testing.T) {
+		var s server
+		require.NoError(t, MergePopulate(&s, p.Get("base"), p.Get("missing"), p.Get("override"), p.Get("nulls")), "couldn't merge-populate")
+		assert.Equal(t, "localhost", s.Host, "expected absent values not to change the target")
+		assert.Nil(t, s.Labels, "expected explicit null to override earlier values")
+	})
+
+	t.Run("validation after merging", func(t *testing.T) {
+		var s server
+		require.NoError(t, MergePopulate(&s, p.Get("base"), p.Get("override")), "expected port from a later value to satisfy range tag")
+
+		err := MergePopulate(&server{}, p.Get("base"))
+		require.Error(t, err, "expected unset port to fail validation")
+		assert.Contains(t, err.Error(), `invalid value for key "port"`, "unexpected error message")
+	})
+
+	t.Run("multiple providers", func(t *testing.T) {
+		other, err := NewYAML(Source(strings.NewReader("host: example.com")))
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		require.NoError(t, MergePopulate(&s, p.Get("base"), p.Get("override"), other.Get(Root)), "couldn't merge-populate")
+		assert.Equal(t, "example.com", s.Host, "expected value from second provider to win")
+		assert.Equal(t, "override.pem", s.TLS.Cert, "expected values from first provider to be kept")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		bad, err := NewYAML(Source(strings.NewReader("port: http")))
+		require.NoError(t, err, "couldn't construct provider")
+		err = MergePopulate(&server{}, p.Get("base"), bad.Get(Root))
+		require.Error(t, err, "expected populate error")
+		assert.Contains(t, err.Error(), `couldn't populate key "port"`, "unexpected error message")
+
+		assert.NoError(t, MergePopulate(&server{}), "expected no values to be a no-op")
+	})
+}
+
+func TestStrictScalars(t *testing.T) {
+	type release struct {
+		Country string            `yaml:"country"`
+		Version string            `yaml:"version"`
+		Tags    []string          `yaml:"tags"`
+		Labels  map[string]string `yaml:"labels"`
+		Count   int               `yaml:"count"`
+		Build   json.Number       `yaml:"build"`
+		Note    *string           `yaml:"note"`
+	}
+	populate := func(t *testing.T, src string, opts ...YAMLOption) (release, error) {
+		p, err := NewYAML(append([]YAMLOption{Source(strings.NewReader(src))}, opts...)...)
+		require.NoError(t, err, "couldn't construct provider")
+		var r release
+		return r, p.Get(Root).Populate(&r)
+	}
+
+	t.Run("silently converted by default", func(t *testing.T) {
+		r, err := populate(t, "country: no\nversion: 1.0")
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, "false", r.Country, "expected the Norway problem without StrictScalars")
+		assert.Equal(t, "1", r.Version, "expected version to lose its fractional part without StrictScalars")
+	})
+
+	tests := []struct {
+		desc string
+		src  string
+		err  string
+	}{
+		{"Norway problem", "country: no", `couldn't populate key "country": expected a string, got boolean false`},
+		{"on", "country: on", `couldn't populate key "country": expected a string, got boolean true`},
+		{"numeric version", "version: 1.0", `couldn't populate key "version": expected a string, got number 1`},
+		{"decimal version", "version: 1.10", `couldn't populate key "version": expected a string, got number 1.1`},
+		{"sequence element", "tags: [a, yes]", `couldn't populate key "tags.1": expected a string, got boolean true`},
+		{"map value", "labels: {env: prod, rev: 42}", `couldn't populate key "labels.rev": expected a string, got number 42`},
+		{"pointer", "note: off", `couldn't populate key "note": expected a string, got boolean false`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := populate(t, tt.src, StrictScalars())
+			require.Error(t, err, "expected StrictScalars to reject %q", tt.src)
+			assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+			assert.Contains(t, err.Error(), "quote the value", "expected error to suggest quoting")
+		})
+	}
+
+	t.Run("quoted and non-string fields", func(t *testing.T) {
+		r, err := populate(t, strings.Join([]string{
+			`country: "no"`,
+			`version: '1.0'`,
+			`tags: [a, "yes"]`,
+			`count: 3`,
+			`build: 1234`,
+			`note: ~`,
+		}, "\n"), StrictScalars())
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, release{
+			Country: "no",
+			Version: "1.0",
+			Tags:    []string{"a", "yes"},
+			Count:   3,
+			Build:   "1234",
+		}, r, "unexpected populated struct")
+	})
+
+	t.Run("survives defaults", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("country: no")), StrictScalars())
+		require.NoError(t, err, "couldn't construct provider")
+		v, err := p.Get(Root).WithDefault(map[string]int{"count": 1})
+		require.NoError(t, err, "couldn't apply defaults")
+		var r release
+		assert.Error(t, v.Populate(&r), "expected StrictScalars to survive WithDefault")
+	})
+}
+
+func TestTrimStrings(t *testing.T) {
+	src := Source(strings.NewReader(strings.Join([]string{
+		`greeting: "  hello  "`,
+		`"  padded key ": value`,
+		`tags: [" a", "b "]`,
+		`timeout: " 5s "`,
+		`port: 8080`,
+		`debug: true`,
+		`empty: null`,
+		`raw: {note: " kept "}`,
+	}, "\n")))
+	type cfg struct {
+		Greeting  string
+		PaddedKey string `yaml:"  padded key "`
+		Tags      []string
+		Timeout   time.Duration
+		Port      int
+		Debug     bool
+		Empty     *string
+		Raw       interface{}
+	}
+
+	t.Run("trim", func(t *testing.T) {
+		p, err := NewYAML(src, TrimStrings())
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		require.NoError(t, p.Get(Root).Populate(&c), "couldn't populate")
+		assert.Equal(t, "hello", c.Greeting, "expected surrounding whitespace to be trimmed")
+		assert.Equal(t, "value", c.PaddedKey, "expected keys to be left alone")
+		assert.Equal(t, []string{"a", "b"}, c.Tags, "expected sequence elements to be trimmed")
+		assert.Equal(t, 5*time.Second, c.Timeout, "expected strings to be trimmed before unmarshaling")
+		assert.Equal(t, 8080, c.Port, "expected numbers to be untouched")
+		assert.True(t, c.Debug, "expected Booleans to be untouched")
+		assert.Nil(t, c.Empty, "expected nulls to be untouched")
+		assert.Equal(t, map[interface{}]interface{}{"note": "kept"}, c.Raw, "expected untyped strings to be trimmed")
+		assert.Equal(t, "hello", p.Get("greeting").Value(), "expected Value to be populated with trimmed strings")
+		canonical, err := p.CanonicalBytes()
+		require.NoError(t, err, "couldn't serialize provider")
+		assert.Contains(t, string(canonical), "  hello  ", "expected provider contents to be unchanged")
+
+		var greeting string
+		require.NoError(t, p.Get("greeting").Populate(&greeting), "couldn't populate scalar")
+		assert.Equal(t, "hello", greeting, "expected scalar to be trimmed")
+	})
+
+	t.Run("custom transform", func(t *testing.T) {
+		collapse := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+		p, err := NewYAML(
+			Source(strings.NewReader(`{name: " Ada   Lovelace ", port: 8080}`)),
+			TrimStringsFunc(collapse),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		var c struct {
+			Name string
+			Port int
+		}
+		require.NoError(t, p.Get(Root).Populate(&c), "couldn't populate")
+		assert.Equal(t, "Ada Lovelace", c.Name, "expected custom transform to be applied")
+		assert.Equal(t, 8080, c.Port, "expected numbers to be untouched")
+	})
+
+	t.Run("nil transform", func(t *testing.T) {
+		_, err := NewYAML(src, TrimStringsFunc(nil))
+		require.Error(t, err, "expected nil transform to fail")
+		assert.Contains(t, err.Error(), "must not be nil", "unexpected error message")
+	})
+}
+
+func TestUseEnvTags(t *testing.T) {
+	type TLS struct {
+		Enabled bool `env:"CONFIG_TEST_ENVTAGS_TLS"`
+	}
+	type cfg struct {
+		Port     int           `env:"CONFIG_TEST_ENVTAGS_PORT"`
+		Host     string        `env:"CONFIG_TEST_ENVTAGS_HOST"`
+		Name     string        `env:"CONFIG_TEST_ENVTAGS_NAME"`
+		Timeout  time.Duration `env:"CONFIG_TEST_ENVTAGS_TIMEOUT" default:"5s"`
+		Hosts    []string      `env:"CONFIG_TEST_ENVTAGS_HOSTS"`
+		Token    *string       `env:"CONFIG_TEST_ENVTAGS_TOKEN"`
+		TLS      TLS
+		Untagged int
+	}
+	file := Source(strings.NewReader("port: 80\nhost: file-host\nname: file-name\nhosts: [a]\nuntagged: 1"))
+
+	t.Run("environment overrides file", func(t *testing.T) {
+		for k, v := range map[string]string{
+			"CONFIG_TEST_ENVTAGS_PORT":    "9090",
+			"CONFIG_TEST_ENVTAGS_NAME":    "007",
+			"CONFIG_TEST_ENVTAGS_TIMEOUT": "1m",
+			"CONFIG_TEST_ENVTAGS_HOSTS":   "[b, c]",
+			"CONFIG_TEST_ENVTAGS_TOKEN":   "yes",
+			"CONFIG_TEST_ENVTAGS_TLS":     "true",
+		} {
+			require.NoError(t, os.Setenv(k, v), "couldn't set %s", k)
+			defer os.Unsetenv(k)
+		}
+		p, err := NewYAML(file, UseEnvTags(), UseDefaultTags())
+		require.NoError(t, err, "couldn't construct provider")
+
+		var c cfg
+		require.NoError(t, p.Get(Root).Populate(&c), "couldn't populate")
+		assert.Equal(t, 9090, c.Port, "expected environment variable to override file")
+		assert.Equal(t, "file-host", c.Host, "expected unset environment variable to fall back to file")
+		assert.Equal(t, "007", c.Name, "expected string fields to use the value as-is")
+		assert.Equal(t, time.Minute, c.Timeout, "expected environment variable to override default tag")
+		assert.Equal(t, []string{"b", "c"}, c.Hosts, "expected value to be parsed as YAML")
+		require.NotNil(t, c.Token, "expected pointer field to be set")
+		assert.Equal(t, "yes", *c.Token, "expected pointers to strings to use the value as-is")
+		assert.True(t, c.TLS.Enabled, "expected env tags in nested structs to apply")
+		assert.Equal(t, 1, c.Untagged, "expected untagged fields to be untouched")
+
+		var tls TLS
+		require.NoError(t, p.Get("missing").Populate(&tls), "couldn't populate absent key")
+		assert.True(t, tls.Enabled, "expected env tags to apply without configuration")
+	})
+
+	t.Run("unset variables", func(t *testing.T) {
+		p, err := NewYAML(file, UseEnvTags())
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		require.NoError(t, p.Get(Root).Populate(&c), "couldn't populate")
+		assert.Equal(t, 80, c.Port, "expected file value without environment variable")
+		assert.Equal(t, []string{"a"}, c.Hosts, "expected file value without environment variable")
+		assert.Nil(t, c.Token, "expected pointer to stay nil without environment variable")
+	})
+
+	t.Run("opt-in", func(t *testing.T) {
+		require.NoError(t, os.Setenv("CONFIG_TEST_ENVTAGS_PORT", "9090"), "couldn't set environment variable")
+		defer os.Unsetenv("CONFIG_TEST_ENVTAGS_PORT")
+		p, err := NewYAML(file)
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		require.NoError(t, p.Get(Root).Populate(&c), "couldn't populate")
+		assert.Equal(t, 80, c.Port, "expected env tags to be ignored without UseEnvTags")
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		require.NoError(t, os.Setenv("CONFIG_TEST_ENVTAGS_PORT", "many"), "couldn't set environment variable")
+		defer os.Unsetenv("CONFIG_TEST_ENVTAGS_PORT")
+		p, err := NewYAML(file, UseEnvTags())
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		err = p.Get(Root).Populate(&c)
+		require.Error(t, err, "expected unparseable value to fail")
+		assert.Contains(t, err.Error(), `invalid value "many" in environment variable CONFIG_TEST_ENVTAGS_PORT for field Port`, "unexpected error message")
+	})
+}
+
+func TestPopulateAll(t *testing.T) {
+	type Base struct {
+		Region string
+		Zone   int
+	}
+	type cfg struct {
+		Base    `yaml:",inline"`
+		Name    string
+		Port    int
+		Timeout time.Duration
+		Tags    []string
+		Ignored string `yaml:"-"`
+		Server  struct {
+			Host  string
+			Level string `oneof:"debug info"`
+		}
+	}
+	newProvider := func(t testing.TB, yml string, opts ...YAMLOption) *YAML {
+		p, err := NewYAML(append([]YAMLOption{Source(strings.NewReader(yml))}, opts...)...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("all errors", func(t *testing.T) {
+		p := newProvider(t, strings.Join([]string{
+			"name: svc",
+			"port: eighty",
+			"timeout: soon",
+			"tags: {a: b}",
+			"region: us-east",
+			"zone: [1]",
+			"server: {host: example.com, level: info}",
+		}, "\n"))
+		c := cfg{Ignored: "kept"}
+		errs := p.Get(Root).PopulateAll(&c)
+		require.Len(t, errs, 4, "expected an error for each misconfigured field, got %v", errs)
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		all := strings.Join(msgs, "\n")
+		assert.Contains(t, all, `couldn't populate key "port": expected int, got string`, "expected port error")
+		assert.Contains(t, all, `couldn't populate key "timeout"`, "expected timeout error")
+		assert.Contains(t, all, `couldn't populate key "tags": expected []string, got mapping`, "expected tags error")
+		assert.Contains(t, all, `couldn't populate key "zone": expected int, got sequence`, "expected embedded field error")
+
+		assert.Equal(t, "svc", c.Name, "expected valid fields to be populated")
+		assert.Equal(t, "example.com", c.Server.Host, "expected valid nested fields to be populated")
+		assert.Equal(t, "us-east", c.Region, "expected valid embedded fields to be populated")
+		assert.Equal(t, "kept", c.Ignored, "expected skipped fields to be untouched")
+	})
+
+	t.Run("checks before decoding", func(t *testing.T) {
+		p := newProvider(t, "name: 42\nport: eighty\nserver: {level: loud}", StrictScalars())
+		var c cfg
+		require.Error(t, p.Get(Root).Populate(&c), "expected Populate to fail")
+		errs := p.Get(Root).PopulateAll(&c)
+		require.Len(t, errs, 3, "expected strict scalar, decode, and validation errors, got %v", errs)
+		assert.Contains(t, errs[0].Error(), `key "name"`, "expected strict scalar error first")
+		assert.Contains(t, errs[1].Error(), `key "port"`, "expected decode error second")
+		assert.Contains(t, errs[2].Error(), "server.level", "expected nested validation error last")
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		p := newProvider(t, "port: eighty\nprot: 80\nserver: {level: info}")
+		var c cfg
+		errs := p.Get(Root).PopulateAll(&c)
+		require.Len(t, errs, 2, "expected decode and unknown key errors, got %v", errs)
+		assert.Contains(t, errs[1].Error(), "prot", "expected unknown key to be reported")
+	})
+
+	t.Run("embedded structs", func(t *testing.T) {
+		type embedding struct {
+			Base
+			Port int
+		}
+		p := newProvider(t, "region: us-west\nzone: three\nport: eighty")
+		var c embedding
+		errs := p.Get(Root).PopulateAll(&c)
+		require.Len(t, errs, 2, "expected errors from the field and the embedded struct, got %v", errs)
+		assert.Contains(t, errs[0].Error(), `key "port"`, "expected field error first")
+		assert.Contains(t, errs[1].Error(), "zone", "expected promoted field error")
+		assert.Equal(t, "us-west", c.Region, "expected valid promoted fields to be populated")
+	})
+
+	t.Run("field tags", func(t *testing.T) {
+		type tagged struct {
+			Port     int
+			Labels   map[string]string `format:"json"`
+			Endpoint struct {
+				Host string
+				Port int
+			} `format:"tuple"`
+			Name  string `default:"anon"`
+			Token string `env:"CONFIG_TEST_POPULATEALL_TOKEN"`
+			Zone  int
+		}
+		require.NoError(t, os.Setenv("CONFIG_TEST_POPULATEALL_TOKEN", "secret"), "couldn't set environment variable")
+		defer os.Unsetenv("CONFIG_TEST_POPULATEALL_TOKEN")
+		p := newProvider(t, strings.Join([]string{
+			"port: eighty",
+			`labels: '{"team": "infra"}'`,
+			"endpoint: [example.com, 443]",
+			"zone: [1]",
+		}, "\n"), UseDefaultTags(), UseEnvTags())
+		var c tagged
+		errs := p.Get(Root).PopulateAll(&c)
+		require.Len(t, errs, 2, "expected errors only for the misconfigured fields, got %v", errs)
+		assert.Contains(t, errs[0].Error(), `key "port"`, "expected port error")
+		assert.Contains(t, errs[1].Error(), `key "zone"`, "expected zone error")
+		assert.Equal(t, map[string]string{"team": "infra"}, c.Labels, "expected JSON field to be decoded")
+		assert.Equal(t, "example.com", c.Endpoint.Host, "expected tuple field to be decoded")
+		assert.Equal(t, 443, c.Endpoint.Port, "expected tuple field to be decoded")
+		assert.Equal(t, "anon", c.Name, "expected default to be applied")
+		assert.Equal(t, "secret", c.Token, "expected env tag to be applied")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		p := newProvider(t, "name: svc\nserver: {host: example.com, level: debug}")
+		var c cfg
+		assert.Nil(t, p.Get(Root).PopulateAll(&c), "expected no errors")
+		assert.Equal(t, "svc", c.Name, "unexpected name")
+	})
+
+	t.Run("non-struct target", func(t *testing.T) {
+		p := newProvider(t, "ports: [1, two, three]")
+		var ports []int
+		errs := p.Get("ports").PopulateAll(&ports)
+		assert.Len(t, errs, 2, "expected Populate's located errors, got %v", errs)
+	})
+}
+
+func TestLoadInto(t *testing.T) {
+	type tls struct {
+		Enabled bool   `yaml:"enabled"`
+		Cert    string `yaml:"cert"`
+	}
+	type server struct {
+		Host    string            `yaml:"host"`
+		Port    int               `yaml:"port"`
+		Timeout time.Duration     `yaml:"timeout"`
+		TLS     tls               `yaml:"tls"`
+		Labels  map[string]string `yaml:"labels"`
+		Peers   []string          `yaml:"peers"`
+	}
+	defaults := func() server {
+		return server{
+			Host:    "localhost",
+			Port:    80,
+			Timeout: time.Second,
+			TLS:     tls{Enabled: true, Cert: "default.pem"},
+			Labels:  map[string]string{"team": "infra", "tier": "web"},
+			Peers:   []string{"a", "b"},
+		}
+	}
+
+	t.Run("overrides some fields", func(t *testing.T) {
+		s := defaults()
+		err := LoadInto(&s,
+			Source(strings.NewReader("port: 8080\ntls: {cert: override.pem}\nlabels: {tier: api}")),
+			Source(strings.NewReader("peers: [c]")),
+		)
+		require.NoError(t, err, "couldn't load configuration")
+		assert.Equal(t, server{
+			Host:    "localhost",
+			Port:    8080,
+			Timeout: time.Second,
+			TLS:     tls{Enabled: true, Cert: "override.pem"},
+			Labels:  map[string]string{"team": "infra", "tier": "api"},
+			Peers:   []string{"c"},
+		}, s, "expected configuration to override defaults field by field")
+	})
+
+	t.Run("empty configuration", func(t *testing.T) {
+		s := defaults()
+		require.NoError(t, LoadInto(&s, Source(strings.NewReader(""))), "couldn't load configuration")
+		assert.Equal(t, defaults(), s, "expected defaults to be left intact")
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		s := defaults()
+		err := LoadInto(&s, Source(strings.NewReader("prot: 8080")))
+		require.Error(t, err, "expected unknown key to fail in strict mode")
+		assert.Contains(t, err.Error(), "field prot not found", "unexpected error message")
+
+		s = defaults()
+		require.NoError(t, LoadInto(&s, Source(strings.NewReader("prot: 8080")), Permissive()), "expected Permissive to allow unknown keys")
+		assert.Equal(t, 80, s.Port, "unexpected port")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		s := defaults()
+		err := LoadInto(&s, Source(strings.NewReader("port: http")))
+		require.Error(t, err, "expected type mismatch")
+		assert.Contains(t, err.Error(), `couldn't populate key "port"`, "expected error to name the key")
+
+		err = LoadInto(&s, File("testdata/does-not-exist.yaml"))
+		require.Error(t, err, "expected missing file to fail")
+
+		err = LoadInto(s, Source(strings.NewReader("port: 1")))
+		require.Error(t, err, "expected non-pointer target to fail")
+		assert.Contains(t, err.Error(), "must be a non-nil pointer", "unexpected error message")
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Raw captures a subtree of configuration as YAML, without decoding it. It's
+// useful for configuration whose schema isn't known to the code populating
+// it: for example, a host application can populate
+//   type HostConfig struct {
+//     Plugins map[string]config.Raw
+//   }
+// and pass each plugin its own block, leaving validation to the plugin. The
+// captured YAML is re-serialized after merging and variable expansion, so
+// it's already in canonical form (see CanonicalBytes). Like other types,
+// Raw is left nil by an explicit null.
+//
+// Raw also marshals back to the YAML it holds, so it round-trips through
+// Static.
+type Raw []byte
+
+var (
+	_ yaml.Unmarshaler = (*Raw)(nil)
+	_ yaml.Marshaler   = Raw(nil)
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (r *Raw) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var node interface{}
+	if err := unmarshal(&node); err != nil {
+		return err
+	}
+	bs, err := yaml.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("couldn't capture raw YAML: %v", err)
+	}
+	*r = bs
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler. A nil Raw marshals to null.
+func (r Raw) MarshalYAML() (interface{}, error) {
+	if r == nil {
+		return nil, nil
+	}
+	var node interface{}
+	if err := yaml.Unmarshal(r, &node); err != nil {
+		return nil, fmt.Errorf("invalid raw YAML: %v", err)
+	}
+	return node, nil
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Registry maps the values of a discriminator key to concrete types, so
+// that Populate can decode tagged unions into interface-typed fields. For
+// example, with a registry using the discriminator key "type",
+//   plugins:
+//     - {type: http, port: 80}
+//     - {type: file, path: /var/log/app}
+// populates a []Plugin field with a *HTTPConfig and a *FileConfig if
+// factories for those types were registered under "http" and "file". See
+// TypeRegistry.
+//
+// Registries are safe for concurrent use.
+type Registry struct {
+	key string
+
+	mu        sync.RWMutex
+	factories map[string]func() interface{}
+}
+
+// NewRegistry creates an empty registry whose tagged unions are
+// distinguished by the value of the supplied key.
+func NewRegistry(discriminator string) (*Registry, error) {
+	if discriminator == "" {
+		return nil, errors.New("discriminator key must not be empty")
+	}
+	return &Registry{key: discriminator, factories: make(map[string]func() interface{})}, nil
+}
+
+// Register associates a discriminator value with a factory, which must
+// return a new, non-nil pointer (typically to a struct) each time it's called.
+// It returns an error if the name is already registered.
+func (r *Registry) Register(name string, factory func() interface{}) error {
+	if factory == nil {
+		return fmt.Errorf("can't register %q: factory is nil", name)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("%s %q is already registered", r.key, name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+func (r *Registry) factory(name string) (func() interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.factories[name]
+	return f, ok
+}
+
+func (r *Registry) names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TypeRegistry makes Populate use a registry to decode tagged unions. When
+// populating an interface-typed value (a struct field, sequence element, or
+// map value, or the target itself) from a mapping that contains the
+// registry's discriminator key, Populate creates a value with the factory
+// registered for the discriminator's value and populates it from the rest of
+// the mapping, using the same rules as any other Populate. Discriminators
+// without a registered factory make Populate return an error naming the key.
+// Interface-typed values populated from anything else, including mappings
+// without the discriminator key, are left as gopkg.in/yaml.v2 decodes them.
+//
+// If a field's type is a non-empty interface, the factory's values must
+// implement it. Snapshots don't record the registry.
+func TypeRegistry(r *Registry) YAMLOption {
+	if r == nil {
+		return failed(errors.New("type registry must not be nil"))
+	}
+	return optionFunc(func(c *config) {
+		c.registry = r
+	})
+}
+
+// isUnion reports whether a node is a tagged union.
+func (r *Registry) isUnion(node interface{}) bool {
+	m, ok := node.(map[interface{}]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = m[r.key]
+	return ok
+}
+
+// hide returns a copy of a node with the tagged unions that populate
+// interface-typed values replaced by nulls. gopkg.in/yaml.v2 can't decode
+// mappings into non-empty interfaces, so unions are populated separately by
+// resolveTypes.
+func (r *Registry) hide(node interface{}, t reflect.Type) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Interface:
+		if r.isUnion(node) {
+			return nil, nil
+		}
+	case reflect.Ptr:
+		return r.hide(node, t.Elem())
+	case reflect.Struct:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return node, nil
+		}
+		fields, err := structFields(t)
+		if err != nil {
+			return nil, err
+		}
+		hidden := make(map[interface{}]interface{}, len(m))
+		for k, v := range m {
+			hidden[k] = v
+		}
+		for _, field := range fields {
+			child, ok := m[field.key]
+			if !ok {
+				continue
+			}
+			if hidden[field.key], err = r.hide(child, field.typ); err != nil {
+				return nil, err
+			}
+		}
+		return hidden, nil
+	case reflect.Slice, reflect.Array:
+		seq, ok := node.([]interface{})
+		if !ok {
+			return node, nil
+		}
+		hidden := make([]interface{}, len(seq))
+		for i, elem := range seq {
+			var err error
+			if hidden[i], err = r.hide(elem, t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+		return hidden, nil
+	case reflect.Map:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return node, nil
+		}
+		hidden := make(map[interface{}]interface{}, len(m))
+		for k, v := range m {
+			var err error
+			if hidden[k], err = r.hide(v, t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+		return hidden, nil
+	}
+	return node, nil
+}
+
+// resolveTypes walks a populated Go value alongside the YAML node it was
+// populated from, replacing the contents of interface-typed values that
+// hold tagged unions.
+func (y *YAML) resolveTypes(node interface{}, v reflect.Value, path []string) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.CanSet() {
+			return nil
+		}
+		resolved, ok, err := y.resolveType(node, v.Type(), path)
+		if err != nil || !ok {
+			return err
+		}
+		v.Set(resolved)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return y.resolveTypes(node, v.Elem(), path)
+	case reflect.Struct:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		fields, err := structFields(v.Type())
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			child, ok := m[field.key]
+			if !ok || field.unexported {
+				continue
+			}
+			if err := y.resolveTypes(child, v.FieldByIndex(field.index), extend(path, field.key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		seq, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i := 0; i < len(seq) && i < v.Len(); i++ {
+			if err := y.resolveTypes(seq[i], v.Index(i), extend(path, fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok || v.IsNil() {
+			return nil
+		}
+		for _, k := range v.MapKeys() {
+			child, ok := m[k.Interface()]
+			if !ok {
+				continue
+			}
+			// Map values aren't addressable, so resolve a copy and store it.
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(k))
+			if err := y.resolveTypes(child, elem, extend(path, fmt.Sprint(k.Interface()))); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, elem)
+		}
+	}
+	return nil
+}
+
+// resolveType populates a registered type from a tagged union. It reports
+// false if the node isn't a tagged union.
+func (y *YAML) resolveType(node interface{}, t reflect.Type, path []string) (reflect.Value, bool, error) {
+	if !y.registry.isUnion(node) {
+		return reflect.Value{}, false, nil
+	}
+	m := node.(map[interface{}]interface{})
+	disc := m[y.registry.key]
+	at := strings.Join(extend(path, y.registry.key), _separator)
+	name, ok := disc.(string)
+	if !ok {
+		return reflect.Value{}, false, fmt.Errorf("invalid %s at key %q: expected a string, got %v", y.registry.key, at, disc)
+	}
+	factory, ok := y.registry.factory(name)
+	if !ok {
+		return reflect.Value{}, false, fmt.Errorf(
+			"unknown %s %q at key %q: registered %ss are %v",
+			y.registry.key, name, at, y.registry.key, y.registry.names(),
+		)
+	}
+	target := factory()
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() {
+		return reflect.Value{}, false, fmt.Errorf("factory for %s %q returned %T, not a non-nil pointer", y.registry.key, name, target)
+	}
+	if !tv.Type().AssignableTo(t) {
+		return reflect.Value{}, false, fmt.Errorf("%s %q at key %q: %T doesn't implement %v", y.registry.key, name, at, target, t)
+	}
+	rest := make(map[interface{}]interface{}, len(m)-1)
+	for k, v := range m {
+		if k != y.registry.key {
+			rest[k] = v
+		}
+	}
+	if err := y.decode(rest, path, target, nil /* applied */); err != nil {
+		return reflect.Value{}, false, err
+	}
+	return tv, true, nil
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type plugin interface {
+	pluginName() string
+}
+
+type httpPlugin struct {
+	Port int `yaml:"port"`
+}
+
+func (*httpPlugin) pluginName() string { return "http" }
+
+type filePlugin struct {
+	Path string `yaml:"path"`
+}
+
+func (*filePlugin) pluginName() string { return "file" }
+
+func newPluginRegistry(t *testing.T) *Registry {
+	r, err := NewRegistry("type")
+	require.NoError(t, err, "couldn't create registry")
+	require.NoError(t, r.Register("http", func() interface{} { return &httpPlugin{} }), "couldn't register http")
+	require.NoError(t, r.Register("file", func() interface{} { return &filePlugin{} }), "couldn't register file")
+	return r
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("empty discriminator", func(t *testing.T) {
+		_, err := NewRegistry("")
+		assert.Error(t, err, "expected error for empty discriminator key")
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		r := newPluginRegistry(t)
+		err := r.Register("http", func() interface{} { return &httpPlugin{} })
+		require.Error(t, err, "expected error registering a name twice")
+		assert.Contains(t, err.Error(), `type "http" is already registered`, "unexpected error message")
+	})
+
+	t.Run("nil registry", func(t *testing.T) {
+		_, err := NewYAML(TypeRegistry(nil))
+		assert.Error(t, err, "expected error for nil registry")
+	})
+}
+
+func TestTypeRegistry(t *testing.T) {
+	const src = `
+plugins:
+  - type: http
+    port: 80
+  - type: file
+    path: /var/log/app
+primary: {type: file, path: /tmp/primary}
+any: {type: http, port: 8080}
+byName:
+  web: {type: http, port: 443}
+untyped: {port: 1}
+`
+	p, err := NewYAML(Source(strings.NewReader(src)), TypeRegistry(newPluginRegistry(t)))
+	require.NoError(t, err, "couldn't create provider")
+
+	type cfg struct {
+		Plugins []plugin          `yaml:"plugins"`
+		Primary plugin            `yaml:"primary"`
+		Any     interface{}       `yaml:"any"`
+		ByName  map[string]plugin `yaml:"byName"`
+		Untyped interface{}       `yaml:"untyped"`
+	}
+	var c cfg
+	require.NoError(t, p.Get(Root).Populate(&c), "populate failed")
+	assert.Equal(t, []plugin{&httpPlugin{Port: 80}, &filePlugin{Path: "/var/log/app"}}, c.Plugins, "unexpected plugins")
+	assert.Equal(t, &filePlugin{Path: "/tmp/primary"}, c.Primary, "unexpected primary plugin")
+	assert.Equal(t, &httpPlugin{Port: 8080}, c.Any, "unexpected empty-interface plugin")
+	assert.Equal(t, map[string]plugin{"web": &httpPlugin{Port: 443}}, c.ByName, "unexpected plugins by name")
+	assert.Equal(t, map[interface{}]interface{}{"port": 1}, c.Untyped, "mappings without a discriminator should decode as usual")
+
+	var primary plugin
+	require.NoError(t, p.Get("primary").Populate(&primary), "populate of interface target failed")
+	assert.Equal(t, &filePlugin{Path: "/tmp/primary"}, primary, "unexpected interface target")
+}
+
+func TestTypeRegistryErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		src  string
+		want string
+	}{
+		{
+			desc: "unknown type",
+			src:  "plugins: [{type: http, port: 80}, {type: grpc}]",
+			want: `unknown type "grpc" at key "plugins.1.type": registered types are [file http]`,
+		},
+		{
+			desc: "non-string discriminator",
+			src:  "plugins: [{type: [http]}]",
+			want: `invalid type at key "plugins.0.type": expected a string`,
+		},
+		{
+			desc: "strict fields",
+			src:  "plugins: [{type: http, port: 80, path: /tmp}]",
+			want: "path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := NewYAML(Source(strings.NewReader(tt.src)), TypeRegistry(newPluginRegistry(t)))
+			require.NoError(t, err, "couldn't create provider")
+			var c struct {
+				Plugins []plugin `yaml:"plugins"`
+			}
+			err = p.Get(Root).Populate(&c)
+			require.Error(t, err, "expected populate to fail")
+			assert.Contains(t, err.Error(), tt.want, "unexpected error message")
+		})
+	}
+
+	t.Run("missing interface", func(t *testing.T) {
+		r, err := NewRegistry("type")
+		require.NoError(t, err, "couldn't create registry")
+		require.NoError(t, r.Register("str", func() interface{} { return new(string) }), "couldn't register str")
+		p, err := NewYAML(Static(map[string]interface{}{"p": map[string]interface{}{"type": "str"}}), TypeRegistry(r))
+		require.NoError(t, err, "couldn't create provider")
+		var c struct {
+			P plugin `yaml:"p"`
+		}
+		err = p.Get(Root).Populate(&c)
+		require.Error(t, err, "expected error for a type that doesn't implement the field's interface")
+		assert.Contains(t, err.Error(), "doesn't implement config.plugin", "unexpected error message")
+	})
+}
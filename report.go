@@ -0,0 +1,73 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// A Report describes how a target was populated. See PopulateReport.
+type Report struct {
+	// Unused lists the configured keys that the target has no field for, as
+	// reported by PopulateWithUnused.
+	Unused []string
+	// Defaulted lists the dotted keys of fields set from default struct tags
+	// because the configuration didn't set them (see UseDefaultTags).
+	Defaulted []string
+	// Warnings holds the provider's warnings about deprecated keys, as
+	// reported by Warnings.
+	Warnings []string
+}
+
+// PopulateReport populates target from the root of the configuration, like
+// Get(Root).Populate, and reports everything worth knowing about the result:
+// unused keys, fields filled in from defaults, and deprecation warnings. It's
+// meant to be logged once at startup. Paths in the report are sorted. If
+// populating fails, the error is returned with an empty report.
+func (y *YAML) PopulateReport(target interface{}) (Report, error) {
+	t := reflect.TypeOf(target)
+	if t == nil {
+		return Report{}, errors.New("can't populate a nil target")
+	}
+	var defaulted []string
+	err := y.populateRecording(nil /* path */, target, &defaulted)
+	if y.observer != nil {
+		y.observer.OnPopulate(Root, err)
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	var unused []string
+	if !y.empty {
+		if err := y.collectUnused(&unused, y.contents, t, nil /* path */); err != nil {
+			return Report{}, err
+		}
+	}
+	sort.Strings(unused)
+	sort.Strings(defaulted)
+	return Report{
+		Unused:    unused,
+		Defaulted: defaulted,
+		Warnings:  y.Warnings(),
+	}, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopulateReport(t *testing.T) {
+	type tls struct {
+		Cert string `yaml:"cert" default:"server.pem"`
+	}
+	type backend struct {
+		Name    string `yaml:"name"`
+		Weight  int    `yaml:"weight" default:"1"`
+		Retries int    `yaml:"retries"`
+	}
+	type server struct {
+		Host     string    `yaml:"host" default:"localhost"`
+		Port     int       `yaml:"port" default:"80"`
+		TLS      *tls      `yaml:"tls"`
+		Backends []backend `yaml:"backends"`
+	}
+	type cfg struct {
+		Server server `yaml:"server"`
+		Name   string `yaml:"name"`
+	}
+
+	p, err := NewYAML(
+		Source(strings.NewReader(`
+name: api
+legacy_name: old-api
+server:
+  port: 8080
+  tls: {}
+  backends:
+    - {name: a, weight: 5}
+    - {name: b, extra: true}
+`)),
+		Permissive(),
+		UseDefaultTags(),
+		DeprecateKeys(map[string]string{"legacy_name": "use name instead"}),
+	)
+	require.NoError(t, err, "couldn't construct provider")
+
+	var c cfg
+	report, err := p.PopulateReport(&c)
+	require.NoError(t, err, "couldn't populate")
+	assert.Equal(t, Report{
+		Unused:    []string{"legacy_name", "server.backends.1.extra"},
+		Defaulted: []string{"server.backends.1.weight", "server.host", "server.tls.cert"},
+		Warnings:  []string{`key "legacy_name" is deprecated: use name instead`},
+	}, report, "unexpected report")
+	assert.Equal(t, "localhost", c.Server.Host, "expected target to be populated with defaults")
+	assert.Equal(t, 8080, c.Server.Port, "expected configured value to take priority")
+	assert.Equal(t, 1, c.Server.Backends[1].Weight, "expected defaults in sequence elements")
+
+	t.Run("empty configuration", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("# nothing here")), UseDefaultTags())
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		report, err := p.PopulateReport(&s)
+		require.NoError(t, err, "couldn't populate")
+		assert.Empty(t, report.Unused, "expected no unused keys")
+		assert.Equal(t, []string{"host", "port"}, report.Defaulted, "unexpected defaulted fields")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := p.PopulateReport(nil)
+		assert.Error(t, err, "expected nil target to fail")
+
+		var bad struct {
+			Name int `yaml:"name"`
+		}
+		report, err := p.PopulateReport(&bad)
+		assert.Error(t, err, "expected mismatched type to fail")
+		assert.Equal(t, Report{}, report, "expected empty report on error")
+	})
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/config/internal/merge"
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// SetSource builds a source of YAML configuration from key=value pairs, like
+// the --set flags of many command-line tools. Each key is a dotted path, and
+// each value is interpreted using YAML's rules for scalars, so
+//   SetSource("server.port=9090", "server.name=api", "feature.enabled=true")
+// is equivalent to the YAML
+//   server: {port: 9090, name: api}
+//   feature: {enabled: true}
+// Values that aren't a single plain YAML scalar (for example, "[a, b]",
+// "'quoted'", or anything containing a comment, like "#ff0000") are strings,
+// as is an empty value; use key=null for an explicit null. Values are used
+// literally and never expanded.
+//
+// Path segments that are non-negative integers address sequence elements, so
+// servers.0.host=a sets the host of the first server. Elements must be set in
+// order, with no gaps. Since sequences are replaced when merging, setting any
+// element replaces the whole sequence from lower-priority sources. Setting
+// the same key more than once keeps the last value; setting both a key and
+// a key nested inside it is an error.
+//
+// To give the pairs priority over other configuration, supply SetSource last.
+func SetSource(pairs ...string) YAMLOption {
+	settings := make([]setting, 0, len(pairs))
+	for _, pair := range pairs {
+		eq := strings.IndexByte(pair, '=')
+		if eq <= 0 {
+			return failed(fmt.Errorf("invalid setting %q: expected key=value", pair))
+		}
+		settings = append(settings, setting{
+			name:  pair,
+			path:  strings.Split(pair[:eq], _separator),
+			value: pair[eq+1:],
+		})
+	}
+	bs, err := settingsYAML(settings, "invalid setting %q")
+	if err != nil {
+		return failed(err)
+	}
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{bytes: bs, raw: true, name: "SetSource"})
+	})
+}
+
+// FlagSet builds a source of YAML configuration from the flags in fs that
+// were set on the command line, so that flags override configuration files:
+//   fs := flag.NewFlagSet("server", flag.ExitOnError)
+//   fs.Int("server.port", 80, "port to listen on")
+//   fs.Parse(os.Args[1:])
+//   provider, err := config.NewYAML(config.File("base.yaml"), config.FlagSet(fs))
+// Each flag's name is a dotted path, and its value (as formatted by its
+// flag.Value) is interpreted as in SetSource, so --server.port=9090 is
+// equivalent to the YAML
+//   server: {port: 9090}
+// Only flags that were set are used (see flag.FlagSet.Visit), so a flag's
+// default never overrides other configuration; to make flag defaults part of
+// the configuration, supply them with Static or Source. Values are used
+// literally and never expanded.
+//
+// The flags are read when the provider is constructed, which must happen
+// after fs.Parse. To give the flags priority over other configuration,
+// supply FlagSet last.
+func FlagSet(fs *flag.FlagSet) YAMLOption {
+	if fs == nil {
+		return failed(errors.New("flag set must not be nil"))
+	}
+	return optionFunc(func(c *config) {
+		c.sources = append(c.sources, source{name: "FlagSet", load: func(int64) ([]source, error) {
+			if !fs.Parsed() {
+				return nil, fmt.Errorf("can't read flags from %s: flags haven't been parsed", fs.Name())
+			}
+			var settings []setting
+			fs.Visit(func(f *flag.Flag) {
+				settings = append(settings, setting{
+					name:  f.Name,
+					path:  strings.Split(f.Name, _separator),
+					value: f.Value.String(),
+				})
+			})
+			bs, err := settingsYAML(settings, "invalid flag %q")
+			return []source{{bytes: bs, raw: true, name: "FlagSet"}}, err
+		}})
+	})
+}
+
+// A setting assigns a value, interpreted as a YAML scalar, to a path. Its
+// name identifies it in errors.
+type setting struct {
+	name  string
+	path  []string
+	value string
+}
+
+// settingsYAML builds YAML from a list of settings, reporting the first
+// setting that conflicts with an earlier one by formatting its name with
+// invalid. Without any settings, it returns no YAML, rather than a null.
+func settingsYAML(settings []setting, invalid string) ([]byte, error) {
+	var root interface{}
+	for _, s := range settings {
+		var err error
+		root, err = setAt(root, s.path, parseSetValue(s.value))
+		if err != nil {
+			return nil, fmt.Errorf(invalid+": %v", s.name, err)
+		}
+	}
+	if root == nil {
+		return nil, nil
+	}
+	bs, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't marshal settings to YAML: %v", err))
+	}
+	return bs, nil
+}
+
+// parseSetValue interprets a value as a YAML scalar if it's a single plain
+// scalar without comments, and otherwise uses it literally, so that values
+// like "#ff0000" and "hi # there" aren't cut short at the comment.
+func parseSetValue(s string) interface{} {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	doc, err := decodeDocument([]byte(trimmed))
+	if err != nil || doc == nil || hasComments(doc) {
+		return s
+	}
+	node := doc.Content[0]
+	if node.Kind != yaml3.ScalarNode || node.Style != 0 || hasComments(node) {
+		return s
+	}
+	var val interface{}
+	if err := yaml.Unmarshal([]byte(trimmed), &val); err != nil || !merge.IsScalar(val) {
+		return s
+	}
+	return val
+}
+
+func hasComments(n *yaml3.Node) bool {
+	return n.HeadComment != "" || n.LineComment != "" || n.FootComment != ""
+}
+
+// setAt sets the value at a path, creating mappings and sequences as needed,
+// and returns the updated node.
+func setAt(node interface{}, path []string, val interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		if node != nil && !merge.IsScalar(node) {
+			return nil, errors.New("already has nested settings")
+		}
+		return val, nil
+	}
+	segment := path[0]
+	if segment == "" {
+		return nil, errors.New("empty path segment")
+	}
+	if idx, err := strconv.Atoi(segment); err == nil && idx >= 0 {
+		if node == nil {
+			node = []interface{}{}
+		}
+		seq, ok := node.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("can't index %q: already set as a %s", segment, describeNode(node))
+		}
+		if idx > len(seq) {
+			return nil, fmt.Errorf("index %d skips elements: set elements in order, starting from 0", idx)
+		}
+		if idx == len(seq) {
+			seq = append(seq, nil)
+		}
+		child, err := setAt(seq[idx], path[1:], val)
+		if err != nil {
+			return nil, err
+		}
+		seq[idx] = child
+		return seq, nil
+	}
+	if node == nil {
+		node = make(map[interface{}]interface{})
+	}
+	m, ok := node.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("can't set key %q: already set as a %s", segment, describeNode(node))
+	}
+	child, err := setAt(m[segment], path[1:], val)
+	if err != nil {
+		return nil, err
+	}
+	m[segment] = child
+	return m, nil
+}
+
+func describeNode(node interface{}) string {
+	switch {
+	case merge.IsMapping(node):
+		return "mapping"
+	case merge.IsSequence(node):
+		return "sequence"
+	default:
+		return "scalar"
+	}
+}
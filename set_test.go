@@ -0,0 +1,207 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSource(t *testing.T) {
+	const base = `
+server: {port: 80, name: web}
+servers:
+  - host: old-a
+  - host: old-b
+`
+	provider := func(t testing.TB, pairs ...string) *YAML {
+		p, err := NewYAML(Source(strings.NewReader(base)), SetSource(pairs...))
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("scalars", func(t *testing.T) {
+		p := provider(t,
+			"server.port=9090",
+			"feature.enabled=true",
+			"feature.ratio=0.5",
+			"feature.name=x",
+			"feature.list=[a, b]",
+			"feature.empty=",
+			"feature.nothing=null",
+			"feature.equation=a=b",
+			"feature.literal=$HOME",
+			"feature.color=#ff0000",
+			"feature.message=hi # there",
+			"feature.blank= ",
+			"feature.quoted='x'",
+			"feature.padded= 42 ",
+		)
+		assert.Equal(t, 9090, p.Get("server.port").Value(), "expected int")
+		assert.Equal(t, "web", p.Get("server.name").Value(), "expected lower-priority keys to remain")
+		assert.Equal(t, true, p.Get("feature.enabled").Value(), "expected bool")
+		assert.Equal(t, 0.5, p.Get("feature.ratio").Value(), "expected float")
+		assert.Equal(t, "x", p.Get("feature.name").Value(), "expected string")
+		assert.Equal(t, "[a, b]", p.Get("feature.list").Value(), "expected non-scalars to be strings")
+		assert.Equal(t, "", p.Get("feature.empty").Value(), "expected empty string")
+		assert.True(t, p.Get("feature.nothing").HasValue(), "expected explicit null")
+		assert.Nil(t, p.Get("feature.nothing").Value(), "expected explicit null")
+		assert.Equal(t, "a=b", p.Get("feature.equation").Value(), "expected only first = to split")
+		assert.Equal(t, "$HOME", p.Get("feature.literal").Value(), "expected values to be literal")
+		assert.Equal(t, "#ff0000", p.Get("feature.color").Value(), "expected comment-like values to be strings")
+		assert.Equal(t, "hi # there", p.Get("feature.message").Value(), "expected inline comments to be kept")
+		assert.Equal(t, " ", p.Get("feature.blank").Value(), "expected blank values to be strings")
+		assert.Equal(t, "'x'", p.Get("feature.quoted").Value(), "expected quoted values to be literal")
+		assert.Equal(t, 42, p.Get("feature.padded").Value(), "expected surrounding space to be ignored")
+	})
+
+	t.Run("repeated keys", func(t *testing.T) {
+		p := provider(t, "server.port=1", "server.port=2")
+		assert.Equal(t, 2, p.Get("server.port").Value(), "expected last setting to win")
+	})
+
+	t.Run("list indices", func(t *testing.T) {
+		p := provider(t, "servers.0.host=a", "servers.0.port=1", "servers.1.host=b", "tags.0=x", "tags.1=z")
+		assert.Equal(t, "a", p.Get("servers.0.host").Value(), "unexpected first host")
+		assert.Equal(t, 1, p.Get("servers.0.port").Value(), "unexpected first port")
+		assert.Equal(t, "b", p.Get("servers.1.host").Value(), "unexpected second host")
+		assert.Equal(t, []interface{}{"x", "z"}, p.Get("tags").Value(), "unexpected sequence")
+	})
+
+	t.Run("expansion", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("a: $FOO")),
+			SetSource("b=$$FOO"),
+			Expand(func(string) (string, bool) { return "expanded", true }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "expanded", p.Get("a").Value(), "expected other sources to be expanded")
+		assert.Equal(t, "$$FOO", p.Get("b").Value(), "expected settings to be literal")
+	})
+
+	t.Run("no pairs", func(t *testing.T) {
+		p := provider(t)
+		assert.Equal(t, 80, p.Get("server.port").Value(), "expected no settings to change nothing")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			pairs []string
+			err   string
+		}{
+			{[]string{"no-equals"}, "expected key=value"},
+			{[]string{"=value"}, "expected key=value"},
+			{[]string{"a..b=1"}, "empty path segment"},
+			{[]string{"a=1", "a.b=2"}, `can't set key "b": already set as a scalar`},
+			{[]string{"a.b=2", "a=1"}, "already has nested settings"},
+			{[]string{"a.b=1", "a.0=2"}, `can't index "0": already set as a mapping`},
+			{[]string{"a.0=1", "a.b=2"}, `can't set key "b": already set as a sequence`},
+			{[]string{"ports.80=http"}, "index 80 skips elements"},
+		}
+		for _, tt := range tests {
+			_, err := NewYAML(SetSource(tt.pairs...))
+			require.Error(t, err, "expected %v to fail", tt.pairs)
+			assert.Contains(t, err.Error(), tt.err, "unexpected error message for %v", tt.pairs)
+		}
+	})
+}
+
+func TestFlagSet(t *testing.T) {
+	newFlags := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		fs.Int("server.port", 80, "port")
+		fs.String("server.host", "flag-default", "host")
+		fs.Bool("debug", false, "debug")
+		fs.Duration("timeout", time.Second, "timeout")
+		fs.String("name", "", "name")
+		return fs
+	}
+	file := Source(strings.NewReader("server: {port: 8080, host: file-host}\ndebug: false\ntimeout: 5s"))
+
+	t.Run("set flags override files", func(t *testing.T) {
+		fs := newFlags()
+		require.NoError(t, fs.Parse([]string{"-server.port=9090", "-debug", "-timeout=1m", "-name=007"}), "couldn't parse flags")
+		p, err := NewYAML(file, FlagSet(fs))
+		require.NoError(t, err, "couldn't construct provider")
+
+		assert.Equal(t, 9090, p.Get("server.port").Value(), "expected set flag to override file")
+		assert.Equal(t, "file-host", p.Get("server.host").Value(), "expected unset flag not to override file")
+		assert.Equal(t, true, p.Get("debug").Value(), "expected Boolean flag to be inferred as a Boolean")
+		assert.Equal(t, "1m0s", p.Get("timeout").Value(), "expected flag values to be formatted by their flag.Value")
+		var cfg struct {
+			Server struct {
+				Port int
+				Host string
+			}
+			Debug   bool
+			Timeout time.Duration
+			Name    string
+		}
+		require.NoError(t, p.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, time.Minute, cfg.Timeout, "unexpected timeout")
+		assert.Equal(t, "7", cfg.Name, "expected values to be interpreted as YAML scalars")
+	})
+
+	t.Run("no flags set", func(t *testing.T) {
+		fs := newFlags()
+		require.NoError(t, fs.Parse(nil), "couldn't parse flags")
+		p, err := NewYAML(file, FlagSet(fs))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 8080, p.Get("server.port").Value(), "expected flag defaults not to override file")
+
+		empty, err := NewYAML(FlagSet(fs))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.False(t, empty.Get(Root).HasValue(), "expected no configuration without set flags")
+	})
+
+	t.Run("read at construction", func(t *testing.T) {
+		fs := newFlags()
+		opt := FlagSet(fs)
+		require.NoError(t, fs.Parse([]string{"-server.port=1"}), "couldn't parse flags")
+		p, err := NewYAML(file, opt)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 1, p.Get("server.port").Value(), "expected flags to be read when the provider is constructed")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewYAML(FlagSet(newFlags()))
+		require.Error(t, err, "expected unparsed flags to fail")
+		assert.Contains(t, err.Error(), "flags haven't been parsed", "unexpected error message")
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("a", "", "")
+		fs.String("a.b", "", "")
+		require.NoError(t, fs.Parse([]string{"-a=1", "-a.b=2"}), "couldn't parse flags")
+		_, err = NewYAML(FlagSet(fs))
+		require.Error(t, err, "expected conflicting flags to fail")
+		assert.Contains(t, err.Error(), `invalid flag "a.b"`, "unexpected error message")
+
+		_, err = NewYAML(FlagSet(nil))
+		assert.Error(t, err, "expected nil flag set to fail")
+	})
+}
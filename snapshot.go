@@ -0,0 +1,159 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package config
+
+import (
+	"bytes"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+)
+
+// A Snapshot is a plain, serializable copy of a provider's configuration. It
+// holds the merged and expanded configuration in canonical form, along with
+// the settings that affect reading it, so it can be stored (for example, as
+// JSON) and later loaded without re-reading and re-merging the original
+// sources.
+//
+// Options that hold functions or other values that can't be serialized
+// aren't recorded, so providers loaded from a snapshot behave as if they
+// were constructed without them: TrimStrings and TrimStringsFunc,
+// NormalizeKeys, MergeFunc, WithObserver, TypeRegistry, and OwnedSections.
+// Loaded providers also aren't sealed, even if the snapshot was taken from a
+// sealed provider (see Seal).
+type Snapshot struct {
+	Name string // the provider's name
+	// Contents holds canonical YAML, or is empty if the provider had no
+	// configuration. If the provider was constructed with PreserveNumbers,
+	// numbers it preserved are written exactly, as in the original sources.
+	Contents []byte
+
+	Strict        bool              // whether populating rejects unknown keys
+	CoerceScalars bool              // see the CoerceScalars option
+	KeyMatch      KeyMatchMode      // see the KeyMatch option
+	StrictPaths   []string          // see the StrictPaths option
+	ReplaceMaps   []string          // see the ReplaceMaps option
+	Deprecated    map[string]string // see the DeprecateKeys option
+	StructTag     string            // see the StructTag option
+	MaxDepth      int               // see the MaxDepth option; zero uses the default
+	MaxSeqLen     int               // see the MaxSequenceLen option; zero is unlimited
+	DefaultTags   bool              // see the UseDefaultTags option
+	RejectNulls   bool              // see the RejectNullStructs option
+	StrictScalars bool              // see the StrictScalars option
+	EnvTags       bool              // see the UseEnvTags option
+	Numbers       bool              // see the PreserveNumbers option
+}
+
+// Snapshot captures the provider's configuration. See Snapshot.Load.
+func (y *YAML) Snapshot() Snapshot {
+	s := Snapshot{
+		Name:          y.name,
+		Strict:        y.strict,
+		CoerceScalars: y.coerce,
+		KeyMatch:      y.keyMatch,
+		StructTag:     y.tag,
+		MaxDepth:      y.maxDepth,
+		MaxSeqLen:     y.maxSeqLen,
+		DefaultTags:   y.defaultTags,
+		RejectNulls:   y.rejectNulls,
+		StrictScalars: y.strictScalar,
+		EnvTags:       y.envTags,
+		Numbers:       y.preserve,
+	}
+	if len(y.deprecated) > 0 {
+		s.Deprecated = make(map[string]string, len(y.deprecated))
+		for k, v := range y.deprecated {
+			s.Deprecated[k] = v
+		}
+	}
+	for _, p := range y.strictPaths {
+		s.StrictPaths = append(s.StrictPaths, strings.Join(p, _separator))
+	}
+	for _, p := range y.replaceMaps {
+		s.ReplaceMaps = append(s.ReplaceMaps, strings.Join(p, _separator))
+	}
+	if !y.empty {
+		bs, err := y.CanonicalBytes()
+		if err != nil {
+			panic(unreachable.Wrap(err).Error())
+		}
+		if y.numbers != nil {
+			restored, err := y.restoreNumbers(bytes.NewBuffer(bs), nil)
+			if err != nil {
+				panic(unreachable.Wrap(err).Error())
+			}
+			bs = restored.Bytes()
+		}
+		s.Contents = bs
+	}
+	return s
+}
+
+// Load re-creates a provider from a snapshot. Since the snapshot's contents
+// were already expanded, variables aren't expanded again, even if the
+// environment has changed: a value that was written as $$FOO in the original
+// sources remains the literal $FOO. Consequently, WithDefault doesn't expand
+// variables in defaults applied to the loaded provider.
+func (s Snapshot) Load() (*YAML, error) {
+	opts := []YAMLOption{Name(s.Name), RawSource(bytes.NewReader(s.Contents))}
+	if !s.Strict {
+		opts = append(opts, Permissive())
+	}
+	if s.CoerceScalars {
+		opts = append(opts, CoerceScalars())
+	}
+	if s.KeyMatch != PreferString {
+		opts = append(opts, KeyMatch(s.KeyMatch))
+	}
+	if s.StructTag != "" {
+		opts = append(opts, StructTag(s.StructTag))
+	}
+	if s.DefaultTags {
+		opts = append(opts, UseDefaultTags())
+	}
+	if s.RejectNulls {
+		opts = append(opts, RejectNullStructs())
+	}
+	if s.StrictScalars {
+		opts = append(opts, StrictScalars())
+	}
+	if s.EnvTags {
+		opts = append(opts, UseEnvTags())
+	}
+	if s.Numbers {
+		opts = append(opts, PreserveNumbers())
+	}
+	if s.MaxDepth > 0 {
+		opts = append(opts, MaxDepth(s.MaxDepth))
+	}
+	if s.MaxSeqLen > 0 {
+		opts = append(opts, MaxSequenceLen(s.MaxSeqLen))
+	}
+	if len(s.StrictPaths) > 0 {
+		opts = append(opts, StrictPaths(s.StrictPaths...))
+	}
+	if len(s.ReplaceMaps) > 0 {
+		opts = append(opts, ReplaceMaps(s.ReplaceMaps...))
+	}
+	if len(s.Deprecated) > 0 {
+		opts = append(opts, DeprecateKeys(s.Deprecated))
+	}
+	return NewYAML(opts...)
+}
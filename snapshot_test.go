@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	env := map[string]string{"REGION": "us-east"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+	orig, err := NewYAML(
+		Name("service"),
+		Source(strings.NewReader("region: $REGION\nprice: $$5\nsecurity: {tls: true}")),
+		Source(strings.NewReader("port: '8080'\nlabels: {a: 1}")),
+		Expand(lookup),
+		CoerceScalars(),
+		StrictPaths("security"),
+		ReplaceMaps("labels"),
+	)
+	require.NoError(t, err, "couldn't construct provider")
+
+	// Round-trip through JSON, as if stored on disk.
+	bs, err := json.Marshal(orig.Snapshot())
+	require.NoError(t, err, "couldn't marshal snapshot")
+	var snap Snapshot
+	require.NoError(t, json.Unmarshal(bs, &snap), "couldn't unmarshal snapshot")
+
+	// A different process might have a different environment.
+	env["REGION"] = "eu-west"
+	env["5"] = "oops"
+
+	loaded, err := snap.Load()
+	require.NoError(t, err, "couldn't load snapshot")
+	assert.Equal(t, "service", loaded.Name(), "unexpected name")
+	assert.Equal(t, orig.Get(Root).Value(), loaded.Get(Root).Value(), "unexpected contents")
+	assert.Equal(t, "us-east", loaded.Get("region").Value(), "snapshot shouldn't be re-expanded")
+	assert.Equal(t, "$5", loaded.Get("price").Value(), "literal dollar signs shouldn't be expanded")
+
+	var cfg struct {
+		Port     int
+		Region   string
+		Price    string
+		Labels   map[string]int
+		Security struct{ TLS bool }
+	}
+	require.NoError(t, loaded.Get(Root).Populate(&cfg), "expected CoerceScalars to survive snapshot")
+	assert.Equal(t, 8080, cfg.Port, "unexpected coerced port")
+
+	var insecure struct{ Security struct{} }
+	assert.Error(t, loaded.Get(Root).Populate(&insecure), "expected StrictPaths to survive snapshot")
+
+	withDefault, err := loaded.Get(Root).WithDefault(map[string]interface{}{
+		"labels": map[string]int{"b": 2},
+	})
+	require.NoError(t, err, "couldn't apply defaults")
+	assert.Equal(t, map[interface{}]interface{}{"a": 1}, withDefault.Get("labels").Value(), "expected ReplaceMaps to survive snapshot")
+
+	t.Run("empty", func(t *testing.T) {
+		empty, err := NewYAML(Source(strings.NewReader("")), Permissive())
+		require.NoError(t, err, "couldn't construct provider")
+		snap := empty.Snapshot()
+		assert.Empty(t, snap.Contents, "expected no contents")
+		assert.False(t, snap.Strict, "expected permissive snapshot")
+
+		loaded, err := snap.Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		assert.False(t, loaded.Get(Root).HasValue(), "expected empty provider")
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		deep := strings.Repeat("[", _defaultMaxDepth+1) + "1" + strings.Repeat("]", _defaultMaxDepth+1)
+		p, err := NewYAML(Source(strings.NewReader(deep)), MaxDepth(_defaultMaxDepth+1))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.Snapshot().Load()
+		assert.NoError(t, err, "expected MaxDepth to survive snapshot")
+	})
+
+	t.Run("max sequence length", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("hosts: [a, b]")), MaxSequenceLen(2))
+		require.NoError(t, err, "couldn't construct provider")
+		loaded, err := p.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		_, err = loaded.Get(Root).WithDefault(map[string]interface{}{"peers": []int{1, 2, 3}})
+		assert.Error(t, err, "expected MaxSequenceLen to survive snapshot")
+	})
+
+	t.Run("default tags", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("{}")), UseDefaultTags())
+		require.NoError(t, err, "couldn't construct provider")
+		loaded, err := p.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		var cfg struct {
+			Port int `default:"80"`
+		}
+		require.NoError(t, loaded.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, 80, cfg.Port, "expected UseDefaultTags to survive snapshot")
+	})
+
+	t.Run("reject null structs", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("server: null")), RejectNullStructs())
+		require.NoError(t, err, "couldn't construct provider")
+		loaded, err := p.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		var cfg struct{ Server struct{ Port int } }
+		assert.Error(t, loaded.Get(Root).Populate(&cfg), "expected RejectNullStructs to survive snapshot")
+	})
+
+	t.Run("strict scalars", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("country: no")), StrictScalars())
+		require.NoError(t, err, "couldn't construct provider")
+		loaded, err := p.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		var cfg struct{ Country string }
+		assert.Error(t, loaded.Get(Root).Populate(&cfg), "expected StrictScalars to survive snapshot")
+	})
+
+	t.Run("preserved numbers", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("limit: 123456789012345678901234567890\nratio: 0.5")), PreserveNumbers())
+		require.NoError(t, err, "couldn't construct provider")
+		bs, err := json.Marshal(p.Snapshot())
+		require.NoError(t, err, "couldn't marshal snapshot")
+		var snap Snapshot
+		require.NoError(t, json.Unmarshal(bs, &snap), "couldn't unmarshal snapshot")
+		loaded, err := snap.Load()
+		require.NoError(t, err, "couldn't load snapshot")
+
+		n, err := loaded.Get("limit").Number()
+		require.NoError(t, err, "couldn't get number")
+		assert.Equal(t, "123456789012345678901234567890", n.String(), "expected exact number to survive snapshot")
+		assert.Equal(t, 0.5, loaded.Get("ratio").Value(), "expected other numbers to be unchanged")
+	})
+
+	t.Run("env tags", func(t *testing.T) {
+		require.NoError(t, os.Setenv("CONFIG_TEST_SNAPSHOT_PORT", "9090"), "couldn't set environment variable")
+		defer os.Unsetenv("CONFIG_TEST_SNAPSHOT_PORT")
+		p, err := NewYAML(Source(strings.NewReader("port: 80")), UseEnvTags())
+		require.NoError(t, err, "couldn't construct provider")
+		loaded, err := p.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		var cfg struct {
+			Port int `env:"CONFIG_TEST_SNAPSHOT_PORT"`
+		}
+		require.NoError(t, loaded.Get(Root).Populate(&cfg), "couldn't populate")
+		assert.Equal(t, 9090, cfg.Port, "expected UseEnvTags to survive snapshot")
+	})
+
+	t.Run("deprecated keys", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("server: {addr: 'localhost:80'}")),
+			DeprecateKeys(map[string]string{"server.addr": "use server.host instead"}),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		bs, err := json.Marshal(p.Snapshot())
+		require.NoError(t, err, "couldn't marshal snapshot")
+		var snap Snapshot
+		require.NoError(t, json.Unmarshal(bs, &snap), "couldn't unmarshal snapshot")
+		loaded, err := snap.Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		assert.Equal(t, p.Warnings(), loaded.Warnings(), "expected DeprecateKeys to survive snapshot")
+		assert.NotEmpty(t, loaded.Warnings(), "expected a deprecation warning")
+	})
+
+	t.Run("null", func(t *testing.T) {
+		null, err := NewYAML(Source(strings.NewReader("~")))
+		require.NoError(t, err, "couldn't construct provider")
+		loaded, err := null.Snapshot().Load()
+		require.NoError(t, err, "couldn't load snapshot")
+		assert.True(t, loaded.Get(Root).HasValue(), "expected explicit null to survive snapshot")
+	})
+}
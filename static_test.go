@@ -44,3 +44,31 @@ func TestStaticErrors(t *testing.T) {
 		require.Error(t, err, "expected serializing value to fail")
 	})
 }
+
+func TestStaticNamed(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		p, err := NewYAML(
+			Static(map[string]string{"foo": "bar", "baz": "quux"}),
+			StaticNamed("test-overrides", map[string]interface{}{"foo": "override"}),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "test-overrides", p.Name(), "unexpected provider name")
+		assert.Equal(t, "test-overrides", p.Get("foo").Source(), "unexpected value source")
+		assert.Equal(t, "override", p.Get("foo").Value(), "expected named source to take priority")
+		assert.Equal(t, "quux", p.Get("baz").Value(), "expected sources to merge")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		var err error
+		assert.NotPanics(t, func() {
+			_, err = NewYAML(StaticNamed("test-overrides", map[string]interface{}{"ch": make(chan int)}))
+		})
+		require.Error(t, err, "expected serializing value to fail")
+		assert.Contains(t, err.Error(), "test-overrides", "expected error to name the source")
+	})
+
+	t.Run("serialization fails", func(t *testing.T) {
+		_, err := NewYAML(StaticNamed("test-overrides", noYAML{}))
+		require.Error(t, err, "expected serializing value to fail")
+	})
+}
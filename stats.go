@@ -0,0 +1,93 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import "time"
+
+// CollectStats makes a provider record how large its sources are and how
+// long each stage of its construction takes, for profiling slow startups.
+// The results are available from YAML.Stats. Without CollectStats, the
+// provider doesn't read the clock at all.
+func CollectStats() YAMLOption {
+	return optionFunc(func(c *config) {
+		c.collectStats = true
+	})
+}
+
+// LoadStats describes the construction of a YAML provider; see
+// CollectStats.
+type LoadStats struct {
+	// Sources lists the provider's sources in priority order, from lowest to
+	// highest, after any includes have been resolved.
+	Sources []SourceStats
+
+	// Merge is the time spent merging the sources, Expand is the time spent
+	// expanding variables (including the protection of raw and verbatim
+	// values), and Decode is the time spent decoding the merged
+	// configuration.
+	Merge  time.Duration
+	Expand time.Duration
+	Decode time.Duration
+}
+
+// SourceStats describes a single source of a YAML provider.
+type SourceStats struct {
+	Name  string // as described in YAML.Explain
+	Bytes int
+}
+
+// Stats returns the statistics recorded while the provider was constructed.
+// If the provider wasn't constructed with CollectStats, they're all zero.
+// Providers created by WithDefault and ApplyMergePatch record their own
+// statistics.
+func (y *YAML) Stats() LoadStats {
+	if y.stats == nil {
+		return LoadStats{}
+	}
+	stats := *y.stats
+	stats.Sources = append([]SourceStats(nil), y.stats.Sources...)
+	return stats
+}
+
+// A stopwatch times the stages of provider construction if it's enabled,
+// and does nothing otherwise.
+type stopwatch struct {
+	enabled bool
+	start   time.Time
+}
+
+// restart starts timing a new stage.
+func (s *stopwatch) restart() {
+	if s.enabled {
+		s.start = time.Now()
+	}
+}
+
+// lap returns the duration of the current stage and starts timing the next.
+func (s *stopwatch) lap() time.Duration {
+	if !s.enabled {
+		return 0
+	}
+	now := time.Now()
+	d := now.Sub(s.start)
+	s.start = now
+	return d
+}
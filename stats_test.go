@@ -0,0 +1,69 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectStats(t *testing.T) {
+	base := "server:\n  host: localhost\n  port: ${PORT:80}\nname: svc\n"
+	override := "server: {port: 8080}\n"
+	newProvider := func(t testing.TB, opts ...YAMLOption) *YAML {
+		p, err := NewYAML(append([]YAMLOption{
+			Source(strings.NewReader(base)),
+			Source(strings.NewReader(override)),
+			Expand(func(string) (string, bool) { return "", false }),
+		}, opts...)...)
+		require.NoError(t, err, "couldn't construct provider")
+		return p
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		p := newProvider(t, CollectStats())
+		stats := p.Stats()
+		require.Len(t, stats.Sources, 2, "expected stats for each source")
+		assert.Equal(t, len(base), stats.Sources[0].Bytes, "unexpected size of first source")
+		assert.Equal(t, len(override), stats.Sources[1].Bytes, "unexpected size of second source")
+		assert.NotEmpty(t, stats.Sources[0].Name, "expected sources to be named")
+		assert.True(t, stats.Merge > 0, "expected merge duration to be recorded")
+		assert.True(t, stats.Expand > 0, "expected expand duration to be recorded")
+		assert.True(t, stats.Decode > 0, "expected decode duration to be recorded")
+
+		stats.Sources[0].Bytes = 0
+		assert.Equal(t, len(base), p.Stats().Sources[0].Bytes, "expected Stats to return a copy")
+	})
+
+	t.Run("derived providers", func(t *testing.T) {
+		p := newProvider(t, CollectStats())
+		v, err := p.Get("timeout").WithDefault("1s")
+		require.NoError(t, err, "couldn't apply default")
+		assert.Len(t, v.provider.Stats().Sources, 3, "expected the default to be listed as a source")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Equal(t, LoadStats{}, newProvider(t).Stats(), "expected no stats without CollectStats")
+	})
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"go.uber.org/config/internal/merge"
+	"go.uber.org/multierr"
+	yaml "gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// A TagResolver converts the value of a scalar with a custom YAML tag into
+// configuration. See RegisterTag.
+type TagResolver = func(value string) (interface{}, error)
+
+// RegisterTag resolves scalars with a custom YAML tag, such as !env or !file,
+// when the provider is constructed. For example, with
+//   RegisterTag("!env", EnvTag(os.LookupEnv))
+// the source
+//   password: !env DB_PASSWORD
+// sets password to the value of the DB_PASSWORD environment variable. The
+// resolver receives the scalar's text, and its result replaces the tagged
+// scalar; it may return any value that can be marshaled to YAML, including
+// mappings and sequences. If a resolver fails, NewYAML returns an error that
+// names the tag, the key, and the source.
+//
+// Tags are resolved in each source before sources are merged, since merging
+// discards tags. (Values produced by resolvers are still subject to variable
+// expansion, unless the source is raw.) Tags must begin with a single !, and
+// only one resolver may be registered for each tag. Scalars with tags that
+// aren't registered are decoded as before, as if they were untagged, and
+// registered tags on mappings and sequences are ignored.
+func RegisterTag(tag string, resolve TagResolver) YAMLOption {
+	if !strings.HasPrefix(tag, "!") || strings.HasPrefix(tag, "!!") || len(tag) == 1 {
+		return failed(fmt.Errorf("invalid tag %q: custom tags must begin with a single !", tag))
+	}
+	if resolve == nil {
+		return failed(fmt.Errorf("can't register tag %q: resolver is nil", tag))
+	}
+	return optionFunc(func(c *config) {
+		if _, ok := c.tags[tag]; ok {
+			c.err = multierr.Append(c.err, fmt.Errorf("tag %q is already registered", tag))
+			return
+		}
+		if c.tags == nil {
+			c.tags = make(map[string]TagResolver)
+		}
+		c.tags[tag] = resolve
+	})
+}
+
+// EnvTag returns a TagResolver that treats each tagged scalar as the name of
+// a variable and resolves it using the supplied lookup function, typically
+// os.LookupEnv. Unlike Expand, it has no syntax for defaults: variables that
+// aren't found are errors. The resolved value is always a string.
+func EnvTag(lookup LookupFunc) TagResolver {
+	return func(name string) (interface{}, error) {
+		val, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("variable %q isn't set", name)
+		}
+		return val, nil
+	}
+}
+
+// FileTag is a TagResolver that treats each tagged scalar as a file name and
+// resolves it to the file's contents, as a string. It's useful for secrets
+// mounted as files. Relative names are interpreted relative to the process's
+// working directory.
+func FileTag(name string) (interface{}, error) {
+	bs, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return string(bs), nil
+}
+
+// resolveTags applies the registered tag resolvers to each source.
+func resolveTags(srcs []source, tags map[string]TagResolver) ([]source, error) {
+	for i := range srcs {
+		if !mentionsTag(srcs[i].bytes, tags) {
+			continue
+		}
+		bs, err := resolveSourceTags(srcs[i].bytes, tags)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't resolve tags in %s: %v", srcs[i].describe(i), err)
+		}
+		srcs[i].bytes = bs
+	}
+	return srcs, nil
+}
+
+// mentionsTag reports whether a source might use any of the tags, which
+// skips parsing sources twice in the common case.
+func mentionsTag(bs []byte, tags map[string]TagResolver) bool {
+	for tag := range tags {
+		if bytes.Contains(bs, []byte(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSourceTags resolves the tagged scalars in a single source.
+// gopkg.in/yaml.v2 discards tags, so gopkg.in/yaml.v3 locates them in the
+// source's node tree, and the resolved values are patched into the
+// source as gopkg.in/yaml.v2 decodes it.
+func resolveSourceTags(bs []byte, tags map[string]TagResolver) ([]byte, error) {
+	var doc yaml3.Node
+	if err := yaml3.NewDecoder(bytes.NewReader(bs)).Decode(&doc); err == io.EOF {
+		return bs, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var contents interface{}
+	if err := yaml.Unmarshal(bs, &contents); err != nil {
+		return nil, err
+	}
+	r := &tagResolver{tags: tags}
+	if len(doc.Content) == 0 {
+		return bs, nil
+	}
+	contents, err := r.resolve(doc.Content[0], contents, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !r.changed {
+		return bs, nil
+	}
+	out, err := yaml.Marshal(contents)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal resolved tags to YAML: %v", err)
+	}
+	return out, nil
+}
+
+type tagResolver struct {
+	tags    map[string]TagResolver
+	changed bool
+}
+
+// resolve walks a gopkg.in/yaml.v3 node alongside the value gopkg.in/yaml.v2
+// decoded from it, returning the value with tagged scalars resolved.
+func (r *tagResolver) resolve(n *yaml3.Node, val interface{}, path []string) (interface{}, error) {
+	switch n.Kind {
+	case yaml3.AliasNode:
+		// Aliases are decoded as copies of their anchors.
+		return r.resolve(n.Alias, val, path)
+	case yaml3.ScalarNode:
+		f, ok := r.tags[n.Tag]
+		if !ok {
+			return val, nil
+		}
+		resolved, err := f(n.Value)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't resolve %s %q at key %q: %v", n.Tag, n.Value, strings.Join(path, _separator), err)
+		}
+		r.changed = true
+		return resolved, nil
+	case yaml3.SequenceNode:
+		seq, ok := val.([]interface{})
+		if !ok || len(seq) != len(n.Content) {
+			return val, nil
+		}
+		for i, child := range n.Content {
+			resolved, err := r.resolve(child, seq[i], extend(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			seq[i] = resolved
+		}
+		return seq, nil
+	case yaml3.MappingNode:
+		m, ok := val.(map[interface{}]interface{})
+		if !ok {
+			return val, nil
+		}
+		return m, r.resolveMapping(n, m, path, nil)
+	}
+	return val, nil
+}
+
+// resolveMapping resolves the values of a mapping node, skipping keys that
+// are set by a higher-priority mapping. Keys merged in with << are resolved
+// unless the mapping sets them explicitly.
+func (r *tagResolver) resolveMapping(n *yaml3.Node, m map[interface{}]interface{}, path []string, shadowed map[interface{}]bool) error {
+	own := make(map[interface{}]bool, len(shadowed)+len(n.Content)/2)
+	for k := range shadowed {
+		own[k] = true
+	}
+	var merges []*yaml3.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keyNode, valNode := n.Content[i], n.Content[i+1]
+		if keyNode.Tag == "!!merge" {
+			merges = append(merges, mergedMappings(valNode)...)
+			continue
+		}
+		key, ok := decodeKey(keyNode)
+		if !ok {
+			continue
+		}
+		own[key] = true
+		if _, ok := m[key]; !ok || shadowed[key] {
+			continue
+		}
+		resolved, err := r.resolve(valNode, m[key], extend(path, fmt.Sprint(key)))
+		if err != nil {
+			return err
+		}
+		m[key] = resolved
+	}
+	for _, merged := range merges {
+		if err := r.resolveMapping(merged, m, path, own); err != nil {
+			return err
+		}
+		for i := 0; i+1 < len(merged.Content); i += 2 {
+			if key, ok := decodeKey(merged.Content[i]); ok {
+				own[key] = true
+			}
+		}
+	}
+	return nil
+}
+
+// mergedMappings returns the mappings referenced by the value of a << key,
+// in order of decreasing priority.
+func mergedMappings(n *yaml3.Node) []*yaml3.Node {
+	if n.Kind == yaml3.AliasNode {
+		n = n.Alias
+	}
+	switch n.Kind {
+	case yaml3.MappingNode:
+		return []*yaml3.Node{n}
+	case yaml3.SequenceNode:
+		var ms []*yaml3.Node
+		for _, child := range n.Content {
+			ms = append(ms, mergedMappings(child)...)
+		}
+		return ms
+	}
+	return nil
+}
+
+// decodeKey decodes a scalar mapping key the way gopkg.in/yaml.v2 does.
+func decodeKey(n *yaml3.Node) (interface{}, bool) {
+	if n.Kind == yaml3.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml3.ScalarNode {
+		return nil, false
+	}
+	quoted := yaml3.SingleQuotedStyle | yaml3.DoubleQuotedStyle | yaml3.LiteralStyle | yaml3.FoldedStyle
+	if n.Style&quoted != 0 || (n.Style&yaml3.TaggedStyle != 0 && n.Tag == "!!str") {
+		return n.Value, true
+	}
+	var key interface{}
+	if err := yaml.Unmarshal([]byte(n.Value), &key); err != nil {
+		return nil, false
+	}
+	return key, merge.IsScalar(key)
+}
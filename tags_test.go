@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterTag(t *testing.T) {
+	upper := RegisterTag("!upper", func(v string) (interface{}, error) {
+		return strings.ToUpper(v), nil
+	})
+	split := RegisterTag("!split", func(v string) (interface{}, error) {
+		return strings.Split(v, ","), nil
+	})
+
+	t.Run("custom resolvers", func(t *testing.T) {
+		src := `
+name: !upper api
+plain: hello
+hosts: !split a,b
+nested: {list: [x, !upper y], 1: !upper one}
+other: !unregistered kept
+`
+		p, err := NewYAML(Source(strings.NewReader(src)), upper, split)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "API", p.Get("name").Value(), "expected tagged scalar to be resolved")
+		assert.Equal(t, "hello", p.Get("plain").Value(), "expected untagged scalar to be unchanged")
+		assert.Equal(t, []interface{}{"a", "b"}, p.Get("hosts").Value(), "expected resolver to produce a sequence")
+		assert.Equal(t, "Y", p.Get("nested.list.1").Value(), "expected tagged sequence element to be resolved")
+		assert.Equal(t, "ONE", p.Get("nested.1").Value(), "expected value under integer key to be resolved")
+		assert.Equal(t, "kept", p.Get("other").Value(), "expected unregistered tag to be ignored")
+	})
+
+	t.Run("anchors and merge keys", func(t *testing.T) {
+		src := `
+base: &base {name: !upper base, port: 80}
+copy: *base
+derived:
+  <<: *base
+  port: !upper override
+`
+		p, err := NewYAML(Source(strings.NewReader(src)), upper)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "BASE", p.Get("copy.name").Value(), "expected alias to be resolved")
+		assert.Equal(t, "BASE", p.Get("derived.name").Value(), "expected merged key to be resolved")
+		assert.Equal(t, "OVERRIDE", p.Get("derived.port").Value(), "expected explicit key to win over merged key")
+	})
+
+	t.Run("merged sources", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("a: !upper low\nb: !upper low")),
+			Source(strings.NewReader("b: high")),
+			upper,
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "LOW", p.Get("a").Value(), "unexpected value from lower-priority source")
+		assert.Equal(t, "high", p.Get("b").Value(), "expected higher-priority source to win")
+	})
+
+	t.Run("resolver error", func(t *testing.T) {
+		fail := RegisterTag("!fail", func(v string) (interface{}, error) {
+			return nil, errors.New("no such secret")
+		})
+		_, err := NewYAML(StaticNamed("secrets.yaml", nil), Source(strings.NewReader("db: {password: !fail pw}")), fail)
+		require.Error(t, err, "expected resolver error to abort construction")
+		assert.Contains(t, err.Error(), `couldn't resolve !fail "pw" at key "db.password": no such secret`, "expected error to name the tag and key")
+		assert.Contains(t, err.Error(), "source at index 1", "expected error to name the source")
+	})
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		_, err := NewYAML(RegisterTag("env", EnvTag(nil)))
+		assert.Error(t, err, "expected error for tag without !")
+		_, err = NewYAML(RegisterTag("!!str", EnvTag(nil)))
+		assert.Error(t, err, "expected error for standard tag")
+		_, err = NewYAML(RegisterTag("!x", nil))
+		assert.Error(t, err, "expected error for nil resolver")
+		_, err = NewYAML(upper, upper)
+		assert.Error(t, err, "expected error for duplicate tag")
+	})
+}
+
+func TestBuiltinTags(t *testing.T) {
+	dir := writeDir(t, map[string]string{"secret": "s3cr3t"})
+	secret := filepath.Join(dir, "secret")
+	lookup := func(key string) (string, bool) {
+		if key == "USER" {
+			return "alice", true
+		}
+		return "", false
+	}
+
+	p, err := NewYAML(
+		Source(strings.NewReader("user: !env USER\nsecret: !file "+secret)),
+		RegisterTag("!env", EnvTag(lookup)),
+		RegisterTag("!file", FileTag),
+	)
+	require.NoError(t, err, "couldn't construct provider")
+	assert.Equal(t, "alice", p.Get("user").Value(), "unexpected !env value")
+	assert.Equal(t, "s3cr3t", p.Get("secret").Value(), "unexpected !file value")
+
+	_, err = NewYAML(Source(strings.NewReader("user: !env MISSING")), RegisterTag("!env", EnvTag(lookup)))
+	require.Error(t, err, "expected unset variable to fail")
+	assert.Contains(t, err.Error(), `variable "MISSING" isn't set`, "unexpected error message")
+
+	_, err = NewYAML(Source(strings.NewReader("secret: !file "+filepath.Join(dir, "missing"))), RegisterTag("!file", FileTag))
+	assert.Error(t, err, "expected missing file to fail")
+}
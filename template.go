@@ -0,0 +1,198 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+const _commentTagName = "comment"
+
+// Template generates an annotated YAML skeleton for a struct, which is useful
+// for shipping documented example configuration. Template is the inverse of
+// Populate: it emits a key for every field that Populate would set, using the
+// provider's struct tag (see StructTag), with the field's current value. For
+// zero-valued fields with a default struct tag (see UseDefaultTags), it emits
+// the default instead. Fields with a comment struct tag are preceded by the
+// tag's contents as a YAML comment, so
+//   type Server struct {
+//     Port    int           `yaml:"port" comment:"Port to listen on."`
+//     Timeout time.Duration `yaml:"timeout" default:"5s"`
+//   }
+// produces
+//   # Port to listen on.
+//   port: 0
+//   timeout: 5s
+//
+// Nested structs, including nil pointers to structs, are expanded field by
+// field, and the fields of untagged embedded structs are promoted. Empty
+// sequences are shown with a single example element, so their element
+// structure is documented too. Recursive types are expanded once: within a
+// struct, nil pointers to it and empty sequences of it are emitted as null
+// and []. Maps, interfaces, and types that implement
+// their own unmarshaling are emitted as they marshal. The target must be a
+// struct or a pointer to one, and isn't modified.
+func (y *YAML) Template(target interface{}) ([]byte, error) {
+	v := reflect.ValueOf(target)
+	if !v.IsValid() || !isStruct(v.Type()) {
+		return nil, fmt.Errorf("can't generate a template for %T: target must be a struct or a pointer to a struct", target)
+	}
+	node, err := y.templateNode(v.Type(), v, nil)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml3.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, fmt.Errorf("couldn't marshal template: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("couldn't marshal template: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// templateNode builds the template for a value of type t. The value may be
+// invalid, in which case the zero value is used. Expanding lists the struct
+// types being expanded; nil pointers and empty sequences of those types are
+// left as they are, rather than expanded into examples forever.
+func (y *YAML) templateNode(t reflect.Type, v reflect.Value, expanding []reflect.Type) (*yaml3.Node, error) {
+	if !v.IsValid() {
+		v = reflect.Zero(t)
+	}
+	if isOpaque(t) || t == _durationType {
+		return templateLeaf(v)
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if inner := derefType(t); inner.Kind() != reflect.Struct || containsType(expanding, inner) {
+				return templateLeaf(v)
+			}
+			return y.templateNode(t.Elem(), reflect.Value{}, expanding)
+		}
+		return y.templateNode(t.Elem(), v.Elem(), expanding)
+	case reflect.Struct:
+		node := &yaml3.Node{Kind: yaml3.MappingNode}
+		if err := y.templateFields(node, t, v, []reflect.Type{t}, append(expanding, t)); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case reflect.Slice, reflect.Array:
+		node := &yaml3.Node{Kind: yaml3.SequenceNode}
+		if v.Len() == 0 {
+			if containsType(expanding, derefType(t.Elem())) {
+				return node, nil
+			}
+			elem, err := y.templateNode(t.Elem(), reflect.Value{}, expanding)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, elem)
+			return node, nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := y.templateNode(t.Elem(), v.Index(i), expanding)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, elem)
+		}
+		return node, nil
+	}
+	return templateLeaf(v)
+}
+
+// templateFields adds keys for the fields of a struct to a mapping node.
+func (y *YAML) templateFields(node *yaml3.Node, t reflect.Type, v reflect.Value, promoting, expanding []reflect.Type) error {
+	fields, err := taggedFields(t, y.tag)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.unexported {
+			continue
+		}
+		fv := v.FieldByIndex(f.index)
+		if f.embedded {
+			inner := derefType(f.typ)
+			if containsType(promoting, inner) {
+				continue
+			}
+			if fv.Kind() == reflect.Ptr {
+				fv = fv.Elem()
+			}
+			if !fv.IsValid() {
+				fv = reflect.Zero(inner)
+			}
+			if err := y.templateFields(node, inner, fv, append(promoting, inner), append(expanding, inner)); err != nil {
+				return err
+			}
+			continue
+		}
+		if def, ok := f.tag.Lookup(_defaultTagName); ok && fv.IsZero() {
+			parsed := reflect.New(f.typ)
+			if err := yaml.Unmarshal([]byte(def), parsed.Interface()); err != nil {
+				return fmt.Errorf("invalid default %q for field %s of %v: %v", def, f.name, t, err)
+			}
+			fv = parsed.Elem()
+		}
+		val, err := y.templateNode(f.typ, fv, expanding)
+		if err != nil {
+			return err
+		}
+		key := &yaml3.Node{Kind: yaml3.ScalarNode, Value: f.key}
+		if comment, ok := f.tag.Lookup(_commentTagName); ok && comment != "" {
+			key.HeadComment = "# " + strings.ReplaceAll(comment, "\n", "\n# ")
+		}
+		node.Content = append(node.Content, key, val)
+	}
+	return nil
+}
+
+// templateLeaf marshals a value the way gopkg.in/yaml.v2 would, so that the
+// template reads back as the same value.
+func templateLeaf(v reflect.Value) (*yaml3.Node, error) {
+	val := v.Interface()
+	if v.Type() == _durationType {
+		val = v.Interface().(time.Duration).String()
+	}
+	bs, err := yaml.Marshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal %v to YAML: %v", v.Type(), err)
+	}
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(bs, &doc); err != nil {
+		return nil, fmt.Errorf("couldn't marshal %v to YAML: %v", v.Type(), err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml3.Node{Kind: yaml3.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return doc.Content[0], nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type templateTLS struct {
+	Enabled bool   `yaml:"enabled" comment:"Whether to serve TLS."`
+	Cert    string `yaml:"cert" comment:"Path to the PEM-encoded certificate."`
+}
+
+type templateBackend struct {
+	Host   string `yaml:"host"`
+	Weight int    `yaml:"weight" default:"1" comment:"Relative share of traffic."`
+}
+
+type TemplateCommon struct {
+	Region string `yaml:"region" comment:"Deployment region."`
+}
+
+type templateConfig struct {
+	TemplateCommon
+	Name     string            `yaml:"name" comment:"Service name, used in logs\nand metrics."`
+	Port     int               `yaml:"port" default:"8080" comment:"Port to listen on."`
+	Timeout  time.Duration     `yaml:"timeout" default:"5s"`
+	Limit    Bytes             `yaml:"limit" default:"1MiB"`
+	Debug    bool              `yaml:"debug"`
+	Tags     []string          `yaml:"tags"`
+	Labels   map[string]string `yaml:"labels"`
+	TLS      *templateTLS      `yaml:"tls"`
+	Backends []templateBackend `yaml:"backends" comment:"Upstream servers."`
+	Ignored  string            `yaml:"-"`
+	internal string
+}
+
+func TestTemplate(t *testing.T) {
+	p, err := NewYAML()
+	require.NoError(t, err, "couldn't construct provider")
+
+	t.Run("golden", func(t *testing.T) {
+		cfg := templateConfig{
+			Name:   "api",
+			Labels: map[string]string{"team": "core"},
+		}
+		got, err := p.Template(&cfg)
+		require.NoError(t, err, "couldn't generate template")
+		want, err := ioutil.ReadFile("testdata/template.golden")
+		require.NoError(t, err, "couldn't read golden file")
+		assert.Equal(t, string(want), string(got), "template doesn't match golden file")
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		bs, err := p.Template(templateConfig{})
+		require.NoError(t, err, "couldn't generate template")
+		loaded, err := NewYAML(Source(strings.NewReader(string(bs))))
+		require.NoError(t, err, "couldn't load template")
+		var cfg templateConfig
+		require.NoError(t, loaded.Get(Root).Populate(&cfg), "couldn't populate from template")
+		assert.Equal(t, 8080, cfg.Port, "expected default port")
+		assert.Equal(t, 5*time.Second, cfg.Timeout, "expected default timeout")
+		assert.Equal(t, Bytes(1<<20), cfg.Limit, "expected default limit")
+		require.NotNil(t, cfg.TLS, "expected nil pointer to be expanded")
+		assert.Equal(t, []templateBackend{{Weight: 1}}, cfg.Backends, "expected one example backend")
+	})
+
+	t.Run("struct tag", func(t *testing.T) {
+		tagged, err := NewYAML(StructTag("json"))
+		require.NoError(t, err, "couldn't construct provider")
+		bs, err := tagged.Template(struct {
+			Port int `json:"listen_port" comment:"Port."`
+		}{})
+		require.NoError(t, err, "couldn't generate template")
+		assert.Equal(t, "# Port.\nlisten_port: 0\n", string(bs), "expected template to use the provider's struct tag")
+	})
+
+	t.Run("recursive types", func(t *testing.T) {
+		type node struct {
+			Name     string
+			Children []node
+			Next     *node
+		}
+		bs, err := p.Template(node{Children: []node{{Name: "leaf"}}})
+		require.NoError(t, err, "couldn't generate template")
+		assert.Equal(t, strings.Join([]string{
+			"name: \"\"",
+			"children:",
+			"  - name: leaf",
+			"    children: []",
+			"    next: null",
+			"next: null",
+			"",
+		}, "\n"), string(bs), "expected recursive fields not to be expanded again")
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, err := p.Template(42)
+		assert.Error(t, err, "expected error for non-struct target")
+		_, err = p.Template(nil)
+		assert.Error(t, err, "expected error for nil target")
+	})
+}
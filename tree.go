@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const _treeIndent = "  "
+
+// Tree renders the provider's configuration as an indented tree for people
+// to read, for example in a command-line tool's config dump subcommand. Each
+// line shows a key and its kind; scalars also show their value, with strings
+// quoted so that, say, the string "80" is distinguishable from the integer
+// 80. For example,
+//   server: {host: localhost, ports: [80, 443], tls: ~}
+// renders as
+//   server: mapping
+//     host: "localhost" (string)
+//     ports: sequence
+//       0: 80 (integer)
+//       1: 443 (integer)
+//     tls: null
+// Mapping keys are sorted, so the output is deterministic, and sequence
+// elements are labeled with their indices. The format is meant for humans
+// and may change; to serialize configuration, use CanonicalBytes. An empty
+// provider renders as an empty string.
+func (y *YAML) Tree() string {
+	if y.empty {
+		return ""
+	}
+	var b strings.Builder
+	switch y.contents.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		writeTreeChildren(&b, y.contents, 0)
+	default:
+		b.WriteString(treeLabel(y.contents))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func writeTreeChildren(b *strings.Builder, node interface{}, depth int) {
+	switch n := node.(type) {
+	case map[interface{}]interface{}:
+		keys := make([]interface{}, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, k := range keys {
+			writeTreeNode(b, fmt.Sprint(k), n[k], depth)
+		}
+	case []interface{}:
+		for i, elem := range n {
+			writeTreeNode(b, strconv.Itoa(i), elem, depth)
+		}
+	}
+}
+
+func writeTreeNode(b *strings.Builder, key string, node interface{}, depth int) {
+	b.WriteString(strings.Repeat(_treeIndent, depth))
+	b.WriteString(key)
+	b.WriteString(": ")
+	b.WriteString(treeLabel(node))
+	b.WriteByte('\n')
+	writeTreeChildren(b, node, depth+1)
+}
+
+// treeLabel describes a node on a single line.
+func treeLabel(node interface{}) string {
+	switch n := node.(type) {
+	case nil:
+		return "null"
+	case map[interface{}]interface{}:
+		if len(n) == 0 {
+			return "mapping (empty)"
+		}
+		return "mapping"
+	case []interface{}:
+		if len(n) == 0 {
+			return "sequence (empty)"
+		}
+		return "sequence"
+	case string:
+		return fmt.Sprintf("%q (string)", n)
+	}
+	return fmt.Sprintf("%v (%s)", node, yamlKind(node))
+}
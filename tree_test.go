@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	tree := func(t *testing.T, src string) string {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		return p.Tree()
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		src := strings.Join([]string{
+			"server:",
+			"  tls: ~",
+			"  ports: [80, 443]",
+			"  host: localhost",
+			"  port_name: '80'",
+			"  ratio: 0.5",
+			"  debug: false",
+			"  labels: {}",
+			"  peers: []",
+			"routes:",
+			"  - {path: /, backends: [a, b]}",
+			"name: api",
+		}, "\n")
+		const golden = `name: "api" (string)
+routes: sequence
+  0: mapping
+    backends: sequence
+      0: "a" (string)
+      1: "b" (string)
+    path: "/" (string)
+server: mapping
+  debug: false (boolean)
+  host: "localhost" (string)
+  labels: mapping (empty)
+  peers: sequence (empty)
+  port_name: "80" (string)
+  ports: sequence
+    0: 80 (integer)
+    1: 443 (integer)
+  ratio: 0.5 (float)
+  tls: null
+`
+		assert.Equal(t, golden, tree(t, src), "unexpected tree")
+		assert.Equal(t, tree(t, src), tree(t, src), "expected deterministic output")
+	})
+
+	t.Run("non-string keys", func(t *testing.T) {
+		assert.Equal(t, "1: \"car\" (string)\n2: \"bus\" (string)\n", tree(t, "2: bus\n1: car"), "unexpected tree")
+	})
+
+	t.Run("top-level values", func(t *testing.T) {
+		assert.Equal(t, "0: 1 (integer)\n1: \"x\" (string)\n", tree(t, "[1, x]"), "unexpected tree for a sequence")
+		assert.Equal(t, "\"foo\" (string)\n", tree(t, "foo"), "unexpected tree for a scalar")
+		assert.Equal(t, "null\n", tree(t, "~"), "unexpected tree for a null")
+		assert.Equal(t, "", tree(t, ""), "unexpected tree for an empty provider")
+	})
+}
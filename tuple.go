@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tupleToMapping converts a sequence populating a struct field tagged
+// format:"tuple" into a mapping, pairing elements with the struct's fields in
+// declaration order. If the field is a slice or array of structs, each
+// element that's itself a sequence is converted. Other nodes are returned
+// unchanged.
+func tupleToMapping(node interface{}, t reflect.Type, tag string, path []string) (interface{}, error) {
+	seq, ok := node.([]interface{})
+	if !ok {
+		return node, nil
+	}
+	t = derefType(t)
+	switch t.Kind() {
+	case reflect.Struct:
+		return tupleStruct(seq, t, tag, path)
+	case reflect.Slice, reflect.Array:
+		if !isStruct(t.Elem()) {
+			break
+		}
+		converted := make([]interface{}, len(seq))
+		for i, elem := range seq {
+			c, err := tupleToMapping(elem, t.Elem(), tag, extend(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = c
+		}
+		return converted, nil
+	}
+	return node, nil
+}
+
+func tupleStruct(seq []interface{}, t reflect.Type, tag string, path []string) (interface{}, error) {
+	fields, err := taggedFields(t, tag)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !f.unexported {
+			keys = append(keys, f.key)
+		}
+	}
+	if len(seq) != len(keys) {
+		return nil, fmt.Errorf(
+			"couldn't populate key %q: sequence has %d elements, but %v has %d fields",
+			strings.Join(path, _separator), len(seq), t, len(keys),
+		)
+	}
+	m := make(map[interface{}]interface{}, len(keys))
+	for i, k := range keys {
+		m[k] = seq[i]
+	}
+	return m, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopulateTuple(t *testing.T) {
+	type point struct {
+		X, Y, Z int
+	}
+	type shape struct {
+		Origin  point    `yaml:"origin" format:"tuple"`
+		Center  *point   `yaml:"center" format:"tuple"`
+		Path    []point  `yaml:"path" format:"tuple"`
+		Untuple point    `yaml:"untuple"`
+		Labels  []string `yaml:"labels" format:"tuple"`
+	}
+	populate := func(t testing.TB, src string) (shape, error) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var s shape
+		err = p.Get(Root).Populate(&s)
+		return s, err
+	}
+
+	t.Run("exact", func(t *testing.T) {
+		s, err := populate(t, `
+origin: [1, 2, 3]
+center: [4, 5, 6]
+path: [[0, 0, 0], {x: 1, "y": 1, z: 1}]
+labels: [a, b]
+`)
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, point{1, 2, 3}, s.Origin, "unexpected tuple")
+		require.NotNil(t, s.Center, "expected pointer to be allocated")
+		assert.Equal(t, point{4, 5, 6}, *s.Center, "unexpected tuple through pointer")
+		assert.Equal(t, []point{{0, 0, 0}, {1, 1, 1}}, s.Path, "unexpected sequence of tuples")
+		assert.Equal(t, []string{"a", "b"}, s.Labels, "expected non-struct fields to be unaffected")
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+		s, err := populate(t, "origin: {x: 7, z: 9}")
+		require.NoError(t, err, "couldn't populate")
+		assert.Equal(t, point{X: 7, Z: 9}, s.Origin, "expected mappings to populate tagged fields too")
+	})
+
+	tests := []struct {
+		desc string
+		src  string
+		err  string
+	}{
+		{"too short", "origin: [1, 2]", `couldn't populate key "origin": sequence has 2 elements, but config.point has 3 fields`},
+		{"too long", "origin: [1, 2, 3, 4]", `couldn't populate key "origin": sequence has 4 elements, but config.point has 3 fields`},
+		{"element", "path: [[1, 2, 3], [1]]", `couldn't populate key "path.1": sequence has 1 elements`},
+		{"untagged", "untuple: [1, 2, 3]", `couldn't populate key "untuple"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := populate(t, tt.src)
+			require.Error(t, err, "expected populate to fail")
+			assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Duration is a time.Duration that populates from either a string in Go's
+// duration format (for example, "1h30m"; see time.ParseDuration) or a bare
+// integer number of nanoseconds. It marshals to the string format, so it
+// round-trips through Static.
+type Duration time.Duration
+
+var (
+	_ yaml.Unmarshaler = (*Duration)(nil)
+	_ yaml.Marshaler   = Duration(0)
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*d = Duration(n)
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %v", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// String formats the duration like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Bytes is a number of bytes that populates from either a bare integer or a
+// human-friendly size with an SI or IEC suffix, like "256MB" (256 * 1000^2
+// bytes) or "1.5GiB" (1.5 * 1024^3 bytes). Suffixes are case-insensitive and
+// may be separated from the number by spaces; the supported suffixes are B,
+// KB, MB, GB, TB, PB, and EB, along with their IEC counterparts KiB through
+// EiB and the abbreviations K through E and Ki through Ei. Fractional sizes
+// must amount to a whole number of bytes. Bytes marshals to a bare integer,
+// so it round-trips through Static.
+type Bytes int64
+
+var (
+	_ yaml.Unmarshaler = (*Bytes)(nil)
+	_ yaml.Marshaler   = Bytes(0)
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (b *Bytes) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	n, err := parseBytes(s)
+	if err != nil {
+		return fmt.Errorf("invalid byte size %q: %v", s, err)
+	}
+	*b = Bytes(n)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (b Bytes) MarshalYAML() (interface{}, error) {
+	return int64(b), nil
+}
+
+const (
+	_kilo = 1000
+	_kibi = 1024
+)
+
+var _byteUnits = map[string]int64{
+	"": 1, "b": 1,
+
+	"k": _kilo, "kb": _kilo,
+	"m": _kilo * _kilo, "mb": _kilo * _kilo,
+	"g": _kilo * _kilo * _kilo, "gb": _kilo * _kilo * _kilo,
+	"t": _kilo * _kilo * _kilo * _kilo, "tb": _kilo * _kilo * _kilo * _kilo,
+	"p": _kilo * _kilo * _kilo * _kilo * _kilo, "pb": _kilo * _kilo * _kilo * _kilo * _kilo,
+	"e": _kilo * _kilo * _kilo * _kilo * _kilo * _kilo, "eb": _kilo * _kilo * _kilo * _kilo * _kilo * _kilo,
+
+	"ki": _kibi, "kib": _kibi,
+	"mi": _kibi * _kibi, "mib": _kibi * _kibi,
+	"gi": _kibi * _kibi * _kibi, "gib": _kibi * _kibi * _kibi,
+	"ti": _kibi * _kibi * _kibi * _kibi, "tib": _kibi * _kibi * _kibi * _kibi,
+	"pi": _kibi * _kibi * _kibi * _kibi * _kibi, "pib": _kibi * _kibi * _kibi * _kibi * _kibi,
+	"ei": _kibi * _kibi * _kibi * _kibi * _kibi * _kibi, "eib": _kibi * _kibi * _kibi * _kibi * _kibi * _kibi,
+}
+
+func parseBytes(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end == -1 {
+		end = len(s)
+	}
+	num, unit := s[:end], strings.TrimSpace(s[end:])
+	if num == "" {
+		return 0, errors.New("expected a non-negative number")
+	}
+	size, ok := new(big.Rat).SetString(num)
+	if !ok {
+		return 0, fmt.Errorf("malformed number %q", num)
+	}
+	multiplier, ok := _byteUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+	size.Mul(size, new(big.Rat).SetInt64(multiplier))
+	if !size.IsInt() {
+		return 0, errors.New("not a whole number of bytes")
+	}
+	if !size.Num().IsInt64() {
+		return 0, errors.New("too large")
+	}
+	return size.Num().Int64(), nil
+}
+
+// StringList is a list of strings that populates from either a YAML sequence
+// or a single comma-separated string, which lets list-valued fields be set
+// from environment variables and other sources that can only hold scalars.
+// Given
+//   type Server struct {
+//     Hosts StringList `yaml:"hosts"`
+//   }
+// both {hosts: [a, b, c]} and {hosts: "a, b, c"} populate Hosts with
+// []string{"a", "b", "c"}.
+//
+// In the comma-separated form, whitespace around each element is trimmed and
+// elements that are empty after trimming are dropped, so an empty or
+// all-whitespace string populates an empty list. There's no way to escape a
+// comma, so elements containing commas must be written as a sequence.
+// Elements of a sequence are used exactly as written. StringList marshals to
+// a sequence, so it round-trips through Static.
+type StringList []string
+
+var (
+	_ yaml.Unmarshaler = (*StringList)(nil)
+	_ yaml.Marshaler   = StringList(nil)
+)
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *StringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var seq []string
+	if err := unmarshal(&seq); err == nil {
+		*l = seq
+		return nil
+	}
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return fmt.Errorf("invalid string list: expected a sequence of strings or a comma-separated string: %v", err)
+	}
+	list := make(StringList, 0, strings.Count(s, ",")+1)
+	for _, elem := range strings.Split(s, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			list = append(list, elem)
+		}
+	}
+	*l = list
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (l StringList) MarshalYAML() (interface{}, error) {
+	return []string(l), nil
+}
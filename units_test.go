@@ -0,0 +1,186 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		yaml   string
+		expect time.Duration
+		err    string
+	}{
+		{yaml: "1h30m", expect: 90 * time.Minute},
+		{yaml: "'250ms'", expect: 250 * time.Millisecond},
+		{yaml: "-1s", expect: -time.Second},
+		{yaml: "1500", expect: 1500 * time.Nanosecond},
+		{yaml: "0", expect: 0},
+		{yaml: "1.5", err: `invalid duration "1.5"`},
+		{yaml: "soon", err: `invalid duration "soon"`},
+		{yaml: "[1s]", err: "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			p, err := NewYAML(Source(strings.NewReader("timeout: " + tt.yaml)))
+			require.NoError(t, err, "couldn't construct provider")
+			var cfg struct{ Timeout Duration }
+			err = p.Get(Root).Populate(&cfg)
+			if tt.err != "" {
+				require.Error(t, err, "expected populate to fail")
+				assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+				return
+			}
+			require.NoError(t, err, "couldn't populate")
+			assert.Equal(t, tt.expect, time.Duration(cfg.Timeout), "unexpected duration")
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		p, err := NewYAML(Static(map[string]Duration{"timeout": Duration(90 * time.Second)}))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "1m30s", p.Get("timeout").Value(), "expected duration to marshal to a string")
+		var d Duration
+		require.NoError(t, p.Get("timeout").Populate(&d), "couldn't populate")
+		assert.Equal(t, Duration(90*time.Second), d, "unexpected duration")
+	})
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		yaml   string
+		expect int64
+		err    string
+	}{
+		{yaml: "1024", expect: 1024},
+		{yaml: "0", expect: 0},
+		{yaml: "'512'", expect: 512},
+		{yaml: "10B", expect: 10},
+		{yaml: "256MB", expect: 256 * 1000 * 1000},
+		{yaml: "256mb", expect: 256 * 1000 * 1000},
+		{yaml: "4 KiB", expect: 4096},
+		{yaml: "2Gi", expect: 2 << 30},
+		{yaml: "1.5GiB", expect: 3 << 29},
+		{yaml: "1.5k", expect: 1500},
+		{yaml: "8EiB", err: "too large"},
+		{yaml: "1.5", err: "not a whole number of bytes"},
+		{yaml: "1.0001KB", err: "not a whole number of bytes"},
+		{yaml: "-1KB", err: "expected a non-negative number"},
+		{yaml: "MB", err: "expected a non-negative number"},
+		{yaml: "1.2.3MB", err: "malformed number"},
+		{yaml: "12 parsecs", err: `unknown unit "parsecs"`},
+		{yaml: "{size: 1}", err: "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.yaml, func(t *testing.T) {
+			p, err := NewYAML(Source(strings.NewReader("size: " + tt.yaml)))
+			require.NoError(t, err, "couldn't construct provider")
+			var cfg struct{ Size Bytes }
+			err = p.Get(Root).Populate(&cfg)
+			if tt.err != "" {
+				require.Error(t, err, "expected populate to fail")
+				assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+				return
+			}
+			require.NoError(t, err, "couldn't populate")
+			assert.Equal(t, Bytes(tt.expect), cfg.Size, "unexpected size")
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		p, err := NewYAML(Static(map[string]Bytes{"size": 4096}))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, 4096, p.Get("size").Value(), "expected size to marshal to an integer")
+	})
+
+	t.Run("coerced", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("size: ${SIZE}")), CoerceScalars(), Expand(func(string) (string, bool) {
+			return "64MiB", true
+		}))
+		require.NoError(t, err, "couldn't construct provider")
+		var cfg struct{ Size Bytes }
+		require.NoError(t, p.Get(Root).Populate(&cfg), "expected CoerceScalars to leave sizes alone")
+		assert.Equal(t, Bytes(64<<20), cfg.Size, "unexpected size")
+	})
+}
+
+func TestStringList(t *testing.T) {
+	tests := []struct {
+		desc   string
+		yaml   string
+		expect []string
+		err    string
+	}{
+		{desc: "sequence", yaml: "[a, b, c]", expect: []string{"a", "b", "c"}},
+		{desc: "sequence kept as written", yaml: "[' a ', 'b,c']", expect: []string{" a ", "b,c"}},
+		{desc: "block sequence", yaml: "\n  - a\n  - 80", expect: []string{"a", "80"}},
+		{desc: "empty sequence", yaml: "[]", expect: []string{}},
+		{desc: "comma-separated", yaml: "a,b,c", expect: []string{"a", "b", "c"}},
+		{desc: "whitespace trimmed", yaml: "' a ,  b,c  '", expect: []string{"a", "b", "c"}},
+		{desc: "empty elements dropped", yaml: "a,,b,", expect: []string{"a", "b"}},
+		{desc: "single element", yaml: "a", expect: []string{"a"}},
+		{desc: "non-string scalar", yaml: "80", expect: []string{"80"}},
+		{desc: "empty string", yaml: "''", expect: []string{}},
+		{desc: "whitespace only", yaml: "' , '", expect: []string{}},
+		{desc: "mapping", yaml: "{a: b}", err: "invalid string list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := NewYAML(Source(strings.NewReader("hosts: " + tt.yaml)))
+			require.NoError(t, err, "couldn't construct provider")
+			var cfg struct{ Hosts StringList }
+			err = p.Get(Root).Populate(&cfg)
+			if tt.err != "" {
+				require.Error(t, err, "expected populate to fail")
+				assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+				return
+			}
+			require.NoError(t, err, "couldn't populate")
+			assert.Equal(t, StringList(tt.expect), cfg.Hosts, "unexpected list")
+		})
+	}
+
+	t.Run("environment", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("hosts: ${HOSTS}")),
+			Expand(func(string) (string, bool) { return "a.example.com, b.example.com", true }),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		var hosts StringList
+		require.NoError(t, p.Get("hosts").Populate(&hosts), "couldn't populate")
+		assert.Equal(t, StringList{"a.example.com", "b.example.com"}, hosts, "unexpected list from environment")
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		p, err := NewYAML(Static(map[string]StringList{"hosts": {"a", "b"}}))
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, []interface{}{"a", "b"}, p.Get("hosts").Value(), "expected list to marshal to a sequence")
+		var l StringList
+		require.NoError(t, p.Get("hosts").Populate(&l), "couldn't populate")
+		assert.Equal(t, StringList{"a", "b"}, l, "unexpected list")
+	})
+}
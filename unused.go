@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PopulateWithUnused populates target from the root of the configuration,
+// like Get(Root).Populate, and also reports the dotted paths of configured
+// keys that the target has no field for. It's the complement of strict
+// mode's unknown-key errors, and is useful for finding dead configuration in
+// permissive providers (in strict providers, populating fails if any key is
+// unused).
+//
+// Keys are reported at the shallowest unused level: if the configuration
+// sets server.extra.a and server.extra.b but the server struct has no extra
+// field, only server.extra is reported. The elements of maps and sequences
+// are checked against their element types, while values populated into
+// interfaces or into types that implement their own unmarshaling are
+// consumed entirely. Paths are sorted. If populating fails, the error is
+// returned without any paths.
+func (y *YAML) PopulateWithUnused(target interface{}) ([]string, error) {
+	t := reflect.TypeOf(target)
+	if t == nil {
+		return nil, errors.New("can't populate a nil target")
+	}
+	if err := y.Get(Root).Populate(target); err != nil {
+		return nil, err
+	}
+	if y.empty {
+		return nil, nil
+	}
+	var unused []string
+	if err := y.collectUnused(&unused, y.contents, t, nil); err != nil {
+		return nil, err
+	}
+	sort.Strings(unused)
+	return unused, nil
+}
+
+func (y *YAML) collectUnused(unused *[]string, node interface{}, t reflect.Type, path []string) error {
+	t = derefType(t)
+	if isOpaque(t) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		known := make(map[string]reflect.Type)
+		if err := y.knownKeys(known, t, []reflect.Type{t}); err != nil {
+			return err
+		}
+		for k, v := range m {
+			key := fmt.Sprint(k)
+			child := extend(path, key)
+			ft, ok := known[key]
+			if !ok {
+				*unused = append(*unused, strings.Join(child, _separator))
+				continue
+			}
+			if err := y.collectUnused(unused, v, ft, child); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil
+		}
+		for k, v := range m {
+			if err := y.collectUnused(unused, v, t.Elem(), extend(path, fmt.Sprint(k))); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		seq, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, v := range seq {
+			if err := y.collectUnused(unused, v, t.Elem(), extend(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// knownKeys collects the configuration keys a struct type accepts, along
+// with the types they populate. As in walkFields, the fields of untagged
+// embedded structs may appear under their own key or be promoted, and a
+// struct's own fields shadow promoted ones.
+func (y *YAML) knownKeys(known map[string]reflect.Type, t reflect.Type, promoting []reflect.Type) error {
+	fields, err := taggedFields(t, y.tag)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.unexported {
+			continue
+		}
+		if _, ok := known[f.key]; !ok {
+			known[f.key] = f.typ
+		}
+	}
+	for _, f := range fields {
+		if f.unexported || !f.embedded {
+			continue
+		}
+		inner := derefType(f.typ)
+		if containsType(promoting, inner) {
+			continue
+		}
+		if err := y.knownKeys(known, inner, append(promoting, inner)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPopulateWithUnused(t *testing.T) {
+	type Common struct {
+		Region string `yaml:"region"`
+	}
+	type backend struct {
+		Host string `yaml:"host"`
+	}
+	type cfg struct {
+		Common
+		Name     string             `yaml:"name"`
+		Backends []backend          `yaml:"backends"`
+		Zones    map[string]backend `yaml:"zones"`
+		Extra    interface{}        `yaml:"extra"`
+		Timeout  Duration           `yaml:"timeout"`
+	}
+	const src = `
+name: api
+region: us-east
+legacy: {a: 1, b: 2}
+backends:
+  - {host: a}
+  - {host: b, weight: 3}
+zones:
+  east: {host: e, port: 80}
+extra: {anything: goes}
+timeout: 5s
+`
+
+	t.Run("permissive", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)), Permissive())
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		unused, err := p.PopulateWithUnused(&c)
+		require.NoError(t, err, "populate failed")
+		assert.Equal(t, []string{"backends.1.weight", "legacy", "zones.east.port"}, unused, "unexpected unused keys")
+		assert.Equal(t, "api", c.Name, "expected target to be populated")
+		assert.Equal(t, "us-east", c.Region, "expected promoted field to be populated")
+	})
+
+	t.Run("all used", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("name: api\nbackends: [{host: a}]")))
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		unused, err := p.PopulateWithUnused(&c)
+		require.NoError(t, err, "populate failed")
+		assert.Empty(t, unused, "expected no unused keys")
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var c cfg
+		unused, err := p.PopulateWithUnused(&c)
+		assert.Error(t, err, "expected strict populate to fail")
+		assert.Nil(t, unused, "expected no paths on failure")
+	})
+
+	t.Run("nil target", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		_, err = p.PopulateWithUnused(nil)
+		assert.Error(t, err, "expected error for nil target")
+	})
+}
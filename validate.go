@@ -0,0 +1,221 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	_rangeTagName = "range"
+	_oneOfTagName = "oneof"
+)
+
+// validateTags enforces the range and oneof struct tags (see the package
+// documentation) on a populated value, reporting the first violation along
+// with its key. It descends through pointers, interfaces, struct fields,
+// sequence elements, and map values.
+func validateTags(v reflect.Value, path []string, tag string) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return validateTags(v.Elem(), path, tag)
+	case reflect.Struct:
+		if isOpaque(v.Type()) {
+			return nil
+		}
+		fields, err := taggedFields(v.Type(), tag)
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if field.unexported {
+				continue
+			}
+			fv := v.FieldByIndex(field.index)
+			childPath := path
+			if !field.embedded {
+				childPath = extend(path, field.key)
+			}
+			if err := checkFieldTags(fv, field, v.Type(), childPath); err != nil {
+				return err
+			}
+			if err := validateTags(fv, childPath, tag); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateTags(v.Index(i), extend(path, strconv.Itoa(i)), tag); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			if err := validateTags(v.MapIndex(k), extend(path, fmt.Sprint(k.Interface())), tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkFieldTags checks a struct field's value against its range and oneof
+// tags. Tags on pointer, slice, and array fields apply to the values they
+// hold.
+func checkFieldTags(v reflect.Value, f field, parent reflect.Type, path []string) error {
+	checks := []struct {
+		name  string
+		check func(reflect.Value, string) (string, error)
+	}{
+		{_rangeTagName, checkRange},
+		{_oneOfTagName, checkOneOf},
+	}
+	for _, c := range checks {
+		spec, ok := f.tag.Lookup(c.name)
+		if !ok {
+			continue
+		}
+		err := eachValue(v, path, func(v reflect.Value, path []string) error {
+			problem, err := c.check(v, spec)
+			if err != nil {
+				return fmt.Errorf("invalid %s tag %q for field %s of %v: %v", c.name, spec, f.name, parent, err)
+			}
+			if problem != "" {
+				return fmt.Errorf("invalid value for key %q: %s", strings.Join(path, _separator), problem)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachValue calls f for v, or, if v is a pointer, slice, or array, for each
+// value it holds. Nil pointers hold no values.
+func eachValue(v reflect.Value, path []string, f func(reflect.Value, []string) error) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return eachValue(v.Elem(), path, f)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := eachValue(v.Index(i), extend(path, strconv.Itoa(i)), f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return f(v, path)
+}
+
+// checkRange checks a number against a range tag of the form "min,max",
+// either of which may be omitted. It returns a description of the problem if
+// the number is out of range, and an error if the tag is unusable.
+func checkRange(v reflect.Value, spec string) (string, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) != 2 {
+		return "", fmt.Errorf(`range must have the form "min,max"`)
+	}
+	lo, hi := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	var below, above func(string) (bool, error)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		below = func(s string) (bool, error) {
+			n, err := strconv.ParseInt(s, 10, 64)
+			return v.Int() < n, err
+		}
+		above = func(s string) (bool, error) {
+			n, err := strconv.ParseInt(s, 10, 64)
+			return v.Int() > n, err
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		below = func(s string) (bool, error) {
+			n, err := strconv.ParseUint(s, 10, 64)
+			return v.Uint() < n, err
+		}
+		above = func(s string) (bool, error) {
+			n, err := strconv.ParseUint(s, 10, 64)
+			return v.Uint() > n, err
+		}
+	case reflect.Float32, reflect.Float64:
+		below = func(s string) (bool, error) {
+			n, err := strconv.ParseFloat(s, 64)
+			return v.Float() < n, err
+		}
+		above = func(s string) (bool, error) {
+			n, err := strconv.ParseFloat(s, 64)
+			return v.Float() > n, err
+		}
+	default:
+		return "", fmt.Errorf("range requires a numeric field, not %v", v.Type())
+	}
+
+	if lo != "" {
+		out, err := below(lo)
+		if err != nil {
+			return "", fmt.Errorf("invalid minimum: %v", err)
+		}
+		if out {
+			return fmt.Sprintf("%v is less than the minimum of %s", v.Interface(), lo), nil
+		}
+	}
+	if hi != "" {
+		out, err := above(hi)
+		if err != nil {
+			return "", fmt.Errorf("invalid maximum: %v", err)
+		}
+		if out {
+			return fmt.Sprintf("%v is greater than the maximum of %s", v.Interface(), hi), nil
+		}
+	}
+	return "", nil
+}
+
+// checkOneOf checks a string against a oneof tag listing the permitted
+// values, separated by spaces.
+func checkOneOf(v reflect.Value, spec string) (string, error) {
+	if v.Kind() != reflect.String {
+		return "", fmt.Errorf("oneof requires a string field, not %v", v.Type())
+	}
+	allowed := strings.Fields(spec)
+	for _, a := range allowed {
+		if v.String() == a {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("%q isn't one of %s", v.String(), strings.Join(allowed, ", ")), nil
+}
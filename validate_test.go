@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationTags(t *testing.T) {
+	type limits struct {
+		Ratio float64 `yaml:"ratio" range:"0,1"`
+	}
+	type server struct {
+		Port    int               `yaml:"port" range:"1,65535"`
+		Workers uint              `yaml:"workers" range:"1,"`
+		Retries *int              `yaml:"retries" range:",10"`
+		Weights []int             `yaml:"weights" range:"0,100"`
+		Level   string            `yaml:"level" oneof:"debug info warn error"`
+		Limits  map[string]limits `yaml:"limits"`
+	}
+	populate := func(t testing.TB, src string) (server, error) {
+		p, err := NewYAML(Source(strings.NewReader(src)))
+		require.NoError(t, err, "couldn't construct provider")
+		var s server
+		err = p.Get("server").Populate(&s)
+		return s, err
+	}
+	const valid = `
+server:
+  port: 8080
+  workers: 4
+  level: info
+  weights: [0, 50, 100]
+  limits: {api: {ratio: 0.5}}
+`
+
+	t.Run("in range", func(t *testing.T) {
+		s, err := populate(t, valid)
+		require.NoError(t, err, "expected valid configuration to populate")
+		assert.Equal(t, 8080, s.Port, "unexpected port")
+		assert.Nil(t, s.Retries, "expected unset pointer to be skipped")
+	})
+
+	t.Run("boundaries", func(t *testing.T) {
+		_, err := populate(t, "server: {port: 1, workers: 1, retries: 10, level: debug}")
+		assert.NoError(t, err, "expected minimum values to be valid")
+		_, err = populate(t, "server: {port: 65535, workers: 1, level: error}")
+		assert.NoError(t, err, "expected maximum value to be valid")
+	})
+
+	tests := []struct {
+		desc string
+		src  string
+		err  string
+	}{
+		{
+			desc: "above maximum",
+			src:  "server: {port: 65536, workers: 1, level: info}",
+			err:  `invalid value for key "server.port": 65536 is greater than the maximum of 65535`,
+		},
+		{
+			desc: "below minimum",
+			src:  "server: {port: 0, workers: 1, level: info}",
+			err:  `invalid value for key "server.port": 0 is less than the minimum of 1`,
+		},
+		{
+			desc: "unset field",
+			src:  "server: {port: 80, level: info}",
+			err:  `invalid value for key "server.workers": 0 is less than the minimum of 1`,
+		},
+		{
+			desc: "pointer",
+			src:  "server: {port: 80, workers: 1, retries: 11, level: info}",
+			err:  `invalid value for key "server.retries": 11 is greater than the maximum of 10`,
+		},
+		{
+			desc: "sequence element",
+			src:  "server: {port: 80, workers: 1, level: info, weights: [1, 101]}",
+			err:  `invalid value for key "server.weights.1": 101 is greater than the maximum of 100`,
+		},
+		{
+			desc: "map value",
+			src:  "server: {port: 80, workers: 1, level: info, limits: {api: {ratio: 1.5}}}",
+			err:  `invalid value for key "server.limits.api.ratio": 1.5 is greater than the maximum of 1`,
+		},
+		{
+			desc: "oneof",
+			src:  "server: {port: 80, workers: 1, level: verbose}",
+			err:  `invalid value for key "server.level": "verbose" isn't one of debug, info, warn, error`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := populate(t, tt.src)
+			require.Error(t, err, "expected validation to fail")
+			assert.Contains(t, err.Error(), tt.err, "unexpected error message")
+		})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("port: 80")), UseDefaultTags())
+		require.NoError(t, err, "couldn't construct provider")
+		var s struct {
+			Port  int    `yaml:"port" range:"1,1024"`
+			Level string `yaml:"level" default:"trace" oneof:"debug info"`
+		}
+		err = p.Get(Root).Populate(&s)
+		require.Error(t, err, "expected invalid default to fail validation")
+		assert.Contains(t, err.Error(), `invalid value for key "level"`, "unexpected error message")
+	})
+
+	t.Run("invalid tags", func(t *testing.T) {
+		p, err := NewYAML(Source(strings.NewReader("a: 1\nb: x")), Permissive())
+		require.NoError(t, err, "couldn't construct provider")
+
+		var bounds struct {
+			A int `yaml:"a" range:"low,high"`
+		}
+		err = p.Get(Root).Populate(&bounds)
+		require.Error(t, err, "expected unparseable range to fail")
+		assert.Contains(t, err.Error(), `invalid range tag "low,high"`, "unexpected error message")
+
+		var kind struct {
+			B string `yaml:"b" range:"1,2"`
+		}
+		err = p.Get(Root).Populate(&kind)
+		require.Error(t, err, "expected range on a string to fail")
+		assert.Contains(t, err.Error(), "range requires a numeric field", "unexpected error message")
+	})
+}
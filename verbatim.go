@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/config/internal/unreachable"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// A verbatimValue is the original string found at a verbatim path.
+type verbatimValue struct {
+	path  []string
+	value string
+}
+
+// Verbatim marks paths whose string values must be preserved exactly as
+// written in the source that sets them. Values at verbatim paths are never
+// subject to variable expansion, which makes them a good fit for embedded
+// scripts, templates, and PEM blocks.
+//
+// When several sources set a verbatim path, the value from the
+// highest-priority source is used. Verbatim only applies to string scalars;
+// if the winning source sets a mapping, sequence, or non-string scalar at the
+// path, the path is merged and expanded normally.
+func Verbatim(paths ...string) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.verbatim = append(c.verbatim, paths...)
+	})
+}
+
+// verbatimValues re-applies previously-captured verbatim values. It's used
+// when rebuilding a provider, since the rebuilt provider's sources have
+// already been escaped.
+func verbatimValues(vals []verbatimValue) YAMLOption {
+	return optionFunc(func(c *config) {
+		c.verbatimValues = vals
+	})
+}
+
+// snapshotVerbatim finds the original value of each verbatim path in the
+// highest-priority source that sets it.
+func snapshotVerbatim(sources []source, paths []string, strict bool, mode KeyMatchMode) []verbatimValue {
+	decoded := make([]interface{}, len(sources))
+	for i, s := range sources {
+		dec := yaml.NewDecoder(bytes.NewReader(s.bytes))
+		dec.SetStrict(strict)
+		// Merging has already validated all sources, so any error here is
+		// just an empty source.
+		dec.Decode(&decoded[i])
+	}
+
+	var vals []verbatimValue
+	for _, p := range paths {
+		path := strings.Split(p, _separator)
+		if p == Root {
+			path = nil
+		}
+		for i := len(decoded) - 1; i >= 0; i-- {
+			val, ok := lookup(decoded[i], path, mode)
+			if !ok {
+				continue
+			}
+			if s, ok := val.(string); ok {
+				vals = append(vals, verbatimValue{path: path, value: s})
+			}
+			break
+		}
+	}
+	return vals
+}
+
+// protectVerbatim overwrites the verbatim paths in the merged YAML with their
+// original values. If variables are about to be expanded, the values are
+// escaped so that expansion restores them exactly.
+func protectVerbatim(merged *bytes.Buffer, vals []verbatimValue, escape bool, mode KeyMatchMode) (*bytes.Buffer, error) {
+	var contents interface{}
+	if err := yaml.NewDecoder(merged).Decode(&contents); err == io.EOF {
+		return merged, nil
+	} else if err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't decode merged YAML: %v", err))
+	}
+
+	for _, v := range vals {
+		s := v.value
+		if escape {
+			s = string(escapeVariables([]byte(s)))
+		}
+		contents = replaceAt(contents, v.path, s, mode)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := yaml.NewEncoder(buf).Encode(contents); err != nil {
+		return nil, unreachable.Wrap(fmt.Errorf("couldn't re-serialize merged YAML: %v", err))
+	}
+	return buf, nil
+}
+
+// replaceAt replaces the value at a path, returning the updated root. If the
+// path doesn't exist (for example, because a higher-priority source removed a
+// parent mapping), the contents are returned unchanged.
+func replaceAt(root interface{}, path []string, val interface{}, mode KeyMatchMode) interface{} {
+	if len(path) == 0 {
+		return val
+	}
+	parent, ok := lookup(root, path[:len(path)-1], mode)
+	if !ok {
+		return root
+	}
+	m, ok := parent.(map[interface{}]interface{})
+	if !ok {
+		return root
+	}
+	if key, ok := mode.resolve(m, path[len(path)-1]); ok {
+		m[key] = val
+	}
+	return root
+}
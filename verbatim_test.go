@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const _script = `local name = "$NAME"
+if ${count} > 1 then
+  print(name .. "$$")
+end
+`
+
+func TestVerbatim(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "NAME" {
+			return "expanded", true
+		}
+		return "", false
+	}
+	base := `
+script: |
+  local name = "$NAME"
+  if ${count} > 1 then
+    print(name .. "$$")
+  end
+greeting: hello $NAME
+`
+
+	t.Run("literal block survives expansion", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(base)),
+			Expand(lookup),
+			Verbatim("script"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, _script, p.Get("script").Value(), "verbatim value should be untouched")
+		assert.Equal(t, "hello expanded", p.Get("greeting").Value(), "other values should be expanded")
+	})
+
+	t.Run("without verbatim", func(t *testing.T) {
+		_, err := NewYAML(Source(strings.NewReader(base)), Expand(lookup))
+		require.Error(t, err, "expected expansion of script to fail")
+	})
+
+	t.Run("highest-priority source wins", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("script: $NAME")),
+			Source(strings.NewReader(base)),
+			Expand(lookup),
+			Verbatim("script"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, _script, p.Get("script").Value(), "unexpected verbatim value")
+	})
+
+	t.Run("raw source wins", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(base)),
+			RawSource(strings.NewReader("script: cost is $$5")),
+			Expand(lookup),
+			Verbatim("script"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "cost is $$5", p.Get("script").Value(), "unexpected verbatim value")
+	})
+
+	t.Run("nested path", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("tls: {pem: \"-----BEGIN $X-----\"}")),
+			Expand(lookup),
+			Verbatim("tls.pem"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, "-----BEGIN $X-----", p.Get("tls.pem").Value(), "unexpected verbatim value")
+	})
+
+	t.Run("removed by higher-priority source", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("tls: {pem: $X}")),
+			Source(strings.NewReader("tls: ~")),
+			Verbatim("tls.pem"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.False(t, p.Get("tls.pem").HasValue(), "expected verbatim path to be removed")
+	})
+
+	t.Run("non-string values merge normally", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader("script: {foo: bar}")),
+			Source(strings.NewReader("script: {baz: $NAME}")),
+			Expand(lookup),
+			Verbatim("script"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		assert.Equal(t, map[interface{}]interface{}{
+			"foo": "bar",
+			"baz": "expanded",
+		}, p.Get("script").Value(), "unexpected merged value")
+	})
+
+	t.Run("preserved by WithDefault", func(t *testing.T) {
+		p, err := NewYAML(
+			Source(strings.NewReader(base)),
+			Expand(lookup),
+			Verbatim("script"),
+		)
+		require.NoError(t, err, "couldn't construct provider")
+		v, err := p.Get(Root).WithDefault(map[string]string{"other": "default"})
+		require.NoError(t, err, "couldn't set default")
+		assert.Equal(t, _script, v.Get("script").Value(), "verbatim value should survive WithDefault")
+		assert.Equal(t, "default", v.Get("other").Value(), "unexpected default")
+	})
+}